@@ -0,0 +1,251 @@
+// Package archive reads and writes project export archives: a zip file holding a manifest and
+// one NDJSON file per kind of record. Archives are versioned so an instance can refuse exports
+// written by a newer, incompatible release.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// Format identifies LibPulse project exports in the manifest
+const Format = "libpulse-project-export"
+
+// SubjectFormat identifies data subject access bundles, which hold one end user's records
+const SubjectFormat = "libpulse-subject-export"
+
+// Version is the archive layout this release writes and the newest one it reads
+const Version = 1
+
+// Files of an archive. Every file except the manifest holds one JSON record per line.
+const (
+	FileManifest = "manifest.json"
+	FileProject  = "project.ndjson"
+	FileMembers  = "members.ndjson"
+	FileKeys     = "keys.ndjson"
+	FileConsent  = "consent.ndjson"
+	FileEvents   = "events.ndjson"
+	// FileConsentHistory is only written to subject bundles
+	FileConsentHistory = "consent_history.ndjson"
+)
+
+// Manifest describes an archive
+type Manifest struct {
+	Format          string    `json:"format"`
+	Version         int       `json:"version"`
+	ExportedAt      time.Time `json:"exported_at"`
+	SourceProjectID string    `json:"source_project_id"`
+	// SubjectUserIDH is the end user a subject bundle belongs to
+	SubjectUserIDH string         `json:"subject_user_id_h,omitempty"`
+	Counts         map[string]int `json:"counts"`
+}
+
+// ProjectRecord holds the project settings
+type ProjectRecord struct {
+	Name          string    `json:"name"`
+	RetentionDays *int      `json:"retention_days,omitempty"`
+	SignedOnly    *bool     `json:"signed_only,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// MemberRecord is a project member. The email lets another instance, where user ids differ,
+// find the same person.
+type MemberRecord struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// KeyRecord is project key metadata. Secrets (even hashed) are never exported.
+type KeyRecord struct {
+	ID             string     `json:"id"`
+	Label          string     `json:"label"`
+	Env            string     `json:"env"`
+	SignedOnly     bool       `json:"signed_only"`
+	Scopes         []string   `json:"scopes"`
+	PublicKey      string     `json:"public_key"`
+	Disabled       bool       `json:"disabled"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	AllowedCIDRs   []string   `json:"allowed_cidrs"`
+	AllowedOrigins []string   `json:"allowed_origins"`
+}
+
+// NewKeyRecord copies the exportable fields of a key
+func NewKeyRecord(key supabase.ProjectKey) KeyRecord {
+	return KeyRecord{
+		ID:             key.ID,
+		Label:          key.Label,
+		Env:            key.Env,
+		SignedOnly:     key.SignedOnly,
+		Scopes:         key.Scopes,
+		PublicKey:      key.PublicKey,
+		Disabled:       key.Disabled,
+		CreatedAt:      key.CreatedAt,
+		ExpiresAt:      key.ExpiresAt,
+		AllowedCIDRs:   key.AllowedCIDRs,
+		AllowedOrigins: key.AllowedOrigins,
+	}
+}
+
+// ConsentRecord is the consent state of one end user
+type ConsentRecord struct {
+	UserIDH   string    `json:"user_id_h"`
+	State     string    `json:"state"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Writer streams an archive. Files are written one after another; the manifest is written by
+// Close once all record counts are known.
+type Writer struct {
+	zw       *zip.Writer
+	enc      *json.Encoder
+	current  string
+	manifest Manifest
+}
+
+// NewWriter starts an archive of the given project on w
+func NewWriter(w io.Writer, sourceProjectID string, exportedAt time.Time) *Writer {
+	return newWriter(w, Manifest{Format: Format, SourceProjectID: sourceProjectID, ExportedAt: exportedAt})
+}
+
+// NewSubjectWriter starts a bundle of one end user's records in the given project on w
+func NewSubjectWriter(w io.Writer, projectID, userIDH string, exportedAt time.Time) *Writer {
+	return newWriter(w, Manifest{Format: SubjectFormat, SourceProjectID: projectID, SubjectUserIDH: userIDH, ExportedAt: exportedAt})
+}
+
+func newWriter(w io.Writer, manifest Manifest) *Writer {
+	manifest.Version = Version
+	manifest.ExportedAt = manifest.ExportedAt.UTC()
+	manifest.Counts = map[string]int{}
+	return &Writer{zw: zip.NewWriter(w), manifest: manifest}
+}
+
+// Create starts the named NDJSON file; records written afterwards go into it
+func (w *Writer) Create(name string) error {
+	f, err := w.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	w.enc = json.NewEncoder(f)
+	w.current = name
+	w.manifest.Counts[name] = 0
+	return nil
+}
+
+// Write appends one record to the current file
+func (w *Writer) Write(record interface{}) error {
+	if w.enc == nil {
+		return errors.New("archive: no file created")
+	}
+	if err := w.enc.Encode(record); err != nil {
+		return err
+	}
+	w.manifest.Counts[w.current]++
+	return nil
+}
+
+// Close writes the manifest and finishes the archive
+func (w *Writer) Close() error {
+	f, err := w.zw.Create(FileManifest)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(w.manifest); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// RecordError reports a record of an archive file that could not be decoded
+type RecordError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("%s record %d: %s", e.File, e.Line, e.Err.Error())
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads an archive
+type Reader struct {
+	Manifest Manifest
+	files    map[string]*zip.File
+}
+
+// NewReader opens an archive and checks its manifest
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a zip archive: %w", err)
+	}
+
+	ar := &Reader{files: map[string]*zip.File{}}
+	for _, f := range zr.File {
+		ar.files[f.Name] = f
+	}
+
+	mf, ok := ar.files[FileManifest]
+	if !ok {
+		return nil, errors.New("archive has no " + FileManifest)
+	}
+	rc, err := mf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(&ar.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileManifest, err)
+	}
+
+	if ar.Manifest.Format != Format {
+		return nil, fmt.Errorf("unknown archive format %q", ar.Manifest.Format)
+	}
+	if ar.Manifest.Version < 1 || ar.Manifest.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d (this release reads up to %d)", ar.Manifest.Version, Version)
+	}
+
+	return ar, nil
+}
+
+// ReadRecords decodes every record of the named file in order and passes it to fn. A file the
+// archive does not contain has no records.
+func ReadRecords[T any](r *Reader, name string, fn func(T) error) error {
+	f, ok := r.files[name]
+	if !ok {
+		return nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	dec := json.NewDecoder(rc)
+	for line := 1; ; line++ {
+		var record T
+		if err := dec.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return &RecordError{File: name, Line: line, Err: err}
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// NormalizeCIDRs validates an allowlist of CIDRs or bare IPs and returns it in canonical CIDR form.
+// Bare IPs are treated as single-host ranges.
+func NormalizeCIDRs(cidrs []string) ([]string, error) {
+	normalized := make([]string, 0, len(cidrs))
+	for _, raw := range cidrs {
+		entry := strings.TrimSpace(raw)
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid CIDR %q", raw)
+			}
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", raw)
+		}
+		normalized = append(normalized, network.String())
+	}
+	return normalized, nil
+}
+
+// NormalizeOrigins validates an allowlist of origins (scheme://host[:port]) and lowercases them.
+func NormalizeOrigins(origins []string) ([]string, error) {
+	normalized := make([]string, 0, len(origins))
+	for _, raw := range origins {
+		origin := strings.ToLower(strings.TrimRight(strings.TrimSpace(raw), "/"))
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.User != nil {
+			return nil, fmt.Errorf("invalid origin %q", raw)
+		}
+		normalized = append(normalized, u.Scheme+"://"+u.Host)
+	}
+	return normalized, nil
+}
+
+// ipAllowed reports whether ip falls within one of cidrs. An empty allowlist allows every IP.
+func ipAllowed(ip string, cidrs []string) bool {
+	if len(cidrs) == 0 {
+		return true
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// originAllowed reports whether origin is one of origins. An empty allowlist allows every request;
+// a non-empty one requires a matching Origin header.
+func originAllowed(origin string, origins []string) bool {
+	if len(origins) == 0 {
+		return true
+	}
+
+	origin = strings.ToLower(strings.TrimRight(origin, "/"))
+	for _, allowed := range origins {
+		if origin != "" && origin == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"testing"
+)
+
+func TestNormalizeCIDRs(t *testing.T) {
+	got, err := NormalizeCIDRs([]string{"10.0.0.0/8", "192.168.1.7", "2001:db8::/32", " 172.16.5.4/16 "})
+	if err != nil {
+		t.Fatalf("NormalizeCIDRs error: %v", err)
+	}
+
+	want := []string{"10.0.0.0/8", "192.168.1.7/32", "2001:db8::/32", "172.16.0.0/16"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("NormalizeCIDRs[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if _, err := NormalizeCIDRs([]string{"10.0.0.0/33"}); err == nil {
+		t.Errorf("NormalizeCIDRs accepted an invalid prefix length")
+	}
+	if _, err := NormalizeCIDRs([]string{"runner.example.com"}); err == nil {
+		t.Errorf("NormalizeCIDRs accepted a hostname")
+	}
+}
+
+func TestNormalizeOrigins(t *testing.T) {
+	got, err := NormalizeOrigins([]string{"https://App.Example.com/", "http://localhost:3000"})
+	if err != nil {
+		t.Fatalf("NormalizeOrigins error: %v", err)
+	}
+	if got[0] != "https://app.example.com" || got[1] != "http://localhost:3000" {
+		t.Errorf("NormalizeOrigins = %v", got)
+	}
+
+	for _, bad := range []string{"app.example.com", "https://app.example.com/path", "ftp://example.com", "https://"} {
+		if _, err := NormalizeOrigins([]string{bad}); err == nil {
+			t.Errorf("NormalizeOrigins(%q) = nil error, want error", bad)
+		}
+	}
+}
+
+func TestIPAllowed(t *testing.T) {
+	cidrs := []string{"10.0.0.0/8", "2001:db8::/32"}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"11.1.2.3", false},
+		{"2001:db8::1", true},
+		{"not-an-ip", false},
+	}
+
+	for _, tt := range tests {
+		if got := ipAllowed(tt.ip, cidrs); got != tt.want {
+			t.Errorf("ipAllowed(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if !ipAllowed("1.2.3.4", nil) {
+		t.Errorf("ipAllowed with an empty allowlist should allow every IP")
+	}
+}
+
+func TestOriginAllowed(t *testing.T) {
+	origins := []string{"https://app.example.com"}
+
+	if !originAllowed("https://APP.example.com", origins) {
+		t.Errorf("originAllowed should match case-insensitively")
+	}
+	if originAllowed("https://evil.example.com", origins) {
+		t.Errorf("originAllowed accepted an unlisted origin")
+	}
+	if originAllowed("", origins) {
+		t.Errorf("originAllowed accepted a missing Origin with a non-empty allowlist")
+	}
+	if !originAllowed("", nil) {
+		t.Errorf("originAllowed with an empty allowlist should allow every request")
+	}
+}
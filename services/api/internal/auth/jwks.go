@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// minJWKSRefreshInterval bounds how often an unknown kid can trigger a refetch
+const minJWKSRefreshInterval = time.Minute
+
+// ErrUnknownSigningKey is returned when a token references a kid that is not in the JWKS
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// jwk is a single JSON Web Key as published by Supabase Auth
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// signingKey is a parsed public key and the algorithm it is restricted to, if any
+type signingKey struct {
+	key interface{}
+	alg string
+}
+
+// JWKS caches the asymmetric keys Supabase Auth signs session JWTs with.
+// Keys are refetched when a token references an unknown kid and periodically by Run.
+// Fetches run outside mu, so lookups of known keys never wait on the network, and concurrent
+// refreshes share a single request.
+type JWKS struct {
+	URL        string
+	httpClient *http.Client
+	fetches    singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]signingKey
+	fetchedAt time.Time
+}
+
+// NewJWKS creates a JWKS that fetches keys from url
+func NewJWKS(url string) *JWKS {
+	return &JWKS{
+		URL:        url,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		keys:       make(map[string]signingKey),
+	}
+}
+
+// Run refreshes the keys immediately and then on every interval until ctx is cancelled.
+// Failed refreshes keep the previously cached keys.
+func (j *JWKS) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Refresh(ctx); err != nil {
+			log.Printf("jwks refresh error: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh refetches the key document and replaces the cached keys
+func (j *JWKS) Refresh(ctx context.Context) error {
+	_, err, _ := j.fetches.Do("jwks", func() (interface{}, error) {
+		keys, err := j.fetchKeys(ctx)
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.fetchedAt = time.Now()
+		if err != nil {
+			return nil, err
+		}
+		j.keys = keys
+		return nil, nil
+	})
+	return err
+}
+
+// Key returns the public key for kid, refetching the key document if kid is unknown.
+// alg is the token's signing algorithm and must match the key's alg when the key declares one.
+func (j *JWKS) Key(ctx context.Context, kid, alg string) (interface{}, error) {
+	key, ok, recent := j.lookup(kid)
+	if !ok {
+		if recent {
+			return nil, ErrUnknownSigningKey
+		}
+		if err := j.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		if key, ok, _ = j.lookup(kid); !ok {
+			return nil, ErrUnknownSigningKey
+		}
+	}
+
+	if key.alg != "" && key.alg != alg {
+		return nil, fmt.Errorf("signing key %s is restricted to %s", kid, key.alg)
+	}
+	return key.key, nil
+}
+
+// lookup returns the cached key for kid and whether the keys were fetched too recently to
+// refetch them for an unknown kid
+func (j *JWKS) lookup(kid string) (signingKey, bool, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key, ok := j.keys[kid]
+	return key, ok, time.Since(j.fetchedAt) < minJWKSRefreshInterval
+}
+
+// fetchKeys downloads and parses the key document, skipping keys it cannot use
+func (j *JWKS) fetchKeys(ctx context.Context) (map[string]signingKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("fetch jwks: status=%d body=%s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]signingKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Kid == "" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		parsed, err := k.publicKey()
+		if err != nil {
+			log.Printf("jwks: skipping key %s: %s", k.Kid, err.Error())
+			continue
+		}
+		keys[k.Kid] = signingKey{key: parsed, alg: k.Alg}
+	}
+
+	return keys, nil
+}
+
+// publicKey converts an RSA or EC JWK into a crypto public key
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent out of range")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("invalid ec coordinates")
+		}
+		point := append([]byte{4}, append(x, y...)...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
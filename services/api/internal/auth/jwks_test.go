@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newJWKSServer serves the public half of key under kid and counts fetches
+func newJWKSServer(t *testing.T, kid string, key *ecdsa.PrivateKey, fetches *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(jwksHandler(t, kid, key, fetches))
+}
+
+// jwksHandler serves the key document of newJWKSServer
+func jwksHandler(t *testing.T, kid string, key *ecdsa.PrivateKey, fetches *int32) http.HandlerFunc {
+	t.Helper()
+	point, err := key.PublicKey.Bytes()
+	if err != nil {
+		t.Fatalf("encode public key: %v", err)
+	}
+	size := (len(point) - 1) / 2
+	doc := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": kid,
+			"kty": "EC",
+			"alg": "ES256",
+			"use": "sig",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+			"y":   base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		}},
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(fetches, 1)
+		json.NewEncoder(w).Encode(doc)
+	}
+}
+
+func signSession(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, &SupabaseClaims{
+		Role: "authenticated",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func serveSession(cfg MiddlewareConfig, bearer string) int {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/me", NewMiddleware(cfg), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestMiddleware_JWKS(t *testing.T) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	var fetches int32
+	srv := newJWKSServer(t, "kid-1", signer, &fetches)
+	defer srv.Close()
+
+	cfg := MiddlewareConfig{JWTSecret: []byte("legacy-secret"), JWKS: NewJWKS(srv.URL)}
+
+	// Unknown kid in an empty cache triggers the first fetch
+	if code := serveSession(cfg, signSession(t, jwt.SigningMethodES256, "kid-1", signer)); code != http.StatusNoContent {
+		t.Fatalf("ES256 status = %d, want %d", code, http.StatusNoContent)
+	}
+	// Cached afterwards
+	serveSession(cfg, signSession(t, jwt.SigningMethodES256, "kid-1", signer))
+	if got := atomic.LoadInt32(&fetches); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+
+	// Wrong signer and unknown kid are rejected without hammering the JWKS endpoint
+	if code := serveSession(cfg, signSession(t, jwt.SigningMethodES256, "kid-1", other)); code != http.StatusUnauthorized {
+		t.Errorf("forged status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := serveSession(cfg, signSession(t, jwt.SigningMethodES256, "kid-2", signer)); code != http.StatusUnauthorized {
+		t.Errorf("unknown kid status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if got := atomic.LoadInt32(&fetches); got != 1 {
+		t.Errorf("fetches after unknown kid = %d, want 1 (rate limited)", got)
+	}
+
+	// HMAC fallback during migration, and only while the secret is configured
+	legacy := signSession(t, jwt.SigningMethodHS256, "", []byte("legacy-secret"))
+	if code := serveSession(cfg, legacy); code != http.StatusNoContent {
+		t.Errorf("HS256 status = %d, want %d", code, http.StatusNoContent)
+	}
+	if code := serveSession(MiddlewareConfig{JWKS: cfg.JWKS}, legacy); code != http.StatusUnauthorized {
+		t.Errorf("HS256 without secret status = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestJWKS_AlgRestriction(t *testing.T) {
+	signer, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var fetches int32
+	srv := newJWKSServer(t, "kid-1", signer, &fetches)
+	defer srv.Close()
+
+	jwks := NewJWKS(srv.URL)
+	if _, err := jwks.Key(t.Context(), "kid-1", "ES256"); err != nil {
+		t.Fatalf("Key(ES256) error: %v", err)
+	}
+	if _, err := jwks.Key(t.Context(), "kid-1", "RS256"); err == nil {
+		t.Error("Key(RS256) accepted a key restricted to ES256")
+	}
+}
+
+func TestJWKS_UnknownKidRefreshIsShared(t *testing.T) {
+	signer, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var fetches int32
+	handler := jwksHandler(t, "kid-1", signer, &fetches)
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fetches) > 0 {
+			started <- struct{}{}
+			<-release
+		}
+		handler(w, r)
+	}))
+	defer srv.Close()
+
+	jwks := NewJWKS(srv.URL)
+	if err := jwks.Refresh(t.Context()); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	jwks.mu.Lock()
+	jwks.fetchedAt = time.Now().Add(-2 * minJWKSRefreshInterval)
+	jwks.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jwks.Key(t.Context(), "kid-unknown", "ES256")
+		}()
+	}
+	<-started
+
+	// A known key is served while the refetch is still waiting on the network
+	done := make(chan error, 1)
+	go func() {
+		_, err := jwks.Key(t.Context(), "kid-1", "ES256")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Key(kid-1) error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Key(kid-1) blocked behind the refetch")
+	}
+
+	close(release)
+	wg.Wait()
+	if got := atomic.LoadInt32(&fetches); got != 2 {
+		t.Errorf("fetches = %d, want 2 (initial load and one shared refetch)", got)
+	}
+}
@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -28,8 +30,45 @@ type ErrorResponse struct {
 	Code  string `json:"code"`
 }
 
-// NewMiddleware will return a Gin middleware to verify Supabase JWT
-func NewMiddleware(jwtSecret []byte) gin.HandlerFunc {
+// MiddlewareConfig holds the verification material of NewMiddleware
+type MiddlewareConfig struct {
+	// JWTSecret verifies legacy HMAC-signed tokens. Optional once every token is asymmetric.
+	JWTSecret []byte
+	// JWKS verifies asymmetric (RS256/ES256) tokens by kid.
+	JWKS *JWKS
+	// Tokens resolves project access tokens.
+	Tokens PATLookup
+	// Issuer and Audience are the expected iss and aud claims. Empty disables the check.
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew tolerated when checking exp, nbf and iat.
+	Leeway time.Duration
+}
+
+// RoleAnon is the role of Supabase tokens issued to signed-out clients
+const RoleAnon = "anon"
+
+// validSigningMethods are the JWT algorithms accepted from Supabase Auth
+var validSigningMethods = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// NewMiddleware will return a Gin middleware to verify Supabase JWT.
+// Asymmetric tokens are verified against the JWKS; HMAC tokens fall back to the shared secret
+// while projects migrate. Bearer tokens carrying the PAT prefix are resolved through Tokens instead.
+// Expired tokens and tokens for another audience get distinct codes so clients know to refresh.
+func NewMiddleware(cfg MiddlewareConfig) gin.HandlerFunc {
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(validSigningMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(cfg.Audience))
+	}
+
 	return func(c *gin.Context) {
 		// Check Authorization Header
 		authHeader := c.GetHeader("Authorization")
@@ -41,30 +80,68 @@ func NewMiddleware(jwtSecret []byte) gin.HandlerFunc {
 
 		tokenStr := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
 
-		token, err := jwt.ParseWithClaims(tokenStr, &SupabaseClaims{}, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
+		// Project access tokens for automation
+		if IsPAT(tokenStr) {
+			if authenticatePAT(c, cfg.Tokens, tokenStr) {
+				c.Next()
 			}
-			return jwtSecret, nil
-		})
+			return
+		}
+
+		token, err := jwt.ParseWithClaims(tokenStr, &SupabaseClaims{}, func(t *jwt.Token) (interface{}, error) {
+			return cfg.verificationKey(c.Request.Context(), t)
+		}, parserOpts...)
 
 		// Check whether the token is valid
 		if err != nil || !token.Valid {
-			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidToken)
+			code := apierrors.ErrInvalidToken
+			switch {
+			case errors.Is(err, jwt.ErrTokenExpired):
+				code = apierrors.ErrTokenExpired
+			case errors.Is(err, jwt.ErrTokenInvalidAudience):
+				code = apierrors.ErrInvalidAudience
+			}
+			apiErr := apierrors.NewAPIError(code)
 			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
 			return
 		}
 
 		// Check the claims structure.
 		claims, ok := token.Claims.(*SupabaseClaims)
-		if !ok {
+		if !ok || claims.Subject == "" {
 			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidToken)
 			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
 			return
 		}
 
+		// Signed-out clients carry anon tokens, which never authenticate a user.
+		if claims.Role == RoleAnon {
+			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidToken).WithMessage("Anonymous tokens are not accepted")
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
 		// Set the claims in context, so handlers can get it.
 		c.Set(ContextKeyClaims, claims)
 		c.Next()
 	}
 }
+
+// verificationKey selects the key a token is verified with based on its signing method
+func (cfg MiddlewareConfig) verificationKey(ctx context.Context, t *jwt.Token) (interface{}, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(cfg.JWTSecret) == 0 {
+			return nil, errors.New("hmac tokens are not accepted")
+		}
+		return cfg.JWTSecret, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		if cfg.JWKS == nil {
+			return nil, errors.New("asymmetric tokens are not accepted")
+		}
+		kid, _ := t.Header["kid"].(string)
+		return cfg.JWKS.Key(ctx, kid, t.Method.Alg())
+	default:
+		return nil, errors.New("unexpected signing method")
+	}
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestMiddleware_ClaimValidation(t *testing.T) {
+	secret := []byte("legacy-secret")
+	cfg := MiddlewareConfig{
+		JWTSecret: secret,
+		Issuer:    "https://ref.supabase.co/auth/v1",
+		Audience:  "authenticated",
+		Leeway:    30 * time.Second,
+	}
+
+	valid := func() *SupabaseClaims {
+		return &SupabaseClaims{
+			Role: "authenticated",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user-1",
+				Issuer:    cfg.Issuer,
+				Audience:  jwt.ClaimStrings{"authenticated"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(*SupabaseClaims)
+		wantCode int
+		wantErr  string
+	}{
+		{"valid", func(*SupabaseClaims) {}, http.StatusNoContent, ""},
+		{"expired within leeway", func(c *SupabaseClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second)) }, http.StatusNoContent, ""},
+		{"expired", func(c *SupabaseClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }, http.StatusUnauthorized, "token_expired"},
+		{"missing exp", func(c *SupabaseClaims) { c.ExpiresAt = nil }, http.StatusUnauthorized, "invalid_token"},
+		{"wrong audience", func(c *SupabaseClaims) { c.Audience = jwt.ClaimStrings{"other"} }, http.StatusUnauthorized, "invalid_audience"},
+		{"wrong issuer", func(c *SupabaseClaims) { c.Issuer = "https://evil.example.com" }, http.StatusUnauthorized, "invalid_token"},
+		{"anon role", func(c *SupabaseClaims) { c.Role = RoleAnon }, http.StatusUnauthorized, "invalid_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(claims)
+			signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+			if err != nil {
+				t.Fatalf("sign token: %v", err)
+			}
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.GET("/api/v1/me", NewMiddleware(cfg), func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+			req.Header.Set("Authorization", "Bearer "+signed)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantErr != "" {
+				var body ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != tt.wantErr {
+					t.Errorf("code = %q, want %q", body.Code, tt.wantErr)
+				}
+			}
+		})
+	}
+}
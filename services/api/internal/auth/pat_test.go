@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+)
+
+// fakeTokenStore resolves a single token by hash
+type fakeTokenStore struct {
+	hash  string
+	token *supabase.ProjectToken
+}
+
+func (s *fakeTokenStore) GetProjectTokenByHash(ctx context.Context, tokenHash string) (*supabase.ProjectToken, error) {
+	if tokenHash != s.hash {
+		return nil, errors.New("project token not found")
+	}
+	return s.token, nil
+}
+
+// newPATRouter returns a router with a key listing route requiring the read scope, a key
+// creation route requiring keys:write and an undeclared /me route, all echoing the claims.
+func newPATRouter(store PATLookup) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	echo := func(c *gin.Context) {
+		claimsAny, ok := c.Get(ContextKeyClaims)
+		if !ok {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		claims := claimsAny.(*SupabaseClaims)
+		c.String(http.StatusOK, claims.Role+":"+claims.Subject)
+	}
+	api := r.Group("/api/v1", NewMiddleware(MiddlewareConfig{JWTSecret: []byte("jwt-secret"), Tokens: store}))
+	api.GET("/me", echo)
+	api.GET("/projects/:id/keys", RequireTokenScope(TokenScopeRead), echo)
+	api.POST("/projects/:id/keys", RequireTokenScope(TokenScopeKeysWrite), echo)
+	return r
+}
+
+func servePAT(r *gin.Engine, method, path, bearer string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_PAT(t *testing.T) {
+	pat, err := crypto.GeneratePAT()
+	if err != nil {
+		t.Fatalf("GeneratePAT: %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	readToken := supabase.ProjectToken{ProjectID: "proj-1", CreatedBy: "user-1", Scopes: []string{TokenScopeRead}}
+
+	tests := []struct {
+		name   string
+		token  supabase.ProjectToken
+		method string
+		path   string
+		bearer string
+		want   int
+	}{
+		{"valid", readToken, http.MethodGet, "/api/v1/projects/proj-1/keys", pat, http.StatusOK},
+		{"other project", readToken, http.MethodGet, "/api/v1/projects/proj-2/keys", pat, http.StatusForbidden},
+		{"missing scope", readToken, http.MethodPost, "/api/v1/projects/proj-1/keys", pat, http.StatusForbidden},
+		{"undeclared route", readToken, http.MethodGet, "/api/v1/me", pat, http.StatusUnauthorized},
+		{"revoked", supabase.ProjectToken{ProjectID: "proj-1", Scopes: []string{TokenScopeRead}, Revoked: true}, http.MethodGet, "/api/v1/projects/proj-1/keys", pat, http.StatusUnauthorized},
+		{"expired", supabase.ProjectToken{ProjectID: "proj-1", Scopes: []string{TokenScopeRead}, ExpiresAt: &past}, http.MethodGet, "/api/v1/projects/proj-1/keys", pat, http.StatusUnauthorized},
+		{"unknown", readToken, http.MethodGet, "/api/v1/projects/proj-1/keys", pat[:len(pat)-1] + "x", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.token
+			store := &fakeTokenStore{hash: crypto.HashSecret(pat), token: &token}
+			w := servePAT(newPATRouter(store), tt.method, tt.path, tt.bearer)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK && w.Body.String() != RolePAT+":user-1" {
+				t.Errorf("claims = %q, want PAT claims for the token creator", w.Body.String())
+			}
+		})
+	}
+}
+
+// TestRequireTokenScope_Session tests that user sessions are not subject to token scopes
+func TestRequireTokenScope_Session(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-1/keys", nil)
+
+	RequireTokenScope(TokenScopeKeysWrite)(c)
+
+	if c.IsAborted() {
+		t.Fatalf("session request aborted with status %d", w.Code)
+	}
+}
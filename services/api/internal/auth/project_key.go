@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	apierrors "github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// This will be used as the key in Gin Context for the authenticated project key
+const ContextKeyProjectKey = "projectKey"
+
+// Headers used by SDKs to authenticate with a project key
+const (
+	HeaderProjectKey    = "X-LibPulse-Key"
+	HeaderProjectSecret = "X-LibPulse-Secret"
+)
+
+// ProjectKeyLookup abstracts project key lookup for the key middleware.
+type ProjectKeyLookup interface {
+	GetProjectKeyByPublicKey(ctx context.Context, publicKey string) (*supabase.ProjectKey, error)
+}
+
+// AuditLogWriter abstracts audit log writes for the key middleware.
+type AuditLogWriter interface {
+	CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error
+}
+
+// NewProjectKeyMiddleware returns a Gin middleware that authenticates SDK requests with a project key.
+// The public key is always required; the secret is required when the key is signed_only,
+// and verified whenever it is supplied. Keys with IP or Origin allowlists reject requests from
+// elsewhere and record the rejection in audit_logs. The client IP comes from gin's ClientIP,
+// so X-Forwarded-For is only honored from the router's trusted proxies.
+func NewProjectKeyMiddleware(store ProjectKeyLookup, audit AuditLogWriter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		publicKey := strings.TrimSpace(c.GetHeader(HeaderProjectKey))
+		if publicKey == "" {
+			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidProjectKey)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// Reject malformed keys and typos offline, before any database lookup.
+		if err := crypto.ValidatePublicKey(publicKey); err != nil {
+			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidProjectKey)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		key, err := store.GetProjectKeyByPublicKey(c.Request.Context(), publicKey)
+		if err != nil || key == nil {
+			if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+				log.Printf("GetProjectKeyByPublicKey error: %s", err.Error())
+			}
+			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidProjectKey)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// Check expiry before the disabled flag: the expiry sweeper also disables expired keys,
+		// and SDKs should still see the distinct expired code afterwards.
+		if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+			apiErr := apierrors.NewAPIError(apierrors.ErrProjectKeyExpired)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		if key.Disabled {
+			apiErr := apierrors.NewAPIError(apierrors.ErrProjectKeyDisabled)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// Check the secret when required or supplied.
+		secret := strings.TrimSpace(c.GetHeader(HeaderProjectSecret))
+		if secret == "" && key.SignedOnly {
+			apiErr := apierrors.NewAPIError(apierrors.ErrSignatureRequired)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if secret != "" && (crypto.ValidateSecret(secret) != nil || !crypto.VerifySecret(secret, key.SecretEnc)) {
+			apiErr := apierrors.NewAPIError(apierrors.ErrSignatureRequired)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// Enforce allowlists
+		if !ipAllowed(c.ClientIP(), key.AllowedCIDRs) {
+			rejectByAllowlist(c, audit, key, secret, apierrors.ErrIPNotAllowed, "project_key.ip_rejected")
+			return
+		}
+		if !originAllowed(c.GetHeader("Origin"), key.AllowedOrigins) {
+			rejectByAllowlist(c, audit, key, secret, apierrors.ErrOriginNotAllowed, "project_key.origin_rejected")
+			return
+		}
+
+		// Set the key in context, so scope checks and handlers can get it.
+		c.Set(ContextKeyProjectKey, key)
+		c.Next()
+	}
+}
+
+// RequireKeyScope returns a Gin middleware that rejects requests whose project key lacks scope.
+// It must run after NewProjectKeyMiddleware.
+func RequireKeyScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyAny, ok := c.Get(ContextKeyProjectKey)
+		if !ok {
+			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidProjectKey)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		key, ok := keyAny.(*supabase.ProjectKey)
+		if !ok {
+			apiErr := apierrors.NewAPIError(apierrors.ErrInvalidProjectKey)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		if !HasScope(key.Scopes, scope) {
+			apiErr := apierrors.NewAPIError(apierrors.ErrInsufficientScope).
+				WithMessage("Project key is missing required scope: " + scope)
+			c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rejectByAllowlist aborts the request and records the rejection in audit_logs with the hashed client IP
+func rejectByAllowlist(c *gin.Context, audit AuditLogWriter, key *supabase.ProjectKey, secret string, code apierrors.ErrorCode, action string) {
+	apiErr := apierrors.NewAPIError(code)
+
+	authMode := supabase.AuthModePKOnly
+	if secret != "" {
+		authMode = supabase.AuthModeHMAC
+	}
+
+	keyID := key.ID
+	ipHash := crypto.HashIP(c.ClientIP())
+	entry := supabase.AuditLog{
+		ProjectID:  key.ProjectID,
+		ActorType:  supabase.ActorTypeSystem,
+		ActorID:    &keyID,
+		Action:     action,
+		Success:    false,
+		StatusCode: apiErr.StatusCode(),
+		AuthMode:   authMode,
+		Details: map[string]interface{}{
+			"key_id": key.ID,
+			"origin": c.GetHeader("Origin"),
+			"path":   c.FullPath(),
+		},
+		IPHash: &ipHash,
+	}
+	if userAgent := c.Request.UserAgent(); userAgent != "" {
+		entry.UserAgent = &userAgent
+	}
+	if err := audit.CreateAuditLog(c.Request.Context(), entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+
+	c.AbortWithStatusJSON(apiErr.StatusCode(), apiErr)
+}
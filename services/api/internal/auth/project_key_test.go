@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+)
+
+func init() {
+	crypto.Init("test-pepper-for-auth-tests")
+}
+
+// fakeKeyStore returns a fixed key for any public key
+type fakeKeyStore struct {
+	key *supabase.ProjectKey
+}
+
+func (s *fakeKeyStore) GetProjectKeyByPublicKey(ctx context.Context, publicKey string) (*supabase.ProjectKey, error) {
+	return s.key, nil
+}
+
+// fakeAuditLog records audit entries
+type fakeAuditLog struct {
+	entries []supabase.AuditLog
+}
+
+func (a *fakeAuditLog) CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error {
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+// newKeyRouter returns a router with the key middleware in front of a 204 handler
+func newKeyRouter(key *supabase.ProjectKey, audit *fakeAuditLog, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.SetTrustedProxies(trustedProxies)
+	r.POST("/ingest/v1/events", NewProjectKeyMiddleware(&fakeKeyStore{key: key}, audit), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+func newKeyRequest(publicKey, remoteAddr string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/ingest/v1/events", nil)
+	req.Header.Set(HeaderProjectKey, publicKey)
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+func TestProjectKeyMiddleware_CIDRAllowlist(t *testing.T) {
+	publicKey, _ := crypto.GeneratePublicKey(crypto.EnvProd)
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-1", PublicKey: publicKey, AllowedCIDRs: []string{"10.0.0.0/8"}}
+
+	// Allowed runner range
+	audit := &fakeAuditLog{}
+	w := httptest.NewRecorder()
+	newKeyRouter(key, audit, nil).ServeHTTP(w, newKeyRequest(publicKey, "10.1.2.3:4567"))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("allowed IP status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	// Outside the range: rejected and audited with the hashed IP
+	w = httptest.NewRecorder()
+	newKeyRouter(key, audit, nil).ServeHTTP(w, newKeyRequest(publicKey, "203.0.113.9:4567"))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("disallowed IP status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if len(audit.entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	if entry.IPHash == nil || *entry.IPHash != crypto.HashIP("203.0.113.9") {
+		t.Errorf("audit ip_hash = %v, want hash of client IP", entry.IPHash)
+	}
+	if entry.Success || entry.ProjectID != "proj-1" {
+		t.Errorf("audit entry = %+v, want failed entry for proj-1", entry)
+	}
+}
+
+func TestProjectKeyMiddleware_TrustedProxy(t *testing.T) {
+	publicKey, _ := crypto.GeneratePublicKey(crypto.EnvProd)
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-1", PublicKey: publicKey, AllowedCIDRs: []string{"10.0.0.0/8"}}
+
+	// X-Forwarded-For is ignored from untrusted peers
+	req := newKeyRequest(publicKey, "192.0.2.1:4567")
+	req.Header.Set("X-Forwarded-For", "10.1.2.3")
+	w := httptest.NewRecorder()
+	newKeyRouter(key, &fakeAuditLog{}, nil).ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("untrusted proxy status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	// ...and honored from trusted ones
+	req = newKeyRequest(publicKey, "192.0.2.1:4567")
+	req.Header.Set("X-Forwarded-For", "10.1.2.3")
+	w = httptest.NewRecorder()
+	newKeyRouter(key, &fakeAuditLog{}, []string{"192.0.2.0/24"}).ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("trusted proxy status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestProjectKeyMiddleware_OriginAllowlist(t *testing.T) {
+	publicKey, _ := crypto.GeneratePublicKey(crypto.EnvProd)
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-1", PublicKey: publicKey, AllowedOrigins: []string{"https://app.example.com"}}
+
+	req := newKeyRequest(publicKey, "192.0.2.1:4567")
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	newKeyRouter(key, &fakeAuditLog{}, nil).ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("allowed origin status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	audit := &fakeAuditLog{}
+	req = newKeyRequest(publicKey, "192.0.2.1:4567")
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	newKeyRouter(key, audit, nil).ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("disallowed origin status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Action != "project_key.origin_rejected" {
+		t.Errorf("audit entries = %+v, want one origin rejection", audit.entries)
+	}
+}
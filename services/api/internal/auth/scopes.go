@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Project key scopes
+const (
+	ScopeIngest       = "ingest"
+	ScopeConsentWrite = "consent:write"
+	ScopeReadStats    = "read:stats"
+)
+
+// Project access token scopes
+const (
+	TokenScopeRead         = "read"
+	TokenScopeKeysWrite    = "keys:write"
+	TokenScopeProjectWrite = "project:write"
+)
+
+// DefaultKeyScopes are granted when a key is created without explicit scopes.
+var DefaultKeyScopes = []string{ScopeIngest}
+
+// validKeyScopes is the closed set of scopes a project key may hold.
+// Keep in sync with the project_keys_scopes_check constraint.
+var validKeyScopes = map[string]bool{
+	ScopeIngest:       true,
+	ScopeConsentWrite: true,
+	ScopeReadStats:    true,
+}
+
+// validTokenScopes is the closed set of scopes a project access token may hold.
+var validTokenScopes = map[string]bool{
+	TokenScopeRead:         true,
+	TokenScopeKeysWrite:    true,
+	TokenScopeProjectWrite: true,
+}
+
+// NormalizeKeyScopes validates the requested scopes and removes duplicates, keeping order.
+// An empty list resolves to DefaultKeyScopes.
+func NormalizeKeyScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return append([]string(nil), DefaultKeyScopes...), nil
+	}
+	return normalizeScopes(scopes, validKeyScopes)
+}
+
+// NormalizeTokenScopes validates the requested token scopes and removes duplicates, keeping order.
+// Tokens must be granted at least one scope explicitly.
+func NormalizeTokenScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	return normalizeScopes(scopes, validTokenScopes)
+}
+
+func normalizeScopes(scopes []string, valid map[string]bool) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !valid[scope] {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		normalized = append(normalized, scope)
+	}
+
+	return normalized, nil
+}
+
+// HasScope reports whether scope is present in scopes.
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
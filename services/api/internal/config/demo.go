@@ -0,0 +1,38 @@
+// internal/config/demo.go
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GetDemoOwnerUserID returns the user who owns the public demo project. Demo seeding is
+// disabled when it is empty
+func GetDemoOwnerUserID() string {
+	return strings.TrimSpace(os.Getenv("LIBPULSE_DEMO_OWNER_USER_ID"))
+}
+
+// GetDemoProjectName returns the name given to the demo project when it is created
+func GetDemoProjectName() string {
+	if v := strings.TrimSpace(os.Getenv("LIBPULSE_DEMO_PROJECT_NAME")); v != "" {
+		return v
+	}
+	return "LibPulse Demo"
+}
+
+// GetDemoDays returns how many days of history the demo project is seeded with
+func GetDemoDays() int {
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_DEMO_DAYS")); err == nil && v > 0 {
+		return v
+	}
+	return 14
+}
+
+// GetDemoSessionsPerDay returns the average number of synthetic CLI sessions per demo day
+func GetDemoSessionsPerDay() int {
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_DEMO_SESSIONS_PER_DAY")); err == nil && v > 0 {
+		return v
+	}
+	return 60
+}
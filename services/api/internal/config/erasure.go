@@ -0,0 +1,15 @@
+// internal/config/erasure.go
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// GetErasureInterval returns how often queued data subject erasure requests are processed
+func GetErasureInterval() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_ERASURE_INTERVAL")); err == nil && v > 0 {
+		return v
+	}
+	return time.Minute
+}
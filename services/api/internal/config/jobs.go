@@ -0,0 +1,38 @@
+// internal/config/jobs.go
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// jobEnvName turns a job name such as "key-expiry-sweep" into its env var suffix KEY_EXPIRY_SWEEP
+func jobEnvName(job string) string {
+	return strings.ToUpper(strings.ReplaceAll(job, "-", "_"))
+}
+
+// GetJobSchedule returns the schedule spec of a background job: LIBPULSE_JOB_SCHEDULE_<JOB> when
+// set (a cron expression or "@every <duration>"), otherwise every interval
+func GetJobSchedule(job string, interval time.Duration) string {
+	if v := strings.TrimSpace(os.Getenv("LIBPULSE_JOB_SCHEDULE_" + jobEnvName(job))); v != "" {
+		return v
+	}
+	return "@every " + interval.String()
+}
+
+// GetJobTimeout returns how long a single run of a background job may take
+func GetJobTimeout(job string, fallback time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_JOB_TIMEOUT_" + jobEnvName(job))); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// GetJobJitter returns the longest random delay added before each background job run
+func GetJobJitter() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_JOB_JITTER")); err == nil && v >= 0 {
+		return v
+	}
+	return 30 * time.Second
+}
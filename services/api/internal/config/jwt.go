@@ -0,0 +1,48 @@
+// internal/config/jwt.go
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// GetJWKSURL returns where Supabase Auth publishes its JWT signing keys
+func GetJWKSURL(authURL string) string {
+	if v := os.Getenv("SUPABASE_JWKS_URL"); v != "" {
+		return v
+	}
+	return strings.TrimRight(authURL, "/") + "/.well-known/jwks.json"
+}
+
+// GetJWKSRefreshInterval returns how often the JWT signing keys are refetched in the background
+func GetJWKSRefreshInterval() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_JWKS_REFRESH_INTERVAL")); err == nil && v > 0 {
+		return v
+	}
+	return 10 * time.Minute
+}
+
+// GetJWTIssuer returns the expected iss claim of session tokens, the Supabase Auth URL by default
+func GetJWTIssuer(authURL string) string {
+	if v := os.Getenv("SUPABASE_JWT_ISSUER"); v != "" {
+		return v
+	}
+	return strings.TrimRight(authURL, "/")
+}
+
+// GetJWTAudience returns the expected aud claim of session tokens
+func GetJWTAudience() string {
+	if v := os.Getenv("SUPABASE_JWT_AUDIENCE"); v != "" {
+		return v
+	}
+	return "authenticated"
+}
+
+// GetJWTLeeway returns the clock skew tolerated when validating token timestamps
+func GetJWTLeeway() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_JWT_LEEWAY")); err == nil && v >= 0 {
+		return v
+	}
+	return 30 * time.Second
+}
@@ -0,0 +1,25 @@
+// internal/config/keys.go
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetKeyExpiryWarningWindow returns how long before expiry a project key is flagged in the key list
+func GetKeyExpiryWarningWindow() time.Duration {
+	days := 7
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_KEY_EXPIRY_WARNING_DAYS")); err == nil && v > 0 {
+		days = v
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetKeyExpirySweepInterval returns how often expired project keys are swept and disabled
+func GetKeyExpirySweepInterval() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_KEY_EXPIRY_SWEEP_INTERVAL")); err == nil && v > 0 {
+		return v
+	}
+	return 5 * time.Minute
+}
@@ -0,0 +1,54 @@
+// internal/config/mail.go
+package config
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/mail"
+)
+
+// GetMailSender returns an SMTP sender when SMTP_HOST is set, otherwise nil: email is disabled
+func GetMailSender() mail.Sender {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "no-reply@libpulse.dev"
+	}
+
+	return &mail.SMTPSender{
+		Addr:     net.JoinHostPort(host, port),
+		From:     from,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+}
+
+// GetInvitationAcceptURL returns the web app page invitees are sent to
+func GetInvitationAcceptURL() string {
+	appURL := os.Getenv("LIBPULSE_APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+	return strings.TrimRight(appURL, "/") + "/invitations/accept"
+}
+
+// GetInvitationTTL returns how long a project invitation can be accepted
+func GetInvitationTTL() time.Duration {
+	days := 7
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_INVITATION_TTL_DAYS")); err == nil && v > 0 {
+		days = v
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,13 @@
+// internal/config/metrics.go
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetExposeMetrics reports whether expvar counters are served at /debug/vars
+func GetExposeMetrics() bool {
+	v, _ := strconv.ParseBool(os.Getenv("LIBPULSE_EXPOSE_METRICS"))
+	return v
+}
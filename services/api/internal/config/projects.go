@@ -0,0 +1,34 @@
+// internal/config/projects.go
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetProjectDeletionGracePeriod returns how long a deleted project can be restored before it is purged
+func GetProjectDeletionGracePeriod() time.Duration {
+	days := 30
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_PROJECT_DELETION_GRACE_DAYS")); err == nil && v > 0 {
+		days = v
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetProjectPurgeInterval returns how often projects past their grace period are purged
+func GetProjectPurgeInterval() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_PROJECT_PURGE_INTERVAL")); err == nil && v > 0 {
+		return v
+	}
+	return time.Hour
+}
+
+// GetImportMaxBytes returns the largest project export archive that can be imported
+func GetImportMaxBytes() int64 {
+	mb := 1024
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_IMPORT_MAX_MB")); err == nil && v > 0 {
+		mb = v
+	}
+	return int64(mb) << 20
+}
@@ -0,0 +1,23 @@
+// internal/config/proxies.go
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// GetTrustedProxies returns the proxies (IPs or CIDRs) whose X-Forwarded-For header is trusted
+// when resolving client IPs. Nothing is trusted by default, so the peer address is used as-is.
+func GetTrustedProxies() []string {
+	proxiesStr := os.Getenv("LIBPULSE_TRUSTED_PROXIES")
+	if proxiesStr == "" {
+		return nil
+	}
+
+	proxies := strings.Split(proxiesStr, ",")
+	for i, proxy := range proxies {
+		proxies[i] = strings.TrimSpace(proxy)
+	}
+
+	return proxies
+}
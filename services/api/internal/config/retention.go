@@ -0,0 +1,40 @@
+// internal/config/retention.go
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetRetentionDefaultDays returns how long events are kept for projects without retention_days
+func GetRetentionDefaultDays() int {
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_RETENTION_DEFAULT_DAYS")); err == nil && v > 0 {
+		return v
+	}
+	return 90
+}
+
+// GetRetentionMaxDays returns the longest retention any project gets, whatever its setting
+func GetRetentionMaxDays() int {
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_RETENTION_MAX_DAYS")); err == nil && v > 0 {
+		return v
+	}
+	return 365
+}
+
+// GetRetentionBatchSize returns how many events one retention or project purge delete removes at most
+func GetRetentionBatchSize() int {
+	if v, err := strconv.Atoi(os.Getenv("LIBPULSE_RETENTION_BATCH_SIZE")); err == nil && v > 0 {
+		return v
+	}
+	return 5000
+}
+
+// GetRetentionInterval returns how often retention is enforced
+func GetRetentionInterval() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_RETENTION_INTERVAL")); err == nil && v > 0 {
+		return v
+	}
+	return time.Hour
+}
@@ -0,0 +1,16 @@
+// internal/config/secret_scanning.go
+package config
+
+import (
+	"os"
+
+	"github.com/libpulse/platform/services/api/internal/secretscan"
+)
+
+// GetSecretScanningKeysURL returns where leak report signing keys are fetched from
+func GetSecretScanningKeysURL() string {
+	if v := os.Getenv("SECRET_SCANNING_KEYS_URL"); v != "" {
+		return v
+	}
+	return secretscan.DefaultKeysURL
+}
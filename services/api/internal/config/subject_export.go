@@ -0,0 +1,31 @@
+// internal/config/subject_export.go
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// GetSubjectExportInterval returns how often queued data subject access exports are built
+func GetSubjectExportInterval() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_SUBJECT_EXPORT_INTERVAL")); err == nil && v > 0 {
+		return v
+	}
+	return time.Minute
+}
+
+// GetSubjectExportTTL returns how long a finished subject access bundle is kept before deletion
+func GetSubjectExportTTL() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_SUBJECT_EXPORT_TTL")); err == nil && v > 0 {
+		return v
+	}
+	return 72 * time.Hour
+}
+
+// GetSubjectExportURLTTL returns how long a signed download URL for a subject access bundle is valid
+func GetSubjectExportURLTTL() time.Duration {
+	if v, err := time.ParseDuration(os.Getenv("LIBPULSE_SUBJECT_EXPORT_URL_TTL")); err == nil && v > 0 {
+		return v
+	}
+	return 15 * time.Minute
+}
@@ -0,0 +1,258 @@
+// Package demo generates synthetic telemetry for the public demo project. The data imitates a
+// developer CLI instrumented with the LibPulse SDK: commands are recorded as ops, users run them
+// in sessions, and every command is one trace made of a user action, a perf sample and, when it
+// fails, an error.
+package demo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// command describes one CLI command of the demo tool
+type command struct {
+	op      string
+	argsSig string
+	// weight is the relative frequency of the command within a session
+	weight float64
+	// medianMS and spread shape the log-normal duration distribution
+	medianMS float64
+	spread   float64
+	// failRate is the baseline share of failing runs
+	failRate float64
+	failures []failure
+}
+
+type failure struct {
+	code     string
+	message  string
+	severity string
+	frame    string
+}
+
+var commands = []command{
+	{op: "init", argsSig: "--template:str", weight: 1, medianMS: 350, spread: 0.4, failRate: 0.01,
+		failures: []failure{{"E_TEMPLATE_NOT_FOUND", "template \"%s\" not found", "error", "scaffold.resolveTemplate"}}},
+	{op: "login", argsSig: "--sso:bool", weight: 1.5, medianMS: 900, spread: 0.6, failRate: 0.03,
+		failures: []failure{{"E_AUTH_TIMEOUT", "browser login timed out after 120s", "warn", "auth.waitForCallback"}}},
+	{op: "build", argsSig: "--watch:bool,--target:str", weight: 6, medianMS: 4200, spread: 0.5, failRate: 0.04,
+		failures: []failure{
+			{"E_COMPILE", "compilation failed: 3 errors in %s", "error", "compiler.emit"},
+			{"E_OOM", "JavaScript heap out of memory", "fatal", "worker.run"},
+		}},
+	{op: "test", argsSig: "--filter:str,--coverage:bool", weight: 5, medianMS: 7800, spread: 0.7, failRate: 0.08,
+		failures: []failure{{"E_TEST_FAILED", "%s failed", "warn", "runner.report"}}},
+	{op: "lint", argsSig: "--fix:bool", weight: 3, medianMS: 1200, spread: 0.4, failRate: 0.02,
+		failures: []failure{{"E_CONFIG", "invalid rule in .pulserc: %s", "error", "config.load"}}},
+	{op: "deploy", argsSig: "--env:str,--force:bool", weight: 2, medianMS: 21000, spread: 0.45, failRate: 0.03,
+		failures: []failure{
+			{"E_UPLOAD", "upload of %s failed: connection reset", "error", "deploy.upload"},
+			{"E_HEALTHCHECK", "health check did not pass within 60s", "error", "deploy.verify"},
+		}},
+}
+
+// release is a version of the demo tool and the day it shipped, counted back from now.
+// Regressed releases make builds slower and deploys fail more often.
+type release struct {
+	version   string
+	daysAgo   float64
+	regressed bool
+}
+
+// releases must be ordered oldest first
+var releases = []release{
+	{version: "2.3.1", daysAgo: 30},
+	{version: "2.4.0", daysAgo: 9, regressed: true},
+	{version: "2.4.1", daysAgo: 3},
+}
+
+// Options controls how much data is generated
+type Options struct {
+	// Days of history to generate, ending at Now
+	Days int
+	// Users is the number of distinct synthetic users
+	Users int
+	// SessionsPerDay is the average number of CLI sessions per day
+	SessionsPerDay int
+	// Seed makes the output reproducible; runs with the same seed and Now generate the same events
+	Seed uint64
+	Now  time.Time
+	// RunID is mixed into event ids so a new seeding pass never collides with the previous one
+	RunID string
+}
+
+// Generate returns the synthetic events of the demo project, oldest first.
+func Generate(projectID string, opts Options) []supabase.Event {
+	rng := rand.New(rand.NewPCG(opts.Seed, 0x11b9))
+	g := &generator{projectID: projectID, opts: opts, rng: rng}
+
+	users := make([]string, opts.Users)
+	for i := range users {
+		users[i] = hashUser(fmt.Sprintf("demo-user-%d", i))
+	}
+
+	start := opts.Now.Add(-time.Duration(opts.Days) * 24 * time.Hour)
+	sessions := opts.Days * opts.SessionsPerDay
+	for i := 0; i < sessions; i++ {
+		at := start.Add(time.Duration(rng.Int64N(int64(opts.Now.Sub(start)))))
+		// Power users produce most of the traffic
+		user := users[int(math.Floor(math.Pow(rng.Float64(), 2)*float64(len(users))))]
+		g.session(i, user, at)
+	}
+
+	sort.SliceStable(g.events, func(i, j int) bool { return g.events[i].EventTS.Before(g.events[j].EventTS) })
+	return g.events
+}
+
+type generator struct {
+	projectID string
+	opts      Options
+	rng       *rand.Rand
+	events    []supabase.Event
+	seq       int
+}
+
+// session emits the commands one user runs in a row
+func (g *generator) session(n int, user string, at time.Time) {
+	rel := g.releaseAt(at)
+	env := "prod"
+	if g.rng.Float64() < 0.15 {
+		env = "staging"
+	}
+	sessionID := g.id("sess", n)
+
+	for i, runs := 0, 2+g.rng.IntN(7); i < runs && at.Before(g.opts.Now); i++ {
+		cmd := g.pickCommand()
+		duration := g.duration(cmd, rel)
+		failed := g.rng.Float64() < g.failRate(cmd, rel)
+		traceID := g.id("trace", g.seq)
+
+		action := g.event("user_action", cmd.op, at, user, env, rel.version, sessionID, traceID)
+		action.ArgsSig = strPtr(cmd.argsSig)
+		action.ArgsCount = intPtr(g.rng.IntN(3))
+		g.events = append(g.events, action)
+
+		end := at.Add(time.Duration(duration) * time.Millisecond)
+		perf := g.event("perf", cmd.op, end, user, env, rel.version, sessionID, traceID)
+		perf.DurationMS = intPtr(duration)
+		perf.Success = boolPtr(!failed)
+		g.events = append(g.events, perf)
+
+		if failed {
+			f := cmd.failures[g.rng.IntN(len(cmd.failures))]
+			errEvent := g.event("error", cmd.op, end, user, env, rel.version, sessionID, traceID)
+			errEvent.Success = boolPtr(false)
+			errEvent.Severity = strPtr(f.severity)
+			errEvent.Code = strPtr(f.code)
+			errEvent.Message = strPtr(fmt.Sprintf(f.message, g.detail()))
+			errEvent.Stack = strPtr(fmt.Sprintf("Error: %s\n    at %s (pulse/dist/%s.js:%d:%d)\n    at main (pulse/dist/cli.js:42:7)",
+				f.code, f.frame, cmd.op, 10+g.rng.IntN(400), 1+g.rng.IntN(40)))
+			g.events = append(g.events, errEvent)
+		}
+
+		// Think time before the next command
+		at = end.Add(time.Duration(5+g.rng.IntN(180)) * time.Second)
+	}
+}
+
+func (g *generator) event(eventType, op string, at time.Time, user, env, version, sessionID, traceID string) supabase.Event {
+	g.seq++
+	return supabase.Event{
+		ProjectID:   g.projectID,
+		EventID:     g.id("evt", g.seq),
+		Env:         env,
+		EventType:   eventType,
+		EventTS:     at.UTC(),
+		Op:          op,
+		Surface:     strPtr("cli"),
+		Version:     version,
+		UserIDH:     user,
+		SessionID:   strPtr(sessionID),
+		TraceID:     strPtr(traceID),
+		SDKName:     "libpulse-node",
+		SDKVersion:  "0.9.3",
+		SDKLanguage: strPtr("typescript"),
+		SDKRuntime:  strPtr("node-20"),
+		Payload:     json.RawMessage(`{"demo":true}`),
+	}
+}
+
+// releaseAt returns the release a session at t runs. A new release is adopted gradually over
+// two days, so old and new versions overlap for a while.
+func (g *generator) releaseAt(t time.Time) release {
+	daysAgo := g.opts.Now.Sub(t).Hours() / 24
+	current := 0
+	for i, rel := range releases {
+		if daysAgo > rel.daysAgo {
+			break
+		}
+		adoption := (rel.daysAgo - daysAgo) / 2
+		if i == 0 || g.rng.Float64() < adoption {
+			current = i
+		}
+	}
+	return releases[current]
+}
+
+func (g *generator) pickCommand() command {
+	total := 0.0
+	for _, c := range commands {
+		total += c.weight
+	}
+	r := g.rng.Float64() * total
+	for _, c := range commands {
+		if r < c.weight {
+			return c
+		}
+		r -= c.weight
+	}
+	return commands[len(commands)-1]
+}
+
+// duration samples a log-normal run time in milliseconds
+func (g *generator) duration(cmd command, rel release) int {
+	ms := cmd.medianMS * math.Exp(cmd.spread*g.rng.NormFloat64())
+	if rel.regressed && cmd.op == "build" {
+		ms *= 1.8
+	}
+	return int(ms) + 1
+}
+
+func (g *generator) failRate(cmd command, rel release) float64 {
+	if rel.regressed && cmd.op == "deploy" {
+		return cmd.failRate * 6
+	}
+	return cmd.failRate
+}
+
+func (g *generator) detail() string {
+	details := []string{"src/index.ts", "api.test.ts", "no-unused-vars", "bundle.tgz", "minimal"}
+	return details[g.rng.IntN(len(details))]
+}
+
+func (g *generator) id(kind string, n int) string {
+	return fmt.Sprintf("%s%s-%d", IDPrefix(g.opts.RunID), kind, n)
+}
+
+// IDPrefix is the prefix of every id generated with runID
+func IDPrefix(runID string) string {
+	return "demo-" + runID + "-"
+}
+
+// hashUser mimics the SDK's hashed user ids
+func hashUser(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+func boolPtr(b bool) *bool    { return &b }
@@ -0,0 +1,117 @@
+package demo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testOptions() Options {
+	return Options{
+		Days:           14,
+		Users:          40,
+		SessionsPerDay: 60,
+		Seed:           42,
+		Now:            time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		RunID:          "test",
+	}
+}
+
+func TestGenerate_Shape(t *testing.T) {
+	opts := testOptions()
+	events := Generate("proj-demo", opts)
+	if len(events) < opts.Days*opts.SessionsPerDay*2 {
+		t.Fatalf("only %d events generated", len(events))
+	}
+
+	start := opts.Now.Add(-time.Duration(opts.Days) * 24 * time.Hour)
+	ids := map[string]bool{}
+	traces := map[string]map[string]bool{}
+	for i, e := range events {
+		if e.ProjectID != "proj-demo" || e.UserIDH == "" || e.Version == "" || e.SDKName == "" {
+			t.Fatalf("event %d misses required fields: %+v", i, e)
+		}
+		if ids[e.EventID] {
+			t.Fatalf("duplicate event id %s", e.EventID)
+		}
+		ids[e.EventID] = true
+		if e.EventTS.Before(start) || e.EventTS.After(opts.Now.Add(time.Hour)) {
+			t.Fatalf("event %s at %s outside the window", e.EventID, e.EventTS)
+		}
+		if i > 0 && e.EventTS.Before(events[i-1].EventTS) {
+			t.Fatal("events are not ordered by time")
+		}
+		switch e.EventType {
+		case "perf":
+			if e.DurationMS == nil || *e.DurationMS <= 0 {
+				t.Fatalf("perf event without duration: %+v", e)
+			}
+		case "error":
+			if e.Severity == nil || e.Code == nil || e.Message == nil {
+				t.Fatalf("error event without details: %+v", e)
+			}
+		case "user_action":
+		default:
+			t.Fatalf("unexpected event type %q", e.EventType)
+		}
+		if e.TraceID == nil || e.SessionID == nil {
+			t.Fatalf("event without trace or session: %+v", e)
+		}
+		if traces[*e.TraceID] == nil {
+			traces[*e.TraceID] = map[string]bool{}
+		}
+		traces[*e.TraceID][e.EventType] = true
+	}
+
+	for id, types := range traces {
+		if !types["user_action"] || !types["perf"] {
+			t.Fatalf("trace %s lacks its user action or perf sample: %v", id, types)
+		}
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	a := Generate("proj-demo", testOptions())
+	b := Generate("proj-demo", testOptions())
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed produced different events")
+	}
+}
+
+func TestGenerate_RegressedRelease(t *testing.T) {
+	events := Generate("proj-demo", testOptions())
+
+	type stats struct{ runs, failures, buildMS, builds int }
+	byVersion := map[string]*stats{}
+	for _, e := range events {
+		if e.EventType != "perf" {
+			continue
+		}
+		s := byVersion[e.Version]
+		if s == nil {
+			s = &stats{}
+			byVersion[e.Version] = s
+		}
+		switch e.Op {
+		case "deploy":
+			s.runs++
+			if !*e.Success {
+				s.failures++
+			}
+		case "build":
+			s.builds++
+			s.buildMS += *e.DurationMS
+		}
+	}
+
+	bad, good := byVersion["2.4.0"], byVersion["2.4.1"]
+	if bad == nil || good == nil || byVersion["2.3.1"] == nil {
+		t.Fatalf("expected all releases to appear: %v", byVersion)
+	}
+	if bad.buildMS/bad.builds <= good.buildMS/good.builds {
+		t.Errorf("2.4.0 builds should be slower: %d ms vs %d ms", bad.buildMS/bad.builds, good.buildMS/good.builds)
+	}
+	if float64(bad.failures)/float64(bad.runs) <= float64(good.failures)/float64(max(good.runs, 1)) {
+		t.Errorf("2.4.0 deploys should fail more often: %d/%d vs %d/%d", bad.failures, bad.runs, good.failures, good.runs)
+	}
+}
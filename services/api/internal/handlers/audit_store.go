@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// AuditLogStore abstracts audit log writes for handlers, enabling dependency injection and unit testing.
+type AuditLogStore interface {
+	CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// memberRoleRank orders member roles so a higher role satisfies any lower minimum
+var memberRoleRank = map[string]int{
+	supabase.MemberRoleViewer: 1,
+	supabase.MemberRoleAdmin:  2,
+	supabase.MemberRoleOwner:  3,
+}
+
+// HasMinRole reports whether role is at least minRole
+func HasMinRole(role, minRole string) bool {
+	return memberRoleRank[role] > 0 && memberRoleRank[role] >= memberRoleRank[minRole]
+}
+
+// claimsFromContext returns the claims injected by the auth middleware.
+// It writes the error response and returns false when they are missing.
+func claimsFromContext(c *gin.Context) (*auth.SupabaseClaims, bool) {
+	claimsAny, ok := c.Get(auth.ContextKeyClaims)
+	if !ok {
+		apiErr := errors.NewAPIError(errors.ErrUnauthorized)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, false
+	}
+
+	claims, ok := claimsAny.(*auth.SupabaseClaims)
+	if !ok || claims.Subject == "" {
+		apiErr := errors.NewAPIError(errors.ErrUnauthorized)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// authorizeProject loads the project and the caller's role in it, and checks the role is at
+// least minRole. The project's recorded owner is always an owner; everyone else needs a
+// project_members row. It writes the error response and returns false when the request cannot proceed.
+func authorizeProject(c *gin.Context, projectStore ProjectStore, memberStore ProjectMemberStore, claims *auth.SupabaseClaims, projectID string, minRole string) (*supabase.Project, string, bool) {
+	project, err := projectStore.GetProjectByID(c.Request.Context(), projectID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			apiErr := errors.NewAPIError(errors.ErrNotFound)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return nil, "", false
+		}
+		// Other errors (e.g., invalid UUID format) are parameter errors
+		log.Printf("GetProjectByID error: %s", err.Error())
+		apiErr := errors.NewAPIError(errors.ErrBadRequest)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, "", false
+	}
+
+	// Projects pending deletion are only reachable through restore
+	if project == nil || project.DeletedAt != nil {
+		apiErr := errors.NewAPIError(errors.ErrNotFound)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, "", false
+	}
+
+	role := supabase.MemberRoleOwner
+	if project.OwnerUserID != claims.Subject {
+		member, err := memberStore.GetProjectMember(c.Request.Context(), project.ID, claims.Subject)
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			log.Printf("GetProjectMember error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return nil, "", false
+		}
+		if err != nil || member == nil {
+			apiErr := errors.NewAPIError(errors.ErrForbidden)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return nil, "", false
+		}
+		role = member.Role
+	}
+
+	if !HasMinRole(role, minRole) {
+		apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Requires project role " + minRole + " or higher")
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, "", false
+	}
+
+	return project, role, true
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProjectMemberStore implements handlers.ProjectMemberStore for testing.
+type MockProjectMemberStore struct {
+	mock.Mock
+}
+
+// NewMockProjectMemberStore creates a new mock ProjectMemberStore.
+func NewMockProjectMemberStore() *MockProjectMemberStore {
+	return &MockProjectMemberStore{}
+}
+
+func (m *MockProjectMemberStore) GetProjectMember(ctx context.Context, projectID, userID string) (*supabase.ProjectMember, error) {
+	args := m.Called(ctx, projectID, userID)
+	member := args.Get(0)
+	if member == nil {
+		return nil, args.Error(1)
+	}
+	return member.(*supabase.ProjectMember), args.Error(1)
+}
+
+func (m *MockProjectMemberStore) ListProjectMembers(ctx context.Context, projectID string) ([]supabase.ProjectMember, error) {
+	args := m.Called(ctx, projectID)
+	members := args.Get(0)
+	if members == nil {
+		return nil, args.Error(1)
+	}
+	return members.([]supabase.ProjectMember), args.Error(1)
+}
+
+func (m *MockProjectMemberStore) AddProjectMember(ctx context.Context, projectID, userID, role string) (*supabase.ProjectMember, error) {
+	args := m.Called(ctx, projectID, userID, role)
+	member := args.Get(0)
+	if member == nil {
+		return nil, args.Error(1)
+	}
+	return member.(*supabase.ProjectMember), args.Error(1)
+}
+
+func (m *MockProjectMemberStore) UpdateProjectMemberRole(ctx context.Context, projectID, userID, role string) (*supabase.ProjectMember, error) {
+	args := m.Called(ctx, projectID, userID, role)
+	member := args.Get(0)
+	if member == nil {
+		return nil, args.Error(1)
+	}
+	return member.(*supabase.ProjectMember), args.Error(1)
+}
+
+func (m *MockProjectMemberStore) RemoveProjectMember(ctx context.Context, projectID, userID string) error {
+	args := m.Called(ctx, projectID, userID)
+	return args.Error(0)
+}
+
+// newMemberStoreWithRole returns a member store where userID holds role in projectID
+func newMemberStoreWithRole(projectID, userID, role string) *MockProjectMemberStore {
+	store := NewMockProjectMemberStore()
+	if role == "" {
+		store.On("GetProjectMember", mock.Anything, projectID, userID).Return(nil, errors.New("project member not found"))
+	} else {
+		store.On("GetProjectMember", mock.Anything, projectID, userID).
+			Return(&supabase.ProjectMember{ProjectID: projectID, UserID: userID, Role: role}, nil)
+	}
+	return store
+}
+
+func TestHasMinRole(t *testing.T) {
+	assert.True(t, HasMinRole(supabase.MemberRoleOwner, supabase.MemberRoleAdmin))
+	assert.True(t, HasMinRole(supabase.MemberRoleAdmin, supabase.MemberRoleAdmin))
+	assert.True(t, HasMinRole(supabase.MemberRoleViewer, supabase.MemberRoleViewer))
+	assert.False(t, HasMinRole(supabase.MemberRoleViewer, supabase.MemberRoleAdmin))
+	assert.False(t, HasMinRole("", supabase.MemberRoleViewer))
+	assert.False(t, HasMinRole("superuser", supabase.MemberRoleViewer))
+}
+
+// TestProjectKeyHandlers_MemberRoles tests that only admins can create and list keys
+func TestProjectKeyHandlers_MemberRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projectID := "proj-roles"
+	project := &supabase.Project{ID: projectID, Name: "roles", OwnerUserID: "owner-roles"}
+
+	tests := []struct {
+		name       string
+		userID     string
+		role       string
+		wantCreate int
+		wantList   int
+	}{
+		{"admin", "user-role-admin", supabase.MemberRoleAdmin, http.StatusCreated, http.StatusOK},
+		{"viewer", "user-role-viewer", supabase.MemberRoleViewer, http.StatusForbidden, http.StatusForbidden},
+		{"non-member", "user-role-none", "", http.StatusForbidden, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &auth.SupabaseClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: tt.userID}}
+
+			mockProjectStore := NewMockProjectStore()
+			mockProjectStore.On("GetProjectByID", mock.Anything, projectID).Return(project, nil)
+			memberStore := newMemberStoreWithRole(projectID, tt.userID, tt.role)
+			mockKeyStore := NewMockProjectKeyStore()
+			mockKeyStore.On("CreateProjectKey", mock.Anything, mock.Anything).
+				Return(&supabase.ProjectKey{ID: "key-1", ProjectID: projectID, Label: "ci", Env: "prod", Scopes: []string{"ingest"}}, nil)
+			mockKeyStore.On("ListProjectKeys", mock.Anything, projectID).Return([]supabase.ProjectKey{}, nil)
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+projectID+"/keys", `{"label":"ci"}`, projectID, tt.userID)
+			c.Set(auth.ContextKeyClaims, claims)
+			CreateProjectKeyHandler(mockProjectStore, memberStore, mockKeyStore)(c)
+			assert.Equal(t, tt.wantCreate, w.Code)
+
+			w = httptest.NewRecorder()
+			c = newTokenContext(w, http.MethodGet, "/api/v1/projects/"+projectID+"/keys", "", projectID, tt.userID)
+			ListProjectKeysHandler(mockProjectStore, memberStore, mockKeyStore, 7*24*time.Hour)(c)
+			assert.Equal(t, tt.wantList, w.Code)
+		})
+	}
+}
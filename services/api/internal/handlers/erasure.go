@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// ErasureHandlerConfig holds the dependencies of the data subject erasure handlers
+type ErasureHandlerConfig struct {
+	ProjectStore ProjectStore
+	MemberStore  ProjectMemberStore
+	ErasureStore ErasureStore
+	AuditStore   AuditLogStore
+	UserIDs      UserIDPseudonymizer
+}
+
+// CreateErasureRequest matches the OpenAPI schema. It carries exactly one of UserIDH and UserID,
+// a raw identifier hashed the way ingestion hashes it.
+type CreateErasureRequest struct {
+	UserIDH string `json:"user_id_h" binding:"omitempty,max=128"`
+	UserID  string `json:"user_id" binding:"omitempty,max=256"`
+}
+
+// ErasureRequestResponse matches the OpenAPI schema. It never includes the subject's identifier.
+type ErasureRequestResponse struct {
+	ID                    string     `json:"id"`
+	ProjectID             string     `json:"project_id"`
+	Status                string     `json:"status"`
+	RequestedBy           string     `json:"requested_by"`
+	CreatedAt             time.Time  `json:"created_at"`
+	StartedAt             *time.Time `json:"started_at,omitempty"`
+	CompletedAt           *time.Time `json:"completed_at,omitempty"`
+	EventsDeleted         int        `json:"events_deleted"`
+	ConsentsDeleted       int        `json:"consents_deleted"`
+	ConsentHistoryDeleted int        `json:"consent_history_deleted"`
+	Error                 *string    `json:"error,omitempty"`
+}
+
+func newErasureRequestResponse(request supabase.ErasureRequest) ErasureRequestResponse {
+	return ErasureRequestResponse{
+		ID:                    request.ID,
+		ProjectID:             request.ProjectID,
+		Status:                request.Status,
+		RequestedBy:           request.RequestedBy,
+		CreatedAt:             request.CreatedAt,
+		StartedAt:             request.StartedAt,
+		CompletedAt:           request.CompletedAt,
+		EventsDeleted:         request.EventsDeleted,
+		ConsentsDeleted:       request.ConsentsDeleted,
+		ConsentHistoryDeleted: request.ConsentHistoryDeleted,
+		Error:                 request.Error,
+	}
+}
+
+// CreateErasureRequestHandler handles POST /api/v1/projects/{id}/erasures
+// The deletion runs in the background; the response is the request to poll for its status.
+func CreateErasureRequestHandler(cfg ErasureHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req CreateErasureRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if !validSubject(c, req.UserIDH, req.UserID) {
+			return
+		}
+
+		// 3) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 4) Queue the request
+		userIDH, ok := subjectUserIDH(c, cfg.UserIDs, project.ID, req.UserIDH, req.UserID)
+		if !ok {
+			return
+		}
+		subjectHash := crypto.HashSubject(userIDH)
+		request, err := cfg.ErasureStore.CreateErasureRequest(c.Request.Context(), supabase.CreateErasureRequestParams{
+			ProjectID:   project.ID,
+			UserIDH:     userIDH,
+			SubjectHash: subjectHash,
+			RequestedBy: claims.Subject,
+		})
+		if err != nil || request == nil {
+			if err != nil {
+				log.Printf("CreateErasureRequest error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Audit by subject hash only
+		actorID := claims.Subject
+		entry := supabase.AuditLog{
+			ProjectID:  project.ID,
+			ActorType:  supabase.ActorTypeUser,
+			ActorID:    &actorID,
+			Action:     "subject.erasure_requested",
+			Success:    true,
+			StatusCode: http.StatusAccepted,
+			AuthMode:   supabase.AuthModeJWT,
+			Details: map[string]interface{}{
+				"erasure_id":   request.ID,
+				"subject_hash": subjectHash,
+			},
+		}
+		if err := cfg.AuditStore.CreateAuditLog(c.Request.Context(), entry); err != nil {
+			log.Printf("CreateAuditLog error: %s", err.Error())
+		}
+
+		c.JSON(http.StatusAccepted, newErasureRequestResponse(*request))
+	}
+}
+
+// GetErasureRequestHandler handles GET /api/v1/projects/{id}/erasures/{erasureId}
+func GetErasureRequestHandler(cfg ErasureHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 3) Load the request
+		request, err := cfg.ErasureStore.GetErasureRequest(c.Request.Context(), project.ID, c.Param("erasureId"))
+		if err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "not found") {
+				apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("Erasure request not found")
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			log.Printf("GetErasureRequest error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.JSON(http.StatusOK, newErasureRequestResponse(*request))
+	}
+}
+
+// validSubject checks that a data subject request names exactly one of user_id_h and user_id
+func validSubject(c *gin.Context, userIDH, userID string) bool {
+	if (strings.TrimSpace(userIDH) == "") == (strings.TrimSpace(userID) == "") {
+		apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Exactly one of user_id_h and user_id is required")
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return false
+	}
+	return true
+}
+
+// subjectUserIDH returns the user_id_h a data subject request refers to, hashing a raw user_id
+// with the project's salt as ingestion does. The raw value is never stored or logged.
+func subjectUserIDH(c *gin.Context, ids UserIDPseudonymizer, projectID, userIDH, userID string) (string, bool) {
+	if userID == "" {
+		return userIDH, true
+	}
+
+	h, err := ids.UserIDH(c.Request.Context(), projectID, userID)
+	if err != nil {
+		log.Printf("UserIDH error: project %s: %s", projectID, err.Error())
+		apiErr := errors.NewAPIError(errors.ErrInternalError)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return "", false
+	}
+	return h, true
+}
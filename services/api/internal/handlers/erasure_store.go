@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// ErasureStore abstracts data subject erasure requests for handlers, enabling dependency injection and unit testing.
+type ErasureStore interface {
+	CreateErasureRequest(ctx context.Context, params supabase.CreateErasureRequestParams) (*supabase.ErasureRequest, error)
+	GetErasureRequest(ctx context.Context, projectID, requestID string) (*supabase.ErasureRequest, error)
+}
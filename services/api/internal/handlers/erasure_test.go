@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+)
+
+// MockErasureStore implements handlers.ErasureStore for testing.
+type MockErasureStore struct {
+	mock.Mock
+}
+
+func (m *MockErasureStore) CreateErasureRequest(ctx context.Context, params supabase.CreateErasureRequestParams) (*supabase.ErasureRequest, error) {
+	args := m.Called(ctx, params)
+	request := args.Get(0)
+	if request == nil {
+		return nil, args.Error(1)
+	}
+	return request.(*supabase.ErasureRequest), args.Error(1)
+}
+
+func (m *MockErasureStore) GetErasureRequest(ctx context.Context, projectID, requestID string) (*supabase.ErasureRequest, error) {
+	args := m.Called(ctx, projectID, requestID)
+	request := args.Get(0)
+	if request == nil {
+		return nil, args.Error(1)
+	}
+	return request.(*supabase.ErasureRequest), args.Error(1)
+}
+
+// TestCreateErasureRequestHandler_Accepted tests that a request is queued and audited by hash only
+func TestCreateErasureRequestHandler_Accepted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	subject := "uh-erase-me"
+	subjectHash := crypto.HashSubject(subject)
+
+	store := &MockErasureStore{}
+	store.On("CreateErasureRequest", mock.Anything, supabase.CreateErasureRequestParams{
+		ProjectID: memberTestProjectID, UserIDH: subject, SubjectHash: subjectHash, RequestedBy: "admin-user",
+	}).Return(&supabase.ErasureRequest{
+		ID: "er-1", ProjectID: memberTestProjectID, UserIDH: &subject, SubjectHash: subjectHash,
+		Status: supabase.ErasureStatusPending, RequestedBy: "admin-user", CreatedAt: time.Now(),
+	}, nil)
+	audit := &MockAuditLogStore{}
+	audit.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(e supabase.AuditLog) bool {
+		return e.Action == "subject.erasure_requested" && e.Details["subject_hash"] == subjectHash && e.Details["erasure_id"] == "er-1"
+	})).Return(nil)
+
+	cfg := ErasureHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+		ErasureStore: store,
+		AuditStore:   audit,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/erasures", `{"user_id_h":"uh-erase-me"}`, memberTestProjectID, "admin-user")
+	CreateErasureRequestHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	assert.NotContains(t, w.Body.String(), subject)
+	store.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+// TestCreateErasureRequestHandler_ViewerForbidden tests that viewers cannot request erasure
+func TestCreateErasureRequestHandler_ViewerForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &MockErasureStore{}
+	cfg := ErasureHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "viewer-user", supabase.MemberRoleViewer),
+		ErasureStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/erasures", `{"user_id_h":"uh-1"}`, memberTestProjectID, "viewer-user")
+	CreateErasureRequestHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	store.AssertNotCalled(t, "CreateErasureRequest", mock.Anything, mock.Anything)
+}
+
+// TestCreateErasureRequestHandler_RawUserID tests that a raw user_id is hashed with the project's
+// salt and neither stored nor audited
+func TestCreateErasureRequestHandler_RawUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rawID := "alice@example.com"
+	userIDH := "uh-salted-alice"
+	subjectHash := crypto.HashSubject(userIDH)
+
+	ids := &MockUserIDPseudonymizer{}
+	ids.On("UserIDH", mock.Anything, memberTestProjectID, rawID).Return(userIDH, nil)
+	store := &MockErasureStore{}
+	store.On("CreateErasureRequest", mock.Anything, supabase.CreateErasureRequestParams{
+		ProjectID: memberTestProjectID, UserIDH: userIDH, SubjectHash: subjectHash, RequestedBy: "admin-user",
+	}).Return(&supabase.ErasureRequest{
+		ID: "er-2", ProjectID: memberTestProjectID, UserIDH: &userIDH, SubjectHash: subjectHash,
+		Status: supabase.ErasureStatusPending, RequestedBy: "admin-user", CreatedAt: time.Now(),
+	}, nil)
+	audit := &MockAuditLogStore{}
+	audit.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(e supabase.AuditLog) bool {
+		for _, v := range e.Details {
+			if v == rawID {
+				return false
+			}
+		}
+		return e.Details["subject_hash"] == subjectHash
+	})).Return(nil)
+
+	cfg := ErasureHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+		ErasureStore: store,
+		AuditStore:   audit,
+		UserIDs:      ids,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/erasures", `{"user_id":"alice@example.com"}`, memberTestProjectID, "admin-user")
+	CreateErasureRequestHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.NotContains(t, w.Body.String(), rawID)
+	ids.AssertExpectations(t)
+	store.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+// TestCreateErasureRequestHandler_Subject tests that exactly one of user_id_h and user_id is required
+func TestCreateErasureRequestHandler_Subject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := ErasureHandlerConfig{}
+
+	for name, body := range map[string]string{
+		"blank hash": `{"user_id_h":"  "}`,
+		"neither":    `{}`,
+		"both":       `{"user_id_h":"uh-1","user_id":"alice"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/erasures", body, memberTestProjectID, "admin-user")
+			CreateErasureRequestHandler(cfg)(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+// TestGetErasureRequestHandler tests status lookups
+func TestGetErasureRequestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	completedAt := time.Now()
+	store := &MockErasureStore{}
+	store.On("GetErasureRequest", mock.Anything, memberTestProjectID, "er-1").Return(&supabase.ErasureRequest{
+		ID: "er-1", ProjectID: memberTestProjectID, Status: supabase.ErasureStatusCompleted,
+		CompletedAt: &completedAt, EventsDeleted: 42,
+	}, nil)
+	store.On("GetErasureRequest", mock.Anything, memberTestProjectID, "er-missing").Return(nil, errors.New("erasure request not found"))
+
+	cfg := ErasureHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+		ErasureStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/erasures/er-1", "", memberTestProjectID, "admin-user")
+	c.Params = append(c.Params, gin.Param{Key: "erasureId", Value: "er-1"})
+	GetErasureRequestHandler(cfg)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"events_deleted":42`)
+
+	w = httptest.NewRecorder()
+	c = newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/erasures/er-missing", "", memberTestProjectID, "admin-user")
+	c.Params = append(c.Params, gin.Param{Key: "erasureId", Value: "er-missing"})
+	GetErasureRequestHandler(cfg)(c)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+const (
+	defaultEventSearchLimit = 100
+	maxEventSearchLimit     = 1000
+)
+
+// SearchEventsResponse matches the OpenAPI schema
+type SearchEventsResponse struct {
+	Events []json.RawMessage `json:"events"`
+	// NextCursor is set when more events match; pass it as cursor to get them
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// eventCursor is the JSON inside an opaque page cursor
+type eventCursor struct {
+	EventTS time.Time `json:"ts"`
+	EventID string    `json:"id"`
+}
+
+func encodeEventCursor(cursor eventCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeEventCursor(s string) (*supabase.EventCursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	var cursor eventCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.EventID == "" || cursor.EventTS.IsZero() {
+		return nil, false
+	}
+	return &supabase.EventCursor{EventTS: cursor.EventTS, EventID: cursor.EventID}, true
+}
+
+// SearchEventsHandler handles GET /api/v1/projects/{id}/events
+// Events come newest first, paged by an opaque cursor over (event_ts, event_id).
+func SearchEventsHandler(projectStore ProjectStore, memberStore ProjectMemberStore, eventStore EventSearchStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate the query
+		q, msg := parseEventQuery(c)
+		if msg != "" {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage(msg)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and the caller is a member
+		project, _, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleViewer)
+		if !ok {
+			return
+		}
+		q.ProjectID = project.ID
+
+		// 4) Fetch one extra event to learn whether another page follows
+		limit := q.Limit
+		q.Limit++
+		rows, err := eventStore.SearchEvents(c.Request.Context(), q)
+		if err != nil {
+			log.Printf("SearchEvents error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Build response
+		response := SearchEventsResponse{Events: rows}
+		if response.Events == nil {
+			response.Events = []json.RawMessage{}
+		}
+		if len(rows) > limit {
+			response.Events = rows[:limit]
+			var last struct {
+				EventTS time.Time `json:"event_ts"`
+				EventID string    `json:"event_id"`
+			}
+			if err := json.Unmarshal(rows[limit-1], &last); err != nil {
+				log.Printf("SearchEvents cursor error: %s", err.Error())
+				apiErr := errors.NewAPIError(errors.ErrInternalError)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			next := encodeEventCursor(eventCursor{EventTS: last.EventTS, EventID: last.EventID})
+			response.NextCursor = &next
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// parseEventQuery reads the search filters from the query string. It returns a message
+// describing the first invalid parameter, if any.
+func parseEventQuery(c *gin.Context) (supabase.EventQuery, string) {
+	q := supabase.EventQuery{
+		EventType: c.Query("event_type"),
+		Op:        c.Query("op"),
+		Version:   c.Query("version"),
+		Severity:  c.Query("severity"),
+		SDKName:   c.Query("sdk_name"),
+		SessionID: c.Query("session_id"),
+		TraceID:   c.Query("trace_id"),
+		UserIDH:   c.Query("user_id_h"),
+		Limit:     defaultEventSearchLimit,
+	}
+
+	for name, dst := range map[string]**time.Time{"from": &q.From, "to": &q.To} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return q, name + " must be an RFC 3339 timestamp"
+			}
+			*dst = &t
+		}
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return q, "from must be before to"
+	}
+
+	if q.EventType != "" && !slices.Contains([]string{"error", "perf", "user_action"}, q.EventType) {
+		return q, "event_type must be one of error, perf, user_action"
+	}
+	if q.Severity != "" && !slices.Contains([]string{"warn", "error", "fatal"}, q.Severity) {
+		return q, "severity must be one of warn, error, fatal"
+	}
+	if v := c.Query("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, "success must be true or false"
+		}
+		q.Success = &success
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxEventSearchLimit {
+			return q, "limit must be between 1 and " + strconv.Itoa(maxEventSearchLimit)
+		}
+		q.Limit = limit
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		cursor, ok := decodeEventCursor(v)
+		if !ok {
+			return q, "invalid cursor"
+		}
+		q.Before = cursor
+	}
+
+	// event_ts and event_id are always returned, as the next page's cursor is built from them
+	if v := c.Query("fields"); v != "" {
+		q.Fields = []string{"event_ts", "event_id"}
+		for _, field := range strings.Split(v, ",") {
+			field = strings.TrimSpace(field)
+			if !slices.Contains(supabase.EventFields, field) {
+				return q, "unknown field " + strconv.Quote(field)
+			}
+			if !slices.Contains(q.Fields, field) {
+				q.Fields = append(q.Fields, field)
+			}
+		}
+	}
+
+	return q, ""
+}
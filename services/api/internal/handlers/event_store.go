@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// EventStore abstracts event data access for handlers, enabling dependency injection and unit testing.
+type EventStore interface {
+	InsertEvents(ctx context.Context, events []supabase.Event) error
+}
+
+// UserIDPseudonymizer hashes raw end user identifiers sent at ingestion into user_id_h values.
+type UserIDPseudonymizer interface {
+	UserIDH(ctx context.Context, projectID, userID string) (string, error)
+}
+
+// EventSearchStore abstracts event reads for handlers.
+type EventSearchStore interface {
+	SearchEvents(ctx context.Context, q supabase.EventQuery) ([]json.RawMessage, error)
+}
+
+// EventTimeseriesStore abstracts event aggregation for handlers.
+type EventTimeseriesStore interface {
+	EventTimeseries(ctx context.Context, q supabase.TimeseriesQuery) ([]supabase.TimeseriesRow, error)
+}
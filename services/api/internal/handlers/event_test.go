@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// MockEventSearchStore implements handlers.EventSearchStore for testing.
+type MockEventSearchStore struct {
+	mock.Mock
+}
+
+func (m *MockEventSearchStore) SearchEvents(ctx context.Context, q supabase.EventQuery) ([]json.RawMessage, error) {
+	args := m.Called(ctx, q)
+	rows := args.Get(0)
+	if rows == nil {
+		return nil, args.Error(1)
+	}
+	return rows.([]json.RawMessage), args.Error(1)
+}
+
+func eventRows(n int) []json.RawMessage {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rows := make([]json.RawMessage, n)
+	for i := range rows {
+		ts := base.Add(-time.Duration(i) * time.Minute).Format(time.RFC3339Nano)
+		rows[i] = json.RawMessage(`{"event_id":"evt-` + string(rune('a'+i)) + `","event_ts":"` + ts + `","op":"build"}`)
+	}
+	return rows
+}
+
+// TestSearchEventsHandler_PagesWithCursor tests filters, field selection and cursor round-trips
+func TestSearchEventsHandler_PagesWithCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &MockEventSearchStore{}
+	store.On("SearchEvents", mock.Anything, mock.MatchedBy(func(q supabase.EventQuery) bool {
+		return q.Before == nil && q.ProjectID == memberTestProjectID && q.Limit == 3 && q.Op == "build" &&
+			q.Success != nil && !*q.Success && q.From != nil &&
+			len(q.Fields) == 3 && q.Fields[0] == "event_ts" && q.Fields[1] == "event_id" && q.Fields[2] == "op"
+	})).Return(eventRows(3), nil)
+	store.On("SearchEvents", mock.Anything, mock.MatchedBy(func(q supabase.EventQuery) bool {
+		return q.Before != nil && q.Before.EventID == "evt-b" && q.Before.EventTS.Equal(time.Date(2026, 10, 1, 11, 59, 0, 0, time.UTC))
+	})).Return(eventRows(1), nil)
+
+	cfgProjects := newMemberTestProjectStore()
+	members := newMemberStoreWithRole(memberTestProjectID, "viewer-user", supabase.MemberRoleViewer)
+	path := "/api/v1/projects/" + memberTestProjectID + "/events?op=build&success=false&from=2026-09-01T00:00:00Z&fields=op&limit=2"
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, path, "", memberTestProjectID, "viewer-user")
+	SearchEventsHandler(cfgProjects, members, store)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page SearchEventsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Events, 2)
+	if assert.NotNil(t, page.NextCursor) {
+		w = httptest.NewRecorder()
+		c = newTokenContext(w, http.MethodGet, path+"&cursor="+*page.NextCursor, "", memberTestProjectID, "viewer-user")
+		SearchEventsHandler(cfgProjects, members, store)(c)
+
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, w.Body.String(), "next_cursor")
+	}
+	store.AssertExpectations(t)
+}
+
+// TestSearchEventsHandler_InvalidQuery tests that malformed parameters are rejected before any lookup
+func TestSearchEventsHandler_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for name, query := range map[string]string{
+		"bad from":      "from=yesterday",
+		"reversed":      "from=2026-10-02T00:00:00Z&to=2026-10-01T00:00:00Z",
+		"event type":    "event_type=click",
+		"success":       "success=maybe",
+		"limit":         "limit=5000",
+		"cursor":        "cursor=not-a-cursor",
+		"unknown field": "fields=op,password",
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := &MockEventSearchStore{}
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/events?"+query, "", memberTestProjectID, "viewer-user")
+			SearchEventsHandler(newMemberTestProjectStore(), newMemberStoreWithRole(memberTestProjectID, "viewer-user", supabase.MemberRoleViewer), store)(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			store.AssertNotCalled(t, "SearchEvents", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestSearchEventsHandler_NonMember tests that outsiders cannot read events
+func TestSearchEventsHandler_NonMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &MockEventSearchStore{}
+	members := newMemberStoreWithRole(memberTestProjectID, "stranger", "")
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/events", "", memberTestProjectID, "stranger")
+	SearchEventsHandler(newMemberTestProjectStore(), members, store)(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	store.AssertNotCalled(t, "SearchEvents", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+const (
+	// maxTimeseriesBuckets bounds the points per series, e.g. one day of minutes
+	maxTimeseriesBuckets   = 1500
+	defaultTimeseriesLimit = 10
+	maxTimeseriesLimit     = 50
+)
+
+var timeseriesGroupBys = []string{"op", "version", "event_type", "surface", "variant", "sdk_language"}
+
+// TimeseriesPoint matches the OpenAPI schema
+type TimeseriesPoint struct {
+	// TS is the bucket start, in the requested time zone
+	TS     time.Time `json:"ts"`
+	Events int64     `json:"events"`
+	Users  int64     `json:"users"`
+}
+
+// TimeseriesSeries matches the OpenAPI schema
+type TimeseriesSeries struct {
+	// Group is the dimension value; null without group_by or for events lacking the dimension
+	Group  *string           `json:"group"`
+	Events int64             `json:"events"`
+	Points []TimeseriesPoint `json:"points"`
+}
+
+// EventTimeseriesResponse matches the OpenAPI schema
+type EventTimeseriesResponse struct {
+	Bucket   string             `json:"bucket"`
+	Timezone string             `json:"timezone"`
+	From     time.Time          `json:"from"`
+	To       time.Time          `json:"to"`
+	GroupBy  *string            `json:"group_by,omitempty"`
+	Series   []TimeseriesSeries `json:"series"`
+	// Truncated reports that only the limit largest groups are included
+	Truncated bool `json:"truncated"`
+}
+
+// EventTimeseriesHandler handles GET /api/v1/projects/{id}/events/timeseries
+// Every series has one point per bucket between from and to, including empty ones.
+func EventTimeseriesHandler(projectStore ProjectStore, memberStore ProjectMemberStore, eventStore EventTimeseriesStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate the query
+		q, loc, limit, msg := parseTimeseriesQuery(c)
+		var starts []time.Time
+		if msg == "" {
+			q.From = truncateToBucket(q.From.In(loc), q.Bucket)
+			if starts = bucketStarts(q.From, q.To, q.Bucket); len(starts) > maxTimeseriesBuckets {
+				msg = "Range spans more than " + strconv.Itoa(maxTimeseriesBuckets) + " buckets; use a larger bucket"
+			}
+		}
+		if msg != "" {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage(msg)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and the caller is a member
+		project, _, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleViewer)
+		if !ok {
+			return
+		}
+		q.ProjectID = project.ID
+
+		// 4) Aggregate
+		rows, err := eventStore.EventTimeseries(c.Request.Context(), q)
+		if err != nil {
+			log.Printf("EventTimeseries error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Build response
+		series, truncated := buildTimeseries(rows, starts, loc, q.GroupBy != "", limit)
+		response := EventTimeseriesResponse{
+			Bucket:    q.Bucket,
+			Timezone:  q.Timezone,
+			From:      q.From,
+			To:        q.To.In(loc),
+			Series:    series,
+			Truncated: truncated,
+		}
+		if q.GroupBy != "" {
+			response.GroupBy = &q.GroupBy
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// parseTimeseriesQuery reads the aggregation parameters from the query string. It returns a
+// message describing the first invalid parameter, if any.
+func parseTimeseriesQuery(c *gin.Context) (supabase.TimeseriesQuery, *time.Location, int, string) {
+	q := supabase.TimeseriesQuery{
+		Bucket:    c.DefaultQuery("bucket", "hour"),
+		Timezone:  c.DefaultQuery("tz", "UTC"),
+		GroupBy:   c.Query("group_by"),
+		EventType: c.Query("event_type"),
+		Op:        c.Query("op"),
+		Version:   c.Query("version"),
+		To:        time.Now(),
+	}
+	limit := defaultTimeseriesLimit
+
+	from, err := time.Parse(time.RFC3339Nano, c.Query("from"))
+	if err != nil {
+		return q, nil, 0, "from is required and must be an RFC 3339 timestamp"
+	}
+	q.From = from
+	if v := c.Query("to"); v != "" {
+		if q.To, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return q, nil, 0, "to must be an RFC 3339 timestamp"
+		}
+	}
+	if !q.From.Before(q.To) {
+		return q, nil, 0, "from must be before to"
+	}
+
+	if !slices.Contains([]string{"minute", "hour", "day"}, q.Bucket) {
+		return q, nil, 0, "bucket must be one of minute, hour, day"
+	}
+	// Local is the server's zone, which callers cannot know
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil || q.Timezone == "" || q.Timezone == "Local" {
+		return q, nil, 0, "tz must be an IANA time zone name"
+	}
+	if q.GroupBy != "" && !slices.Contains(timeseriesGroupBys, q.GroupBy) {
+		return q, nil, 0, "group_by must be one of op, version, event_type, surface, variant, sdk_language"
+	}
+	if q.EventType != "" && !slices.Contains([]string{"error", "perf", "user_action"}, q.EventType) {
+		return q, nil, 0, "event_type must be one of error, perf, user_action"
+	}
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxTimeseriesLimit {
+			return q, nil, 0, "limit must be between 1 and " + strconv.Itoa(maxTimeseriesLimit)
+		}
+	}
+
+	return q, loc, limit, ""
+}
+
+// truncateToBucket returns the start of the bucket holding t, on t's wall clock
+func truncateToBucket(t time.Time, bucket string) time.Time {
+	y, m, d := t.Date()
+	switch bucket {
+	case "minute":
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, t.Location())
+	case "hour":
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// bucketStarts lists the starts of the buckets from the aligned from up to to. Across daylight
+// saving changes it matches the database's truncation: a repeated wall-clock hour is one bucket
+// and a skipped one has none. It stops after maxTimeseriesBuckets+1 so callers can reject the range.
+func bucketStarts(from, to time.Time, bucket string) []time.Time {
+	step := func(t time.Time) time.Time {
+		switch bucket {
+		case "minute":
+			return t.Add(time.Minute)
+		case "hour":
+			return t.Add(time.Hour)
+		default:
+			return t.AddDate(0, 0, 1)
+		}
+	}
+
+	var starts []time.Time
+	for t := from; t.Before(to) && len(starts) <= maxTimeseriesBuckets; {
+		starts = append(starts, t)
+		n := step(t)
+		next := truncateToBucket(n, bucket)
+		for !next.After(t) {
+			n = step(n)
+			next = truncateToBucket(n, bucket)
+		}
+		t = next
+	}
+	return starts
+}
+
+// buildTimeseries turns the database rows into one gap-filled series per group, largest first,
+// keeping at most limit series. Buckets are matched by wall-clock time, which is how the
+// database truncated them. Without grouping there is always exactly one series.
+func buildTimeseries(rows []supabase.TimeseriesRow, starts []time.Time, loc *time.Location, grouped bool, limit int) ([]TimeseriesSeries, bool) {
+	const wallClock = "2006-01-02T15:04"
+
+	index := make(map[string]int, len(starts))
+	for i, start := range starts {
+		index[start.Format(wallClock)] = i
+	}
+
+	newSeries := func(group *string) *TimeseriesSeries {
+		s := &TimeseriesSeries{Group: group, Points: make([]TimeseriesPoint, len(starts))}
+		for j, start := range starts {
+			s.Points[j].TS = start
+		}
+		return s
+	}
+
+	byGroup := map[string]*TimeseriesSeries{}
+	var order []*TimeseriesSeries
+	if !grouped {
+		s := newSeries(nil)
+		byGroup["\x00"] = s
+		order = append(order, s)
+	}
+	for _, row := range rows {
+		i, ok := index[row.BucketStart.In(loc).Format(wallClock)]
+		if !ok {
+			continue
+		}
+		key := "\x00"
+		if row.GroupValue != nil {
+			key = "=" + *row.GroupValue
+		}
+		s, ok := byGroup[key]
+		if !ok {
+			s = newSeries(row.GroupValue)
+			byGroup[key] = s
+			order = append(order, s)
+		}
+		s.Points[i].Events += row.EventCount
+		s.Points[i].Users += row.UserCount
+		s.Events += row.EventCount
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Events != order[j].Events {
+			return order[i].Events > order[j].Events
+		}
+		return groupName(order[i]) < groupName(order[j])
+	})
+
+	truncated := len(order) > limit
+	if truncated {
+		order = order[:limit]
+	}
+
+	series := make([]TimeseriesSeries, 0, len(order))
+	for _, s := range order {
+		series = append(series, *s)
+	}
+	return series, truncated
+}
+
+func groupName(s *TimeseriesSeries) string {
+	if s.Group == nil {
+		return ""
+	}
+	return *s.Group
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// MockEventTimeseriesStore implements handlers.EventTimeseriesStore for testing.
+type MockEventTimeseriesStore struct {
+	mock.Mock
+}
+
+func (m *MockEventTimeseriesStore) EventTimeseries(ctx context.Context, q supabase.TimeseriesQuery) ([]supabase.TimeseriesRow, error) {
+	args := m.Called(ctx, q)
+	rows := args.Get(0)
+	if rows == nil {
+		return nil, args.Error(1)
+	}
+	return rows.([]supabase.TimeseriesRow), args.Error(1)
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	return loc
+}
+
+// TestBucketStarts_DaylightSaving tests that buckets follow the local wall clock across DST changes
+func TestBucketStarts_DaylightSaving(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+
+	// Fall back on 2026-11-01: 01:00-02:00 happens twice and is one bucket, so four real hours
+	// make three buckets
+	from := time.Date(2026, 11, 1, 0, 0, 0, 0, ny)
+	var hours []int
+	for _, s := range bucketStarts(from, from.Add(4*time.Hour), "hour") {
+		hours = append(hours, s.Hour())
+	}
+	assert.Equal(t, []int{0, 1, 2}, hours)
+
+	// Spring forward on 2026-03-08: 02:00 does not exist
+	from = time.Date(2026, 3, 8, 0, 0, 0, 0, ny)
+	hours = nil
+	for _, s := range bucketStarts(from, from.Add(3*time.Hour), "hour") {
+		hours = append(hours, s.Hour())
+	}
+	assert.Equal(t, []int{0, 1, 3}, hours)
+
+	// Days are calendar days, 23 or 25 hours long around the changes
+	from = time.Date(2026, 10, 31, 0, 0, 0, 0, ny)
+	days := bucketStarts(from, time.Date(2026, 11, 3, 0, 0, 0, 0, ny), "day")
+	var dates []string
+	for _, d := range days {
+		dates = append(dates, d.Format("2006-01-02 15:04"))
+	}
+	assert.Equal(t, []string{"2026-10-31 00:00", "2026-11-01 00:00", "2026-11-02 00:00"}, dates)
+}
+
+// TestBuildTimeseries tests gap filling, grouping by wall-clock bucket and truncation
+func TestBuildTimeseries(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	starts := bucketStarts(from, from.Add(3*time.Hour), "hour")
+	v1, v2, v3 := "1.0.0", "2.0.0", "3.0.0"
+	rows := []supabase.TimeseriesRow{
+		{BucketStart: from, GroupValue: &v1, EventCount: 5, UserCount: 2},
+		{BucketStart: from.Add(2 * time.Hour), GroupValue: &v1, EventCount: 1, UserCount: 1},
+		{BucketStart: from.Add(time.Hour), GroupValue: &v2, EventCount: 9, UserCount: 4},
+		{BucketStart: from, GroupValue: &v3, EventCount: 1, UserCount: 1},
+		{BucketStart: from, GroupValue: nil, EventCount: 2, UserCount: 2},
+	}
+
+	series, truncated := buildTimeseries(rows, starts, time.UTC, true, 3)
+	assert.True(t, truncated)
+	if assert.Len(t, series, 3) {
+		assert.Equal(t, "2.0.0", *series[0].Group)
+		assert.Equal(t, []int64{0, 9, 0}, []int64{series[0].Points[0].Events, series[0].Points[1].Events, series[0].Points[2].Events})
+		assert.Equal(t, "1.0.0", *series[1].Group)
+		assert.Equal(t, int64(6), series[1].Events)
+		assert.Nil(t, series[2].Group)
+	}
+
+	// Without grouping an empty range still yields one zero-filled series
+	series, truncated = buildTimeseries(nil, starts, time.UTC, false, 3)
+	assert.False(t, truncated)
+	if assert.Len(t, series, 1) {
+		assert.Len(t, series[0].Points, 3)
+		assert.Equal(t, starts[2], series[0].Points[2].TS)
+	}
+}
+
+// TestEventTimeseriesHandler tests an aggregation in a time zone with grouping
+func TestEventTimeseriesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	dayStart := time.Date(2026, 10, 1, 0, 0, 0, 0, berlin)
+	op := "build"
+
+	store := &MockEventTimeseriesStore{}
+	store.On("EventTimeseries", mock.Anything, mock.MatchedBy(func(q supabase.TimeseriesQuery) bool {
+		return q.ProjectID == memberTestProjectID && q.Bucket == "day" && q.Timezone == "Europe/Berlin" &&
+			q.GroupBy == "op" && q.EventType == "error" && q.From.Equal(dayStart)
+	})).Return([]supabase.TimeseriesRow{
+		{BucketStart: dayStart.Add(24 * time.Hour).UTC(), GroupValue: &op, EventCount: 3, UserCount: 2},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	path := "/api/v1/projects/" + memberTestProjectID + "/events/timeseries?bucket=day&tz=Europe/Berlin&group_by=op&event_type=error" +
+		"&from=2026-10-01T10:00:00%2B02:00&to=2026-10-04T00:00:00%2B02:00"
+	c := newTokenContext(w, http.MethodGet, path, "", memberTestProjectID, "viewer-user")
+	EventTimeseriesHandler(newMemberTestProjectStore(), newMemberStoreWithRole(memberTestProjectID, "viewer-user", supabase.MemberRoleViewer), store)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp EventTimeseriesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Series, 1) && assert.Len(t, resp.Series[0].Points, 3) {
+		assert.Equal(t, int64(0), resp.Series[0].Points[0].Events)
+		assert.Equal(t, int64(3), resp.Series[0].Points[1].Events)
+		assert.Equal(t, int64(2), resp.Series[0].Points[1].Users)
+	}
+	assert.Contains(t, w.Body.String(), `"ts":"2026-10-02T00:00:00+02:00"`)
+	store.AssertExpectations(t)
+}
+
+// TestEventTimeseriesHandler_InvalidQuery tests that malformed parameters are rejected before any lookup
+func TestEventTimeseriesHandler_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for name, query := range map[string]string{
+		"missing from": "bucket=hour",
+		"bucket":       "from=2026-10-01T00:00:00Z&bucket=week",
+		"tz":           "from=2026-10-01T00:00:00Z&tz=Mars/Olympus",
+		"group by":     "from=2026-10-01T00:00:00Z&group_by=user_id_h",
+		"too many":     "from=2026-01-01T00:00:00Z&to=2026-10-01T00:00:00Z&bucket=minute",
+		"limit":        "from=2026-10-01T00:00:00Z&limit=500",
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := &MockEventTimeseriesStore{}
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/events/timeseries?"+query, "", memberTestProjectID, "viewer-user")
+			EventTimeseriesHandler(newMemberTestProjectStore(), newMemberStoreWithRole(memberTestProjectID, "viewer-user", supabase.MemberRoleViewer), store)(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			store.AssertNotCalled(t, "EventTimeseries", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/archive"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// archiveBatchSize is how many events are read or written per database call during export and import
+const archiveBatchSize = 500
+
+// ProjectArchiveHandlerConfig holds the dependencies of the export and import handlers
+type ProjectArchiveHandlerConfig struct {
+	ProjectStore ProjectStore
+	MemberStore  ProjectMemberStore
+	KeyStore     ProjectKeyStore
+	ConsentStore ConsentStore
+	EventStore   ArchiveEventStore
+	UserStore    UserStore
+	AuditStore   AuditLogStore
+	// MaxImportBytes bounds the size of an uploaded archive
+	MaxImportBytes int64
+}
+
+// ImportProjectResponse matches the OpenAPI schema
+type ImportProjectResponse struct {
+	ProjectID       string `json:"project_id"`
+	ArchiveVersion  int    `json:"archive_version"`
+	SettingsApplied bool   `json:"settings_applied"`
+	// MembersSkipped lists exported members (by email, or user id when unknown) who are not members here
+	MembersSkipped   []string `json:"members_skipped"`
+	ConsentsImported int      `json:"consents_imported"`
+	EventsImported   int      `json:"events_imported"`
+	// KeysNotImported counts exported keys; keys carry no secret in the archive and must be created anew
+	KeysNotImported int `json:"keys_not_imported"`
+}
+
+// ExportProjectHandler handles GET /api/v1/projects/{id}/export
+// The archive is streamed as it is built, so a failure after the first byte can only truncate it.
+func ExportProjectHandler(cfg ProjectArchiveHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Only the owner may take the project's data elsewhere
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleOwner)
+		if !ok {
+			return
+		}
+
+		// 3) Load everything but the events up front, while errors can still be reported as JSON
+		ctx := c.Request.Context()
+		members, err := cfg.MemberStore.ListProjectMembers(ctx, project.ID)
+		if err != nil {
+			log.Printf("ListProjectMembers error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		keys, err := cfg.KeyStore.ListProjectKeys(ctx, project.ID)
+		if err != nil {
+			log.Printf("ListProjectKeys error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		consents, err := cfg.ConsentStore.ListConsents(ctx, project.ID)
+		if err != nil {
+			log.Printf("ListConsents error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		memberRecords := make([]archive.MemberRecord, 0, len(members))
+		for _, member := range members {
+			record := archive.MemberRecord{UserID: member.UserID, Role: member.Role, CreatedAt: member.CreatedAt}
+			if user, err := cfg.UserStore.GetUserByID(ctx, member.UserID); err == nil && user != nil {
+				record.Email = user.Email
+			} else if err != nil {
+				log.Printf("GetUserByID error: %s", err.Error())
+			}
+			memberRecords = append(memberRecords, record)
+		}
+
+		// 4) Stream the archive
+		now := time.Now()
+		filename := fmt.Sprintf("libpulse-%s-%s.zip", project.ID, now.UTC().Format("20060102"))
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Status(http.StatusOK)
+
+		events, err := writeProjectArchive(c, cfg, project, memberRecords, keys, consents, now)
+		if err != nil {
+			log.Printf("export project %s error: %s", project.ID, err.Error())
+			c.Abort()
+			return
+		}
+
+		auditProjectArchive(c, cfg, claims.Subject, project.ID, "project.exported", map[string]interface{}{
+			"events": events,
+		})
+	}
+}
+
+// writeProjectArchive writes the archive to the response and returns the number of events exported
+func writeProjectArchive(c *gin.Context, cfg ProjectArchiveHandlerConfig, project *supabase.Project, members []archive.MemberRecord, keys []supabase.ProjectKey, consents []supabase.Consent, now time.Time) (int, error) {
+	w := archive.NewWriter(c.Writer, project.ID, now)
+
+	if err := w.Create(archive.FileProject); err != nil {
+		return 0, err
+	}
+	if err := w.Write(archive.ProjectRecord{
+		Name:          project.Name,
+		RetentionDays: project.RetentionDays,
+		SignedOnly:    project.SignedOnly,
+		CreatedAt:     project.CreatedAt,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := w.Create(archive.FileMembers); err != nil {
+		return 0, err
+	}
+	for _, member := range members {
+		if err := w.Write(member); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.Create(archive.FileKeys); err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if err := w.Write(archive.NewKeyRecord(key)); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.Create(archive.FileConsent); err != nil {
+		return 0, err
+	}
+	for _, consent := range consents {
+		if err := w.Write(archive.ConsentRecord{UserIDH: consent.UserIDH, State: consent.State, UpdatedAt: consent.UpdatedAt}); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.Create(archive.FileEvents); err != nil {
+		return 0, err
+	}
+	exported := 0
+	after := ""
+	for {
+		events, err := cfg.EventStore.ListEvents(c.Request.Context(), project.ID, after, archiveBatchSize)
+		if err != nil {
+			return exported, err
+		}
+		for _, event := range events {
+			if err := w.Write(event); err != nil {
+				return exported, err
+			}
+		}
+		exported += len(events)
+		if len(events) < archiveBatchSize {
+			break
+		}
+		after = events[len(events)-1].EventID
+	}
+
+	return exported, w.Close()
+}
+
+// ImportProjectHandler handles POST /api/v1/projects/{id}/import
+// The archive (application/zip) is loaded into the existing project {id}: settings are applied,
+// members are listed for the owner to invite, and consent state and events are copied. Consent
+// state only replaces older state in the project. Events keep their ids, so importing the same
+// archive twice does not duplicate them.
+func ImportProjectHandler(cfg ProjectArchiveHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Only the owner may load data into the project
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleOwner)
+		if !ok {
+			return
+		}
+
+		// 3) Spool the upload to disk; zip archives are read from the end
+		spool, err := os.CreateTemp("", "libpulse-import-*.zip")
+		if err != nil {
+			log.Printf("import spool error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		defer os.Remove(spool.Name())
+		defer spool.Close()
+
+		size, err := io.Copy(spool, http.MaxBytesReader(c.Writer, c.Request.Body, cfg.MaxImportBytes))
+		if err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage(fmt.Sprintf("Archive could not be read or exceeds %d bytes", cfg.MaxImportBytes))
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		ar, err := archive.NewReader(spool, size)
+		if err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid export archive: " + err.Error())
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) Load the archive into the project
+		result, err := importProjectArchive(c, cfg, project, ar)
+		if err != nil {
+			var apiErr *errors.APIError
+			var recordErr *archive.RecordError
+			if stderrors.As(err, &recordErr) {
+				apiErr = errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid export archive: " + err.Error())
+			} else {
+				log.Printf("import project %s error: %s", project.ID, err.Error())
+				apiErr = errors.NewAPIError(errors.ErrInternalError).WithMessage("Import failed part way; it is safe to retry with the same archive")
+			}
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		auditProjectArchive(c, cfg, claims.Subject, project.ID, "project.imported", map[string]interface{}{
+			"source_project_id": ar.Manifest.SourceProjectID,
+			"archive_version":   ar.Manifest.Version,
+			"events":            result.EventsImported,
+			"members_skipped":   len(result.MembersSkipped),
+		})
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func importProjectArchive(c *gin.Context, cfg ProjectArchiveHandlerConfig, project *supabase.Project, ar *archive.Reader) (*ImportProjectResponse, error) {
+	ctx := c.Request.Context()
+	result := &ImportProjectResponse{
+		ProjectID:      project.ID,
+		ArchiveVersion: ar.Manifest.Version,
+		MembersSkipped: []string{},
+	}
+
+	// Settings; the target keeps its own name, which is unique per owner
+	err := archive.ReadRecords(ar, archive.FileProject, func(record archive.ProjectRecord) error {
+		if result.SettingsApplied || (record.RetentionDays == nil && record.SignedOnly == nil) {
+			return nil
+		}
+		if _, err := cfg.ProjectStore.UpdateProject(ctx, project.ID, supabase.UpdateProjectParams{
+			RetentionDays: record.RetentionDays,
+			SignedOnly:    record.SignedOnly,
+		}); err != nil {
+			return fmt.Errorf("update project settings: %w", err)
+		}
+		result.SettingsApplied = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Members are never added from an archive: a role there is no consent to join this project.
+	// Everyone not already a member is listed so the owner can invite them. Members are matched
+	// by email since user ids differ between instances.
+	line := 0
+	err = archive.ReadRecords(ar, archive.FileMembers, func(record archive.MemberRecord) error {
+		line++
+		switch record.Role {
+		case supabase.MemberRoleViewer, supabase.MemberRoleAdmin, supabase.MemberRoleOwner:
+		default:
+			return &archive.RecordError{File: archive.FileMembers, Line: line, Err: fmt.Errorf("unknown role %q", record.Role)}
+		}
+
+		userID := ""
+		if record.Email != "" {
+			if id, err := cfg.UserStore.GetUserIDByEmail(ctx, record.Email); err == nil {
+				userID = id
+			}
+		} else if user, err := cfg.UserStore.GetUserByID(ctx, record.UserID); err == nil && user != nil {
+			userID = user.ID
+		}
+		if userID != "" {
+			if _, err := cfg.MemberStore.GetProjectMember(ctx, project.ID, userID); err == nil {
+				return nil
+			}
+		}
+
+		skipped := record.Email
+		if skipped == "" {
+			skipped = record.UserID
+		}
+		result.MembersSkipped = append(result.MembersSkipped, skipped)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = archive.ReadRecords(ar, archive.FileKeys, func(record archive.KeyRecord) error {
+		result.KeysNotImported++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	consents := make([]supabase.Consent, 0, archiveBatchSize)
+	flushConsents := func() error {
+		if len(consents) == 0 {
+			return nil
+		}
+		applied, err := cfg.ConsentStore.ImportConsents(ctx, project.ID, consents)
+		if err != nil {
+			return fmt.Errorf("import consent: %w", err)
+		}
+		result.ConsentsImported += applied
+		consents = consents[:0]
+		return nil
+	}
+	err = archive.ReadRecords(ar, archive.FileConsent, func(record archive.ConsentRecord) error {
+		consents = append(consents, supabase.Consent{ProjectID: project.ID, UserIDH: record.UserIDH, State: record.State, UpdatedAt: record.UpdatedAt})
+		if len(consents) == archiveBatchSize {
+			return flushConsents()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flushConsents()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]supabase.Event, 0, archiveBatchSize)
+	flushEvents := func() error {
+		if len(events) == 0 {
+			return nil
+		}
+		if err := cfg.EventStore.InsertEvents(ctx, events); err != nil {
+			return fmt.Errorf("insert events: %w", err)
+		}
+		result.EventsImported += len(events)
+		events = events[:0]
+		return nil
+	}
+	err = archive.ReadRecords(ar, archive.FileEvents, func(event supabase.Event) error {
+		event.ProjectID = project.ID
+		event.IngestedAt = nil
+		events = append(events, event)
+		if len(events) == archiveBatchSize {
+			return flushEvents()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flushEvents()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// auditProjectArchive records an export or import. Failures are logged only.
+func auditProjectArchive(c *gin.Context, cfg ProjectArchiveHandlerConfig, actorID, projectID, action string, details map[string]interface{}) {
+	entry := supabase.AuditLog{
+		ProjectID:  projectID,
+		ActorType:  supabase.ActorTypeUser,
+		ActorID:    &actorID,
+		Action:     action,
+		Success:    true,
+		StatusCode: http.StatusOK,
+		AuthMode:   supabase.AuthModeJWT,
+		Details:    details,
+	}
+	if err := cfg.AuditStore.CreateAuditLog(c.Request.Context(), entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+}
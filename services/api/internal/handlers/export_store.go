@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// ConsentStore abstracts consent state data access for handlers, enabling dependency injection and unit testing.
+type ConsentStore interface {
+	ListConsents(ctx context.Context, projectID string) ([]supabase.Consent, error)
+	ImportConsents(ctx context.Context, projectID string, consents []supabase.Consent) (int, error)
+}
+
+// ArchiveEventStore abstracts the event reads and writes of project export and import.
+type ArchiveEventStore interface {
+	EventStore
+	ListEvents(ctx context.Context, projectID, afterEventID string, limit int) ([]supabase.Event, error)
+}
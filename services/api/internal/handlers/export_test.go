@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/libpulse/platform/services/api/internal/archive"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// MockConsentStore implements handlers.ConsentStore for testing.
+type MockConsentStore struct {
+	mock.Mock
+}
+
+func (m *MockConsentStore) ListConsents(ctx context.Context, projectID string) ([]supabase.Consent, error) {
+	args := m.Called(ctx, projectID)
+	consents := args.Get(0)
+	if consents == nil {
+		return nil, args.Error(1)
+	}
+	return consents.([]supabase.Consent), args.Error(1)
+}
+
+func (m *MockConsentStore) ImportConsents(ctx context.Context, projectID string, consents []supabase.Consent) (int, error) {
+	args := m.Called(ctx, projectID, consents)
+	return args.Int(0), args.Error(1)
+}
+
+// MockArchiveEventStore implements handlers.ArchiveEventStore for testing.
+type MockArchiveEventStore struct {
+	MockEventStore
+}
+
+func (m *MockArchiveEventStore) ListEvents(ctx context.Context, projectID, afterEventID string, limit int) ([]supabase.Event, error) {
+	args := m.Called(ctx, projectID, afterEventID, limit)
+	events := args.Get(0)
+	if events == nil {
+		return nil, args.Error(1)
+	}
+	return events.([]supabase.Event), args.Error(1)
+}
+
+// TestExportImportProject_RoundTrip exports a project and loads the archive into another one
+func TestExportImportProject_RoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := mock.Anything
+	thirty := 30
+
+	// Export from the source project
+	srcProjects := NewMockProjectStore()
+	srcProjects.On("GetProjectByID", ctx, "proj-src").
+		Return(&supabase.Project{ID: "proj-src", Name: "CLI", OwnerUserID: "owner-src", RetentionDays: &thirty}, nil)
+	srcMembers := newMemberStoreWithRole("proj-src", "owner-src", supabase.MemberRoleOwner)
+	srcMembers.On("ListProjectMembers", ctx, "proj-src").Return([]supabase.ProjectMember{
+		{ProjectID: "proj-src", UserID: "owner-src", Role: supabase.MemberRoleOwner},
+		{ProjectID: "proj-src", UserID: "viewer-src", Role: supabase.MemberRoleViewer},
+		{ProjectID: "proj-src", UserID: "ghost-src", Role: supabase.MemberRoleAdmin},
+	}, nil)
+	keys := NewMockProjectKeyStore()
+	keys.On("ListProjectKeys", ctx, "proj-src").Return([]supabase.ProjectKey{
+		{ID: "key-1", ProjectID: "proj-src", Label: "prod", Env: "prod", PublicKey: "lp_pk_abc", SecretEnc: "secret-hash-value"},
+	}, nil)
+	consents := &MockConsentStore{}
+	consents.On("ListConsents", ctx, "proj-src").Return([]supabase.Consent{
+		{ProjectID: "proj-src", UserIDH: "uh-1", State: "revoked"},
+	}, nil)
+	events := &MockArchiveEventStore{}
+	events.On("ListEvents", ctx, "proj-src", "", archiveBatchSize).Return([]supabase.Event{
+		{ProjectID: "proj-src", EventID: "evt-1", EventType: "perf", Op: "build", Version: "1.0.0", UserIDH: "uh-1", SDKName: "sdk", SDKVersion: "1"},
+		{ProjectID: "proj-src", EventID: "evt-2", EventType: "error", Op: "deploy", Version: "1.0.0", UserIDH: "uh-2", SDKName: "sdk", SDKVersion: "1"},
+	}, nil)
+	users := NewMockUserStore()
+	users.On("GetUserByID", ctx, "owner-src").Return(&supabase.User{ID: "owner-src", Email: "owner@example.com"}, nil)
+	users.On("GetUserByID", ctx, "viewer-src").Return(&supabase.User{ID: "viewer-src", Email: "viewer@example.com"}, nil)
+	users.On("GetUserByID", ctx, "ghost-src").Return(nil, errors.New("user not found"))
+	audit := &MockAuditLogStore{}
+	audit.On("CreateAuditLog", ctx, mock.AnythingOfType("supabase.AuditLog")).Return(nil)
+
+	exportCfg := ProjectArchiveHandlerConfig{
+		ProjectStore: srcProjects, MemberStore: srcMembers, KeyStore: keys, ConsentStore: consents,
+		EventStore: events, UserStore: users, AuditStore: audit, MaxImportBytes: 1 << 20,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/proj-src/export", "", "proj-src", "owner-src")
+	ExportProjectHandler(exportCfg)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	exported := w.Body.Bytes()
+	assert.NotContains(t, string(exported), "secret-hash-value")
+
+	ar, err := archive.NewReader(bytes.NewReader(exported), int64(len(exported)))
+	require.NoError(t, err)
+	assert.Equal(t, archive.Version, ar.Manifest.Version)
+	assert.Equal(t, "proj-src", ar.Manifest.SourceProjectID)
+	assert.Equal(t, map[string]int{"project.ndjson": 1, "members.ndjson": 3, "keys.ndjson": 1, "consent.ndjson": 1, "events.ndjson": 2}, ar.Manifest.Counts)
+
+	// Import into the destination project
+	dstProjects := NewMockProjectStore()
+	dstProjects.On("GetProjectByID", ctx, "proj-dst").
+		Return(&supabase.Project{ID: "proj-dst", Name: "CLI copy", OwnerUserID: "owner-dst"}, nil)
+	dstProjects.On("UpdateProject", ctx, "proj-dst", mock.MatchedBy(func(p supabase.UpdateProjectParams) bool {
+		return p.Name == nil && p.RetentionDays != nil && *p.RetentionDays == 30
+	})).Return(&supabase.Project{ID: "proj-dst"}, nil)
+	dstMembers := newMemberStoreWithRole("proj-dst", "owner-dst", supabase.MemberRoleOwner)
+	dstMembers.On("GetProjectMember", ctx, "proj-dst", "viewer-dst").Return(nil, errors.New("project member not found"))
+	dstUsers := NewMockUserStore()
+	dstUsers.On("GetUserIDByEmail", ctx, "owner@example.com").Return("owner-dst", nil)
+	dstUsers.On("GetUserIDByEmail", ctx, "viewer@example.com").Return("viewer-dst", nil)
+	dstUsers.On("GetUserByID", ctx, "ghost-src").Return(nil, errors.New("user not found"))
+	dstConsents := &MockConsentStore{}
+	dstConsents.On("ImportConsents", ctx, "proj-dst", mock.MatchedBy(func(c []supabase.Consent) bool {
+		return len(c) == 1 && c[0].ProjectID == "proj-dst" && c[0].State == "revoked"
+	})).Return(1, nil)
+	dstEvents := &MockArchiveEventStore{}
+	dstEvents.On("InsertEvents", ctx, mock.MatchedBy(func(e []supabase.Event) bool {
+		return len(e) == 2 && e[0].ProjectID == "proj-dst" && e[1].ProjectID == "proj-dst" && e[0].EventID == "evt-1"
+	})).Return(nil)
+
+	importCfg := ProjectArchiveHandlerConfig{
+		ProjectStore: dstProjects, MemberStore: dstMembers, KeyStore: NewMockProjectKeyStore(), ConsentStore: dstConsents,
+		EventStore: dstEvents, UserStore: dstUsers, AuditStore: audit, MaxImportBytes: 1 << 20,
+	}
+
+	w = httptest.NewRecorder()
+	c = newTokenContext(w, http.MethodPost, "/api/v1/projects/proj-dst/import", string(exported), "proj-dst", "owner-dst")
+	ImportProjectHandler(importCfg)(c)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result ImportProjectResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, ImportProjectResponse{
+		ProjectID:        "proj-dst",
+		ArchiveVersion:   archive.Version,
+		SettingsApplied:  true,
+		MembersSkipped:   []string{"viewer@example.com", "ghost-src"},
+		ConsentsImported: 1,
+		EventsImported:   2,
+		KeysNotImported:  1,
+	}, result)
+
+	dstProjects.AssertExpectations(t)
+	dstMembers.AssertExpectations(t)
+	dstMembers.AssertNotCalled(t, "AddProjectMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	dstConsents.AssertExpectations(t)
+	dstEvents.AssertExpectations(t)
+	audit.AssertNumberOfCalls(t, "CreateAuditLog", 2)
+}
+
+// TestExportProjectHandler_AdminForbidden tests that only the owner can export
+func TestExportProjectHandler_AdminForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := ProjectArchiveHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/export", "", memberTestProjectID, "admin-user")
+	ExportProjectHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// TestImportProjectHandler_InvalidArchive tests that uploads which are not export archives are rejected
+func TestImportProjectHandler_InvalidArchive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// An archive written by a future release
+	var future bytes.Buffer
+	zw := zip.NewWriter(&future)
+	f, err := zw.Create(archive.FileManifest)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(f).Encode(archive.Manifest{Format: archive.Format, Version: archive.Version + 1}))
+	require.NoError(t, zw.Close())
+
+	for name, body := range map[string]string{
+		"not a zip":      "hello",
+		"future version": future.String(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := ProjectArchiveHandlerConfig{
+				ProjectStore:   newMemberTestProjectStore(),
+				MemberStore:    newMemberStoreWithRole(memberTestProjectID, memberTestOwnerID, supabase.MemberRoleOwner),
+				MaxImportBytes: 1 << 20,
+			}
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/import", body, memberTestProjectID, memberTestOwnerID)
+			ImportProjectHandler(cfg)(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "Invalid export archive")
+		})
+	}
+}
+
+// TestImportProjectHandler_UnknownMemberRole tests that a member role this instance does not
+// know is rejected as a malformed archive
+func TestImportProjectHandler_UnknownMemberRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var body bytes.Buffer
+	aw := archive.NewWriter(&body, "proj-src", time.Now())
+	require.NoError(t, aw.Create(archive.FileMembers))
+	require.NoError(t, aw.Write(archive.MemberRecord{UserID: "u-1", Email: "a@example.com", Role: supabase.MemberRoleViewer}))
+	require.NoError(t, aw.Write(archive.MemberRecord{UserID: "u-2", Email: "b@example.com", Role: "superuser"}))
+	require.NoError(t, aw.Close())
+
+	users := NewMockUserStore()
+	users.On("GetUserIDByEmail", mock.Anything, "a@example.com").Return("", errors.New("user not found"))
+
+	cfg := ProjectArchiveHandlerConfig{
+		ProjectStore:   newMemberTestProjectStore(),
+		MemberStore:    newMemberStoreWithRole(memberTestProjectID, memberTestOwnerID, supabase.MemberRoleOwner),
+		UserStore:      users,
+		MaxImportBytes: 1 << 20,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/import", body.String(), memberTestProjectID, memberTestOwnerID)
+	ImportProjectHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "members.ndjson record 2")
+}
+
+// TestImportProjectHandler_KeepsNewerConsent tests that archive consent is imported with its own
+// updated_at and that rows the store kept because the project holds newer state are not counted
+func TestImportProjectHandler_KeepsNewerConsent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exportedAt := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	granted := exportedAt.Add(-24 * time.Hour)
+
+	var body bytes.Buffer
+	aw := archive.NewWriter(&body, "proj-src", exportedAt)
+	require.NoError(t, aw.Create(archive.FileConsent))
+	// uh-revoked revoked consent in the target project after the export was taken
+	require.NoError(t, aw.Write(archive.ConsentRecord{UserIDH: "uh-revoked", State: "granted", UpdatedAt: granted}))
+	require.NoError(t, aw.Write(archive.ConsentRecord{UserIDH: "uh-new", State: "granted", UpdatedAt: granted}))
+	require.NoError(t, aw.Close())
+
+	consents := &MockConsentStore{}
+	consents.On("ImportConsents", mock.Anything, memberTestProjectID, mock.MatchedBy(func(c []supabase.Consent) bool {
+		return len(c) == 2 && c[0].UserIDH == "uh-revoked" && c[0].State == "granted" && c[0].UpdatedAt.Equal(granted)
+	})).Return(1, nil)
+	audit := &MockAuditLogStore{}
+	audit.On("CreateAuditLog", mock.Anything, mock.AnythingOfType("supabase.AuditLog")).Return(nil)
+
+	cfg := ProjectArchiveHandlerConfig{
+		ProjectStore:   newMemberTestProjectStore(),
+		MemberStore:    newMemberStoreWithRole(memberTestProjectID, memberTestOwnerID, supabase.MemberRoleOwner),
+		ConsentStore:   consents,
+		AuditStore:     audit,
+		MaxImportBytes: 1 << 20,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/import", body.String(), memberTestProjectID, memberTestOwnerID)
+	ImportProjectHandler(cfg)(c)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result ImportProjectResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 1, result.ConsentsImported)
+	consents.AssertExpectations(t)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// IngestEvent is a single event as sent by SDKs. It carries exactly one of UserIDH, hashed by the
+// SDK, and UserID, a raw identifier hashed with the project's salt before storage.
+type IngestEvent struct {
+	EventID     string          `json:"event_id" binding:"required,max=128"`
+	EventType   string          `json:"event_type" binding:"required,oneof=error perf user_action"`
+	EventTS     time.Time       `json:"event_ts" binding:"required"`
+	Op          string          `json:"op" binding:"required,max=256"`
+	Variant     *string         `json:"variant"`
+	Surface     *string         `json:"surface"`
+	Version     string          `json:"version" binding:"required,max=64"`
+	ArgsSig     *string         `json:"args_sig"`
+	ArgsCount   *int            `json:"args_count"`
+	Success     *bool           `json:"success"`
+	Severity    *string         `json:"severity" binding:"omitempty,oneof=warn error fatal"`
+	Code        *string         `json:"code"`
+	Message     *string         `json:"message"`
+	Stack       *string         `json:"stack"`
+	DurationMS  *int            `json:"duration_ms" binding:"omitempty,min=0"`
+	UserIDH     string          `json:"user_id_h" binding:"omitempty,max=128"`
+	UserID      string          `json:"user_id" binding:"omitempty,max=256"`
+	SessionID   *string         `json:"session_id"`
+	TraceID     *string         `json:"trace_id"`
+	Payload     json.RawMessage `json:"payload"`
+	SDKName     string          `json:"sdk_name" binding:"required,max=64"`
+	SDKVersion  string          `json:"sdk_version" binding:"required,max=64"`
+	SDKLanguage *string         `json:"sdk_language"`
+	SDKRuntime  *string         `json:"sdk_runtime"`
+	SDKPayload  json.RawMessage `json:"sdk_payload"`
+}
+
+// IngestEventsRequest matches the OpenAPI schema (at most 100 events per batch)
+type IngestEventsRequest struct {
+	Events []IngestEvent `json:"events" binding:"required,min=1,max=100,dive"`
+}
+
+// IngestEventsResponse matches the OpenAPI schema
+type IngestEventsResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+// IngestEventsHandler handles POST /ingest/v1/events
+// Raw user_id values are replaced by their salted hash before storage and never persisted.
+func IngestEventsHandler(store EventStore, ids UserIDPseudonymizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) ensure project key (injected by project key middleware)
+		keyAny, ok := c.Get(auth.ContextKeyProjectKey)
+		if !ok {
+			apiErr := errors.NewAPIError(errors.ErrInvalidProjectKey)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		key, ok := keyAny.(*supabase.ProjectKey)
+		if !ok || key.ProjectID == "" {
+			apiErr := errors.NewAPIError(errors.ErrInvalidProjectKey)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req IngestEventsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		for _, e := range req.Events {
+			if (e.UserIDH == "") == (e.UserID == "") {
+				apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Each event needs exactly one of user_id_h and user_id")
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+		}
+
+		// 3) Bind every event to the key's project and stamp the key's env
+		events := make([]supabase.Event, 0, len(req.Events))
+		for _, e := range req.Events {
+			userIDH := e.UserIDH
+			if e.UserID != "" {
+				h, err := ids.UserIDH(c.Request.Context(), key.ProjectID, e.UserID)
+				if err != nil {
+					log.Printf("UserIDH error: project %s: %s", key.ProjectID, err.Error())
+					apiErr := errors.NewAPIError(errors.ErrInternalError)
+					c.JSON(apiErr.StatusCode(), apiErr)
+					return
+				}
+				userIDH = h
+			}
+
+			events = append(events, supabase.Event{
+				ProjectID:   key.ProjectID,
+				EventID:     e.EventID,
+				Env:         key.Env,
+				EventType:   e.EventType,
+				EventTS:     e.EventTS,
+				Op:          e.Op,
+				Variant:     e.Variant,
+				Surface:     e.Surface,
+				Version:     e.Version,
+				ArgsSig:     e.ArgsSig,
+				ArgsCount:   e.ArgsCount,
+				Success:     e.Success,
+				Severity:    e.Severity,
+				Code:        e.Code,
+				Message:     e.Message,
+				Stack:       e.Stack,
+				DurationMS:  e.DurationMS,
+				UserIDH:     userIDH,
+				SessionID:   e.SessionID,
+				TraceID:     e.TraceID,
+				Payload:     e.Payload,
+				SDKName:     e.SDKName,
+				SDKVersion:  e.SDKVersion,
+				SDKLanguage: e.SDKLanguage,
+				SDKRuntime:  e.SDKRuntime,
+				SDKPayload:  e.SDKPayload,
+			})
+		}
+
+		// 4) Store events
+		if err := store.InsertEvents(c.Request.Context(), events); err != nil {
+			log.Printf("InsertEvents error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, IngestEventsResponse{
+			Accepted: len(events),
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEventStore implements handlers.EventStore for testing.
+type MockEventStore struct {
+	mock.Mock
+}
+
+// NewMockEventStore creates a new mock EventStore.
+func NewMockEventStore() *MockEventStore {
+	return &MockEventStore{}
+}
+
+// InsertEvents mocks EventStore.InsertEvents.
+func (m *MockEventStore) InsertEvents(ctx context.Context, events []supabase.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+// MockUserIDPseudonymizer implements handlers.UserIDPseudonymizer for testing.
+type MockUserIDPseudonymizer struct {
+	mock.Mock
+}
+
+func (m *MockUserIDPseudonymizer) UserIDH(ctx context.Context, projectID, userID string) (string, error) {
+	args := m.Called(ctx, projectID, userID)
+	return args.String(0), args.Error(1)
+}
+
+const validIngestBody = `{"events":[{
+	"event_id":"evt-1",
+	"event_type":"user_action",
+	"event_ts":"2026-01-02T03:04:05Z",
+	"op":"build",
+	"version":"1.2.3",
+	"user_id_h":"u-hash",
+	"sdk_name":"libpulse-go",
+	"sdk_version":"0.1.0"
+}]}`
+
+// newIngestContext builds a test context with the given project key in context
+func newIngestContext(w *httptest.ResponseRecorder, body string, key *supabase.ProjectKey) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/ingest/v1/events", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if key != nil {
+		c.Set(auth.ContextKeyProjectKey, key)
+	}
+	return c
+}
+
+// TestIngestEventsHandler_Success tests that events are bound to the key's project and env
+func TestIngestEventsHandler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := NewMockEventStore()
+
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-123", Env: "staging", Scopes: []string{"ingest"}}
+
+	mockStore.On("InsertEvents", mock.Anything, mock.MatchedBy(func(events []supabase.Event) bool {
+		return len(events) == 1 && events[0].ProjectID == "proj-123" && events[0].Env == "staging" && events[0].Op == "build"
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	c := newIngestContext(w, validIngestBody, key)
+
+	handler := IngestEventsHandler(mockStore, &MockUserIDPseudonymizer{})
+	handler(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"accepted":1`)
+	mockStore.AssertExpectations(t)
+}
+
+// TestIngestEventsHandler_NoKey tests a request without an authenticated key
+func TestIngestEventsHandler_NoKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := NewMockEventStore()
+
+	w := httptest.NewRecorder()
+	c := newIngestContext(w, validIngestBody, nil)
+
+	handler := IngestEventsHandler(mockStore, &MockUserIDPseudonymizer{})
+	handler(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_project_key")
+	mockStore.AssertNotCalled(t, "InsertEvents", mock.Anything, mock.Anything)
+}
+
+// TestIngestEventsHandler_InvalidEvent tests validation of event fields
+func TestIngestEventsHandler_InvalidEvent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := NewMockEventStore()
+
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-123", Scopes: []string{"ingest"}}
+
+	w := httptest.NewRecorder()
+	body := `{"events":[{"event_id":"evt-1","event_type":"click","event_ts":"2026-01-02T03:04:05Z","op":"build","version":"1.2.3","user_id_h":"u","sdk_name":"go","sdk_version":"0.1.0"}]}`
+	c := newIngestContext(w, body, key)
+
+	handler := IngestEventsHandler(mockStore, &MockUserIDPseudonymizer{})
+	handler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockStore.AssertNotCalled(t, "InsertEvents", mock.Anything, mock.Anything)
+}
+
+// TestIngestEventsHandler_DatabaseError tests error handling on insert failure
+func TestIngestEventsHandler_DatabaseError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := NewMockEventStore()
+
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-123", Scopes: []string{"ingest"}}
+
+	mockStore.On("InsertEvents", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+	w := httptest.NewRecorder()
+	c := newIngestContext(w, validIngestBody, key)
+
+	handler := IngestEventsHandler(mockStore, &MockUserIDPseudonymizer{})
+	handler(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "internal_error")
+	mockStore.AssertExpectations(t)
+}
+
+// TestIngestEventsHandler_RawUserID tests that raw user ids are replaced by their project-salted hash
+func TestIngestEventsHandler_RawUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := NewMockEventStore()
+	ids := &MockUserIDPseudonymizer{}
+
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-123", Env: "prod", Scopes: []string{"ingest"}}
+
+	ids.On("UserIDH", mock.Anything, "proj-123", "alice@example.com").Return("salted-hash", nil)
+	mockStore.On("InsertEvents", mock.Anything, mock.MatchedBy(func(events []supabase.Event) bool {
+		return len(events) == 2 && events[0].UserIDH == "salted-hash" && events[1].UserIDH == "u-hash"
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	body := `{"events":[
+		{"event_id":"evt-1","event_type":"perf","event_ts":"2026-01-02T03:04:05Z","op":"build","version":"1.2.3","user_id":"alice@example.com","sdk_name":"go","sdk_version":"0.1.0"},
+		{"event_id":"evt-2","event_type":"perf","event_ts":"2026-01-02T03:04:06Z","op":"build","version":"1.2.3","user_id_h":"u-hash","sdk_name":"go","sdk_version":"0.1.0"}
+	]}`
+	c := newIngestContext(w, body, key)
+
+	IngestEventsHandler(mockStore, ids)(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockStore.AssertExpectations(t)
+	ids.AssertExpectations(t)
+}
+
+// TestIngestEventsHandler_UserIdentifierRequired tests that exactly one user identifier is accepted
+func TestIngestEventsHandler_UserIdentifierRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-123", Scopes: []string{"ingest"}}
+
+	for name, user := range map[string]string{
+		"neither": ``,
+		"both":    `"user_id":"alice","user_id_h":"u-hash",`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockStore := NewMockEventStore()
+			w := httptest.NewRecorder()
+			body := `{"events":[{"event_id":"evt-1","event_type":"perf","event_ts":"2026-01-02T03:04:05Z","op":"build","version":"1.2.3",` + user + `"sdk_name":"go","sdk_version":"0.1.0"}]}`
+			c := newIngestContext(w, body, key)
+
+			IngestEventsHandler(mockStore, &MockUserIDPseudonymizer{})(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockStore.AssertNotCalled(t, "InsertEvents", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestRequireKeyScope tests that keys without the route scope are rejected with the scope named
+func TestRequireKeyScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.POST("/ingest/v1/events", func(c *gin.Context) {
+		c.Set(auth.ContextKeyProjectKey, &supabase.ProjectKey{ProjectID: "proj-123", Scopes: []string{"read:stats"}})
+		c.Next()
+	}, auth.RequireKeyScope(auth.ScopeIngest), func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ingest/v1/events", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "insufficient_scope")
+	assert.Contains(t, w.Body.String(), "ingest")
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/notify"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// InvitationHandlerConfig holds the dependencies of the invitation handlers
+type InvitationHandlerConfig struct {
+	ProjectStore    ProjectStore
+	MemberStore     ProjectMemberStore
+	InvitationStore InvitationStore
+	Notifier        notify.Notifier
+	// AcceptURL is the web app page that redeems invitations; the token is appended as ?token=
+	AcceptURL string
+	// TTL is how long an invitation can be accepted
+	TTL time.Duration
+}
+
+// CreateInvitationRequest matches the OpenAPI schema
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+	Role  string `json:"role" binding:"required,oneof=viewer admin owner"`
+}
+
+// InvitationTokenRequest matches the OpenAPI schema
+type InvitationTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// InvitationResponse matches the OpenAPI Invitation schema (never includes the token)
+type InvitationResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ListInvitationsResponse matches the OpenAPI schema
+type ListInvitationsResponse struct {
+	Invitations []InvitationResponse `json:"invitations"`
+}
+
+// AcceptInvitationResponse matches the OpenAPI schema
+type AcceptInvitationResponse struct {
+	ProjectID string `json:"project_id"`
+	Role      string `json:"role"`
+}
+
+func newInvitationResponse(invitation supabase.ProjectInvitation) InvitationResponse {
+	return InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		InvitedBy: invitation.InvitedBy,
+		CreatedAt: invitation.CreatedAt,
+		ExpiresAt: invitation.ExpiresAt,
+	}
+}
+
+// CreateInvitationHandler handles POST /api/v1/projects/{id}/invitations
+// The invitation token is only delivered by email.
+func CreateInvitationHandler(cfg InvitationHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req CreateInvitationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and the caller may grant the role
+		project, actorRole, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+		if !canManageMember(actorRole, "", req.Role) {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only owners can grant the owner role")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) Generate the token; only its hash is stored
+		token, err := crypto.GenerateInvitationToken()
+		if err != nil {
+			log.Printf("Failed to generate invitation token: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		invitation, err := cfg.InvitationStore.CreateInvitation(c.Request.Context(), supabase.CreateInvitationParams{
+			ProjectID: project.ID,
+			Email:     strings.ToLower(req.Email),
+			Role:      req.Role,
+			TokenHash: crypto.HashSecret(token),
+			InvitedBy: claims.Subject,
+			ExpiresAt: time.Now().Add(cfg.TTL),
+		})
+		if err != nil || invitation == nil {
+			if err != nil {
+				log.Printf("CreateInvitation error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Email the invitee; without the email the invitation is unusable, so undo it
+		notice := notify.InvitationNotice{
+			Email:       invitation.Email,
+			ProjectName: project.Name,
+			Role:        invitation.Role,
+			AcceptURL:   cfg.AcceptURL + "?token=" + url.QueryEscape(token),
+			ExpiresAt:   invitation.ExpiresAt,
+		}
+		if err := cfg.Notifier.NotifyInvitation(c.Request.Context(), notice); err != nil {
+			log.Printf("notify invitation error: %s", err.Error())
+			if err := cfg.InvitationStore.RevokeInvitation(c.Request.Context(), project.ID, invitation.ID); err != nil {
+				log.Printf("RevokeInvitation error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError).WithMessage("Failed to send the invitation email")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.JSON(http.StatusCreated, newInvitationResponse(*invitation))
+	}
+}
+
+// ListInvitationsHandler handles GET /api/v1/projects/{id}/invitations
+func ListInvitationsHandler(cfg InvitationHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 3) List pending invitations
+		invitations, err := cfg.InvitationStore.ListPendingInvitations(c.Request.Context(), project.ID, time.Now())
+		if err != nil {
+			log.Printf("ListPendingInvitations error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		response := ListInvitationsResponse{Invitations: make([]InvitationResponse, 0, len(invitations))}
+		for _, invitation := range invitations {
+			response.Invitations = append(response.Invitations, newInvitationResponse(invitation))
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// RevokeInvitationHandler handles DELETE /api/v1/projects/{id}/invitations/{invitationId}
+func RevokeInvitationHandler(cfg InvitationHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 3) Revoke the invitation
+		if err := cfg.InvitationStore.RevokeInvitation(c.Request.Context(), project.ID, c.Param("invitationId")); err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "not found") {
+				apiErr := errors.NewAPIError(errors.ErrNotFound)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			log.Printf("RevokeInvitation error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// AcceptInvitationHandler handles POST /api/v1/invitations/accept
+// The token is redeemed once, by the authenticated user, who becomes a member with the invited role.
+func AcceptInvitationHandler(cfg InvitationHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		tokenHash, ok := bindInvitationToken(c)
+		if !ok {
+			return
+		}
+
+		// 3) Claim the invitation for the caller
+		invitation, err := cfg.InvitationStore.ClaimInvitation(c.Request.Context(), tokenHash, claims.Subject, time.Now())
+		if err != nil || invitation == nil {
+			writeInvitationLookupError(c, "ClaimInvitation", err)
+			return
+		}
+
+		// 4) Add the caller as a member
+		member, err := cfg.MemberStore.AddProjectMember(c.Request.Context(), invitation.ProjectID, claims.Subject, invitation.Role)
+		if err != nil {
+			errMsg := strings.ToLower(err.Error())
+			if strings.Contains(errMsg, "duplicate") || strings.Contains(errMsg, "23505") {
+				apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("You are already a member of this project")
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			log.Printf("AddProjectMember error: %s", err.Error())
+			if err := cfg.InvitationStore.ReleaseInvitation(c.Request.Context(), invitation.ID); err != nil {
+				log.Printf("ReleaseInvitation error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.JSON(http.StatusOK, AcceptInvitationResponse{
+			ProjectID: member.ProjectID,
+			Role:      member.Role,
+		})
+	}
+}
+
+// DeclineInvitationHandler handles POST /api/v1/invitations/decline
+func DeclineInvitationHandler(cfg InvitationHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		if _, ok := claimsFromContext(c); !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		tokenHash, ok := bindInvitationToken(c)
+		if !ok {
+			return
+		}
+
+		// 3) Decline the invitation
+		invitation, err := cfg.InvitationStore.DeclineInvitation(c.Request.Context(), tokenHash, time.Now())
+		if err != nil || invitation == nil {
+			writeInvitationLookupError(c, "DeclineInvitation", err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// bindInvitationToken parses the token from the request body and returns its hash.
+// Malformed tokens are rejected before any database lookup.
+func bindInvitationToken(c *gin.Context) (string, bool) {
+	var req InvitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apiErr := errors.NewAPIError(errors.ErrBadRequest)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return "", false
+	}
+
+	if err := crypto.ValidateInvitationToken(req.Token); err != nil {
+		apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("Invitation is invalid, expired or already used")
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return "", false
+	}
+
+	return crypto.HashSecret(req.Token), true
+}
+
+// writeInvitationLookupError maps errors from resolving a pending invitation by token
+func writeInvitationLookupError(c *gin.Context, op string, err error) {
+	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+		log.Printf("%s error: %s", op, err.Error())
+		apiErr := errors.NewAPIError(errors.ErrInternalError)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+
+	apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("Invitation is invalid, expired or already used")
+	c.JSON(apiErr.StatusCode(), apiErr)
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// InvitationStore abstracts project invitation data access for handlers, enabling dependency injection and unit testing.
+type InvitationStore interface {
+	CreateInvitation(ctx context.Context, params supabase.CreateInvitationParams) (*supabase.ProjectInvitation, error)
+	ListPendingInvitations(ctx context.Context, projectID string, now time.Time) ([]supabase.ProjectInvitation, error)
+	RevokeInvitation(ctx context.Context, projectID, invitationID string) error
+	ClaimInvitation(ctx context.Context, tokenHash, userID string, now time.Time) (*supabase.ProjectInvitation, error)
+	DeclineInvitation(ctx context.Context, tokenHash string, now time.Time) (*supabase.ProjectInvitation, error)
+	ReleaseInvitation(ctx context.Context, invitationID string) error
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/notify"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockInvitationStore implements handlers.InvitationStore for testing.
+type MockInvitationStore struct {
+	mock.Mock
+}
+
+func (m *MockInvitationStore) CreateInvitation(ctx context.Context, params supabase.CreateInvitationParams) (*supabase.ProjectInvitation, error) {
+	args := m.Called(ctx, params)
+	invitation := args.Get(0)
+	if invitation == nil {
+		return nil, args.Error(1)
+	}
+	return invitation.(*supabase.ProjectInvitation), args.Error(1)
+}
+
+func (m *MockInvitationStore) ListPendingInvitations(ctx context.Context, projectID string, now time.Time) ([]supabase.ProjectInvitation, error) {
+	args := m.Called(ctx, projectID, now)
+	invitations := args.Get(0)
+	if invitations == nil {
+		return nil, args.Error(1)
+	}
+	return invitations.([]supabase.ProjectInvitation), args.Error(1)
+}
+
+func (m *MockInvitationStore) RevokeInvitation(ctx context.Context, projectID, invitationID string) error {
+	args := m.Called(ctx, projectID, invitationID)
+	return args.Error(0)
+}
+
+func (m *MockInvitationStore) ClaimInvitation(ctx context.Context, tokenHash, userID string, now time.Time) (*supabase.ProjectInvitation, error) {
+	args := m.Called(ctx, tokenHash, userID, now)
+	invitation := args.Get(0)
+	if invitation == nil {
+		return nil, args.Error(1)
+	}
+	return invitation.(*supabase.ProjectInvitation), args.Error(1)
+}
+
+func (m *MockInvitationStore) DeclineInvitation(ctx context.Context, tokenHash string, now time.Time) (*supabase.ProjectInvitation, error) {
+	args := m.Called(ctx, tokenHash, now)
+	invitation := args.Get(0)
+	if invitation == nil {
+		return nil, args.Error(1)
+	}
+	return invitation.(*supabase.ProjectInvitation), args.Error(1)
+}
+
+func (m *MockInvitationStore) ReleaseInvitation(ctx context.Context, invitationID string) error {
+	args := m.Called(ctx, invitationID)
+	return args.Error(0)
+}
+
+const invitationAcceptURL = "https://app.example.com/invitations/accept"
+
+// newInvitationConfig wires the members test project into an invitation handler config
+func newInvitationConfig(memberStore *MockProjectMemberStore, invitationStore *MockInvitationStore, notifier *MockNotifier) InvitationHandlerConfig {
+	return InvitationHandlerConfig{
+		ProjectStore:    newMemberTestProjectStore(),
+		MemberStore:     memberStore,
+		InvitationStore: invitationStore,
+		Notifier:        notifier,
+		AcceptURL:       invitationAcceptURL,
+		TTL:             7 * 24 * time.Hour,
+	}
+}
+
+// TestCreateInvitationHandler_Success tests that the token is only emailed and only its hash is stored
+func TestCreateInvitationHandler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	memberStore := newMemberStoreWithRole(memberTestProjectID, "admin-invite", supabase.MemberRoleAdmin)
+	invitationStore := &MockInvitationStore{}
+	notifier := &MockNotifier{}
+
+	var storedHash string
+	invitationStore.On("CreateInvitation", mock.Anything, mock.MatchedBy(func(p supabase.CreateInvitationParams) bool {
+		storedHash = p.TokenHash
+		return p.ProjectID == memberTestProjectID && p.Email == "dev@example.com" &&
+			p.Role == supabase.MemberRoleViewer && p.InvitedBy == "admin-invite" && p.ExpiresAt.After(time.Now())
+	})).Return(&supabase.ProjectInvitation{
+		ID: "inv-1", ProjectID: memberTestProjectID, Email: "dev@example.com", Role: supabase.MemberRoleViewer,
+		InvitedBy: "admin-invite", ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	var sentToken string
+	notifier.On("NotifyInvitation", mock.Anything, mock.MatchedBy(func(n notify.InvitationNotice) bool {
+		sentToken = strings.TrimPrefix(n.AcceptURL, invitationAcceptURL+"?token=")
+		return n.Email == "dev@example.com" && n.ProjectName == "members"
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/invitations",
+		`{"email":"Dev@Example.com","role":"viewer"}`, memberTestProjectID, "admin-invite")
+
+	CreateInvitationHandler(newInvitationConfig(memberStore, invitationStore, notifier))(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"inv-1"`)
+	assert.NoError(t, crypto.ValidateInvitationToken(sentToken))
+	assert.Equal(t, crypto.HashSecret(sentToken), storedHash)
+	assert.NotContains(t, w.Body.String(), sentToken)
+	notifier.AssertExpectations(t)
+}
+
+// TestCreateInvitationHandler_Rejections tests validation, role escalation and delivery failures
+func TestCreateInvitationHandler_Rejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		actorRole string
+		body      string
+		notifyErr error
+		want      int
+	}{
+		{"invalid email", supabase.MemberRoleAdmin, `{"email":"nope","role":"viewer"}`, nil, http.StatusBadRequest},
+		{"unknown role", supabase.MemberRoleAdmin, `{"email":"dev@example.com","role":"superuser"}`, nil, http.StatusBadRequest},
+		{"viewer invites", supabase.MemberRoleViewer, `{"email":"dev@example.com","role":"viewer"}`, nil, http.StatusForbidden},
+		{"admin invites owner", supabase.MemberRoleAdmin, `{"email":"dev@example.com","role":"owner"}`, nil, http.StatusForbidden},
+		{"email fails", supabase.MemberRoleAdmin, `{"email":"dev@example.com","role":"viewer"}`, errors.New("smtp down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberStore := newMemberStoreWithRole(memberTestProjectID, "actor", tt.actorRole)
+			invitationStore := &MockInvitationStore{}
+			invitationStore.On("CreateInvitation", mock.Anything, mock.Anything).
+				Return(&supabase.ProjectInvitation{ID: "inv-1", ProjectID: memberTestProjectID}, nil)
+			invitationStore.On("RevokeInvitation", mock.Anything, memberTestProjectID, "inv-1").Return(nil)
+			notifier := &MockNotifier{}
+			notifier.On("NotifyInvitation", mock.Anything, mock.Anything).Return(tt.notifyErr)
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/invitations",
+				tt.body, memberTestProjectID, "actor")
+
+			CreateInvitationHandler(newInvitationConfig(memberStore, invitationStore, notifier))(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.notifyErr != nil {
+				invitationStore.AssertCalled(t, "RevokeInvitation", mock.Anything, memberTestProjectID, "inv-1")
+			}
+		})
+	}
+}
+
+// TestAcceptInvitationHandler tests redeeming invitations
+func TestAcceptInvitationHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	token, err := crypto.GenerateInvitationToken()
+	assert.NoError(t, err)
+	invitation := &supabase.ProjectInvitation{ID: "inv-accept", ProjectID: memberTestProjectID, Role: supabase.MemberRoleAdmin}
+
+	tests := []struct {
+		name     string
+		token    string
+		claimErr error
+		addErr   error
+		want     int
+	}{
+		{"success", token, nil, nil, http.StatusOK},
+		{"malformed token", "lpinv_nope", nil, nil, http.StatusNotFound},
+		{"used or expired", token, errors.New("invitation not found"), nil, http.StatusNotFound},
+		{"already member", token, nil, errors.New(`{"code":"23505","message":"duplicate key value"}`), http.StatusConflict},
+		{"member insert fails", token, nil, errors.New("connection reset"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invitationStore := &MockInvitationStore{}
+			if tt.claimErr != nil {
+				invitationStore.On("ClaimInvitation", mock.Anything, crypto.HashSecret(token), "invitee", mock.Anything).Return(nil, tt.claimErr)
+			} else {
+				invitationStore.On("ClaimInvitation", mock.Anything, crypto.HashSecret(token), "invitee", mock.Anything).Return(invitation, nil)
+			}
+			invitationStore.On("ReleaseInvitation", mock.Anything, "inv-accept").Return(nil)
+			memberStore := NewMockProjectMemberStore()
+			if tt.addErr != nil {
+				memberStore.On("AddProjectMember", mock.Anything, memberTestProjectID, "invitee", supabase.MemberRoleAdmin).Return(nil, tt.addErr)
+			} else {
+				memberStore.On("AddProjectMember", mock.Anything, memberTestProjectID, "invitee", supabase.MemberRoleAdmin).
+					Return(&supabase.ProjectMember{ProjectID: memberTestProjectID, UserID: "invitee", Role: supabase.MemberRoleAdmin}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodPost, "/api/v1/invitations/accept", `{"token":"`+tt.token+`"}`, "", "invitee")
+
+			AcceptInvitationHandler(newInvitationConfig(memberStore, invitationStore, &MockNotifier{}))(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusInternalServerError {
+				invitationStore.AssertCalled(t, "ReleaseInvitation", mock.Anything, "inv-accept")
+			} else {
+				invitationStore.AssertNotCalled(t, "ReleaseInvitation", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestDeclineInvitationHandler tests declining pending and unknown invitations
+func TestDeclineInvitationHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pending, _ := crypto.GenerateInvitationToken()
+	used, _ := crypto.GenerateInvitationToken()
+
+	invitationStore := &MockInvitationStore{}
+	invitationStore.On("DeclineInvitation", mock.Anything, crypto.HashSecret(pending), mock.Anything).
+		Return(&supabase.ProjectInvitation{ID: "inv-decline"}, nil)
+	invitationStore.On("DeclineInvitation", mock.Anything, crypto.HashSecret(used), mock.Anything).
+		Return(nil, errors.New("invitation not found"))
+	cfg := newInvitationConfig(NewMockProjectMemberStore(), invitationStore, &MockNotifier{})
+
+	for token, want := range map[string]int{pending: http.StatusNoContent, used: http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		c := newTokenContext(w, http.MethodPost, "/api/v1/invitations/decline", `{"token":"`+token+`"}`, "", "invitee")
+
+		DeclineInvitationHandler(cfg)(c)
+
+		assert.Equal(t, want, c.Writer.Status())
+	}
+}
+
+// TestRevokeInvitationHandler tests that admins can revoke pending invitations
+func TestRevokeInvitationHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	memberStore := newMemberStoreWithRole(memberTestProjectID, "admin-revoke", supabase.MemberRoleAdmin)
+	invitationStore := &MockInvitationStore{}
+	invitationStore.On("RevokeInvitation", mock.Anything, memberTestProjectID, "inv-1").Return(nil)
+	invitationStore.On("RevokeInvitation", mock.Anything, memberTestProjectID, "inv-gone").Return(errors.New("invitation not found"))
+	cfg := newInvitationConfig(memberStore, invitationStore, &MockNotifier{})
+
+	for invitationID, want := range map[string]int{"inv-1": http.StatusNoContent, "inv-gone": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		c := newTokenContext(w, http.MethodDelete, "/api/v1/projects/"+memberTestProjectID+"/invitations/"+invitationID, "", memberTestProjectID, "admin-revoke")
+		c.Params = append(c.Params, gin.Param{Key: "invitationId", Value: invitationID})
+
+		RevokeInvitationHandler(cfg)(c)
+
+		assert.Equal(t, want, c.Writer.Status(), invitationID)
+	}
+}
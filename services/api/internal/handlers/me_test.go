@@ -40,6 +40,12 @@ func (m *MockUserStore) GetUserByID(
 	return user, args.Error(1)
 }
 
+// GetUserIDByEmail mocks UserStore.GetUserIDByEmail.
+func (m *MockUserStore) GetUserIDByEmail(ctx context.Context, email string) (string, error) {
+	args := m.Called(ctx, email)
+	return args.String(0), args.Error(1)
+}
+
 // TestGetCurrentUserHandler_Success tests successful user retrieval
 func TestGetCurrentUserHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// AddProjectMemberRequest matches the OpenAPI schema. Exactly one of user_id and email is set.
+type AddProjectMemberRequest struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email" binding:"omitempty,email"`
+	Role   string `json:"role" binding:"required,oneof=viewer admin owner"`
+}
+
+// UpdateProjectMemberRequest matches the OpenAPI schema
+type UpdateProjectMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer admin owner"`
+}
+
+// ProjectMemberResponse matches the OpenAPI ProjectMember schema
+type ProjectMemberResponse struct {
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListProjectMembersResponse matches the OpenAPI schema
+type ListProjectMembersResponse struct {
+	Members []ProjectMemberResponse `json:"members"`
+}
+
+func newProjectMemberResponse(member supabase.ProjectMember) ProjectMemberResponse {
+	return ProjectMemberResponse{
+		UserID:    member.UserID,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}
+}
+
+// canManageMember reports whether actorRole may move a member from currentRole to newRole.
+// Admins manage viewers and admins; only owners can grant the owner role or touch owners.
+// currentRole is empty for new members, newRole is empty for removals.
+func canManageMember(actorRole, currentRole, newRole string) bool {
+	if actorRole == supabase.MemberRoleOwner {
+		return true
+	}
+	return actorRole == supabase.MemberRoleAdmin &&
+		currentRole != supabase.MemberRoleOwner &&
+		newRole != supabase.MemberRoleOwner
+}
+
+// isLastOwnerError reports whether err is the database guard rejecting removal of the last owner
+func isLastOwnerError(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "at least one owner")
+}
+
+// loadMemberTarget authorizes an admin or owner on the :id project and loads the :userId member.
+// The project's recorded owner can only change through an ownership transfer.
+// It writes the error response and returns false when the request cannot proceed.
+func loadMemberTarget(c *gin.Context, projectStore ProjectStore, memberStore ProjectMemberStore, claims *auth.SupabaseClaims) (*supabase.Project, *supabase.ProjectMember, string, bool) {
+	project, actorRole, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+	if !ok {
+		return nil, nil, "", false
+	}
+
+	userID := c.Param("userId")
+	if userID == "" {
+		apiErr := errors.NewAPIError(errors.ErrBadRequest)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, nil, "", false
+	}
+
+	if userID == project.OwnerUserID {
+		apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("Transfer project ownership before changing the project owner's membership")
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, nil, "", false
+	}
+
+	target, err := memberStore.GetProjectMember(c.Request.Context(), project.ID, userID)
+	if err != nil || target == nil {
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			log.Printf("GetProjectMember error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return nil, nil, "", false
+		}
+		apiErr := errors.NewAPIError(errors.ErrNotFound)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, nil, "", false
+	}
+
+	return project, target, actorRole, true
+}
+
+// ensureOtherOwner checks that the project has an owner besides userID.
+// It writes the error response and returns false otherwise.
+func ensureOtherOwner(c *gin.Context, project *supabase.Project, memberStore ProjectMemberStore, userID string) bool {
+	members, err := memberStore.ListProjectMembers(c.Request.Context(), project.ID)
+	if err != nil {
+		log.Printf("ListProjectMembers error: %s", err.Error())
+		apiErr := errors.NewAPIError(errors.ErrInternalError)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return false
+	}
+
+	for _, member := range members {
+		if member.Role == supabase.MemberRoleOwner && member.UserID != userID {
+			return true
+		}
+	}
+
+	apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("Project must keep at least one owner")
+	c.JSON(apiErr.StatusCode(), apiErr)
+	return false
+}
+
+// writeMemberChangeError maps errors from updating or removing a member
+func writeMemberChangeError(c *gin.Context, op string, err error) {
+	switch {
+	case strings.Contains(strings.ToLower(err.Error()), "not found"):
+		apiErr := errors.NewAPIError(errors.ErrNotFound)
+		c.JSON(apiErr.StatusCode(), apiErr)
+	case isLastOwnerError(err):
+		// Raced with another change; the database guard kept the last owner
+		apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("Project must keep at least one owner")
+		c.JSON(apiErr.StatusCode(), apiErr)
+	default:
+		log.Printf("%s error: %s", op, err.Error())
+		apiErr := errors.NewAPIError(errors.ErrInternalError)
+		c.JSON(apiErr.StatusCode(), apiErr)
+	}
+}
+
+// ListProjectMembersHandler handles GET /api/v1/projects/{id}/members
+func ListProjectMembersHandler(projectStore ProjectStore, memberStore ProjectMemberStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is a member
+		project, _, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleViewer)
+		if !ok {
+			return
+		}
+
+		// 3) List members
+		members, err := memberStore.ListProjectMembers(c.Request.Context(), project.ID)
+		if err != nil {
+			log.Printf("ListProjectMembers error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		response := ListProjectMembersResponse{Members: make([]ProjectMemberResponse, 0, len(members))}
+		for _, member := range members {
+			response.Members = append(response.Members, newProjectMemberResponse(member))
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// AddProjectMemberHandler handles POST /api/v1/projects/{id}/members
+// Members are added by user id or by the email of an existing account.
+func AddProjectMemberHandler(projectStore ProjectStore, memberStore ProjectMemberStore, userStore UserStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req AddProjectMemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if (req.UserID == "") == (req.Email == "") {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Exactly one of user_id and email is required")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and the caller may grant the role
+		project, actorRole, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+		if !canManageMember(actorRole, "", req.Role) {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only owners can grant the owner role")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) Resolve the user
+		userID := req.UserID
+		if req.Email != "" {
+			id, err := userStore.GetUserIDByEmail(c.Request.Context(), req.Email)
+			if err != nil {
+				if strings.Contains(strings.ToLower(err.Error()), "not found") {
+					apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("No user with this email")
+					c.JSON(apiErr.StatusCode(), apiErr)
+					return
+				}
+				log.Printf("GetUserIDByEmail error: %s", err.Error())
+				apiErr := errors.NewAPIError(errors.ErrInternalError)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			userID = id
+		}
+
+		// 5) Add the member
+		member, err := memberStore.AddProjectMember(c.Request.Context(), project.ID, userID, req.Role)
+		if err != nil {
+			errMsg := strings.ToLower(err.Error())
+			switch {
+			case strings.Contains(errMsg, "duplicate") || strings.Contains(errMsg, "23505"):
+				apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("User is already a member of this project")
+				c.JSON(apiErr.StatusCode(), apiErr)
+			case strings.Contains(errMsg, "23503") || strings.Contains(errMsg, "foreign key"):
+				apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("No user with this id")
+				c.JSON(apiErr.StatusCode(), apiErr)
+			case strings.Contains(errMsg, "22p02") || strings.Contains(errMsg, "invalid input syntax"):
+				apiErr := errors.NewAPIError(errors.ErrBadRequest)
+				c.JSON(apiErr.StatusCode(), apiErr)
+			default:
+				log.Printf("AddProjectMember error: %s", err.Error())
+				apiErr := errors.NewAPIError(errors.ErrInternalError)
+				c.JSON(apiErr.StatusCode(), apiErr)
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, newProjectMemberResponse(*member))
+	}
+}
+
+// UpdateProjectMemberHandler handles PATCH /api/v1/projects/{id}/members/{userId}
+func UpdateProjectMemberHandler(projectStore ProjectStore, memberStore ProjectMemberStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req UpdateProjectMemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and load the target member
+		project, target, actorRole, ok := loadMemberTarget(c, projectStore, memberStore, claims)
+		if !ok {
+			return
+		}
+
+		// 4) Check the change is allowed
+		if !canManageMember(actorRole, target.Role, req.Role) {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only owners can change owners or grant the owner role")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if target.Role == supabase.MemberRoleOwner && req.Role != supabase.MemberRoleOwner {
+			if !ensureOtherOwner(c, project, memberStore, target.UserID) {
+				return
+			}
+		}
+
+		// 5) Update the role
+		member, err := memberStore.UpdateProjectMemberRole(c.Request.Context(), project.ID, target.UserID, req.Role)
+		if err != nil {
+			writeMemberChangeError(c, "UpdateProjectMemberRole", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, newProjectMemberResponse(*member))
+	}
+}
+
+// RemoveProjectMemberHandler handles DELETE /api/v1/projects/{id}/members/{userId}
+func RemoveProjectMemberHandler(projectStore ProjectStore, memberStore ProjectMemberStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and load the target member
+		project, target, actorRole, ok := loadMemberTarget(c, projectStore, memberStore, claims)
+		if !ok {
+			return
+		}
+
+		// 3) Check the removal is allowed
+		if !canManageMember(actorRole, target.Role, "") {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only owners can remove owners")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if target.Role == supabase.MemberRoleOwner {
+			if !ensureOtherOwner(c, project, memberStore, target.UserID) {
+				return
+			}
+		}
+
+		// 4) Remove the member
+		if err := memberStore.RemoveProjectMember(c.Request.Context(), project.ID, target.UserID); err != nil {
+			writeMemberChangeError(c, "RemoveProjectMember", err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// ProjectMemberStore abstracts project membership data access for handlers, enabling dependency injection and unit testing.
+type ProjectMemberStore interface {
+	GetProjectMember(ctx context.Context, projectID, userID string) (*supabase.ProjectMember, error)
+	ListProjectMembers(ctx context.Context, projectID string) ([]supabase.ProjectMember, error)
+	AddProjectMember(ctx context.Context, projectID, userID, role string) (*supabase.ProjectMember, error)
+	UpdateProjectMemberRole(ctx context.Context, projectID, userID, role string) (*supabase.ProjectMember, error)
+	RemoveProjectMember(ctx context.Context, projectID, userID string) error
+}
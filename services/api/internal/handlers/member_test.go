@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	memberTestProjectID = "proj-members"
+	memberTestOwnerID   = "owner-members"
+)
+
+// newMemberTestProjectStore returns a project store serving the members test project
+func newMemberTestProjectStore() *MockProjectStore {
+	store := NewMockProjectStore()
+	store.On("GetProjectByID", mock.Anything, memberTestProjectID).
+		Return(&supabase.Project{ID: memberTestProjectID, Name: "members", OwnerUserID: memberTestOwnerID}, nil)
+	return store
+}
+
+// newMemberContext builds a test context for the member endpoints, targeting userID when set
+func newMemberContext(w *httptest.ResponseRecorder, method, body, actorID, userID string) *gin.Context {
+	path := "/api/v1/projects/" + memberTestProjectID + "/members"
+	if userID != "" {
+		path += "/" + userID
+	}
+	c := newTokenContext(w, method, path, body, memberTestProjectID, actorID)
+	if userID != "" {
+		c.Params = append(c.Params, gin.Param{Key: "userId", Value: userID})
+	}
+	return c
+}
+
+// TestAddProjectMemberHandler_ByEmail tests that admins can add members by email
+func TestAddProjectMemberHandler_ByEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	memberStore := newMemberStoreWithRole(memberTestProjectID, "admin-1", supabase.MemberRoleAdmin)
+	memberStore.On("AddProjectMember", mock.Anything, memberTestProjectID, "user-new", supabase.MemberRoleViewer).
+		Return(&supabase.ProjectMember{ProjectID: memberTestProjectID, UserID: "user-new", Role: supabase.MemberRoleViewer}, nil)
+	userStore := NewMockUserStore()
+	userStore.On("GetUserIDByEmail", mock.Anything, "dev@example.com").Return("user-new", nil)
+
+	w := httptest.NewRecorder()
+	c := newMemberContext(w, http.MethodPost, `{"email":"dev@example.com","role":"viewer"}`, "admin-1", "")
+
+	AddProjectMemberHandler(newMemberTestProjectStore(), memberStore, userStore)(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"user_id":"user-new"`)
+	memberStore.AssertExpectations(t)
+}
+
+// TestAddProjectMemberHandler_Rejections tests request validation, escalation and duplicates
+func TestAddProjectMemberHandler_Rejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		addErr error
+		want   int
+	}{
+		{"user_id and email", `{"user_id":"u","email":"dev@example.com","role":"viewer"}`, nil, http.StatusBadRequest},
+		{"neither", `{"role":"viewer"}`, nil, http.StatusBadRequest},
+		{"unknown role", `{"user_id":"u","role":"superuser"}`, nil, http.StatusBadRequest},
+		{"admin grants owner", `{"user_id":"u","role":"owner"}`, nil, http.StatusForbidden},
+		{"duplicate", `{"user_id":"u","role":"viewer"}`, errors.New(`{"code":"23505","message":"duplicate key value"}`), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberStore := newMemberStoreWithRole(memberTestProjectID, "admin-1", supabase.MemberRoleAdmin)
+			memberStore.On("AddProjectMember", mock.Anything, memberTestProjectID, "u", mock.Anything).Return(nil, tt.addErr)
+
+			w := httptest.NewRecorder()
+			c := newMemberContext(w, http.MethodPost, tt.body, "admin-1", "")
+
+			AddProjectMemberHandler(newMemberTestProjectStore(), memberStore, NewMockUserStore())(c)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+// TestUpdateProjectMemberHandler tests role changes and the last-owner rule
+func TestUpdateProjectMemberHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		actorRole string
+		target    supabase.ProjectMember
+		owners    []supabase.ProjectMember
+		body      string
+		want      int
+	}{
+		{
+			name:      "admin promotes viewer",
+			actorRole: supabase.MemberRoleAdmin,
+			target:    supabase.ProjectMember{UserID: "user-2", Role: supabase.MemberRoleViewer},
+			body:      `{"role":"admin"}`,
+			want:      http.StatusOK,
+		},
+		{
+			name:      "admin demotes owner",
+			actorRole: supabase.MemberRoleAdmin,
+			target:    supabase.ProjectMember{UserID: "user-2", Role: supabase.MemberRoleOwner},
+			body:      `{"role":"viewer"}`,
+			want:      http.StatusForbidden,
+		},
+		{
+			name:      "owner demotes co-owner",
+			actorRole: supabase.MemberRoleOwner,
+			target:    supabase.ProjectMember{UserID: "user-2", Role: supabase.MemberRoleOwner},
+			owners:    []supabase.ProjectMember{{UserID: "actor", Role: supabase.MemberRoleOwner}, {UserID: "user-2", Role: supabase.MemberRoleOwner}},
+			body:      `{"role":"admin"}`,
+			want:      http.StatusOK,
+		},
+		{
+			name:      "last owner",
+			actorRole: supabase.MemberRoleOwner,
+			target:    supabase.ProjectMember{UserID: "user-2", Role: supabase.MemberRoleOwner},
+			owners:    []supabase.ProjectMember{{UserID: "user-2", Role: supabase.MemberRoleOwner}},
+			body:      `{"role":"admin"}`,
+			want:      http.StatusConflict,
+		},
+		{
+			name:      "recorded project owner",
+			actorRole: supabase.MemberRoleOwner,
+			target:    supabase.ProjectMember{UserID: memberTestOwnerID, Role: supabase.MemberRoleOwner},
+			body:      `{"role":"admin"}`,
+			want:      http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberStore := newMemberStoreWithRole(memberTestProjectID, "actor", tt.actorRole)
+			target := tt.target
+			target.ProjectID = memberTestProjectID
+			memberStore.On("GetProjectMember", mock.Anything, memberTestProjectID, target.UserID).Return(&target, nil)
+			memberStore.On("ListProjectMembers", mock.Anything, memberTestProjectID).Return(tt.owners, nil)
+			memberStore.On("UpdateProjectMemberRole", mock.Anything, memberTestProjectID, target.UserID, mock.Anything).
+				Return(&supabase.ProjectMember{ProjectID: memberTestProjectID, UserID: target.UserID, Role: "admin"}, nil)
+
+			w := httptest.NewRecorder()
+			c := newMemberContext(w, http.MethodPatch, tt.body, "actor", target.UserID)
+
+			UpdateProjectMemberHandler(newMemberTestProjectStore(), memberStore)(c)
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.want != http.StatusOK {
+				memberStore.AssertNotCalled(t, "UpdateProjectMemberRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestRemoveProjectMemberHandler tests member removal permissions
+func TestRemoveProjectMemberHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		actorRole  string
+		targetRole string
+		want       int
+	}{
+		{"admin removes viewer", supabase.MemberRoleAdmin, supabase.MemberRoleViewer, http.StatusNoContent},
+		{"admin removes owner", supabase.MemberRoleAdmin, supabase.MemberRoleOwner, http.StatusForbidden},
+		{"viewer removes viewer", supabase.MemberRoleViewer, supabase.MemberRoleViewer, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberStore := newMemberStoreWithRole(memberTestProjectID, "actor", tt.actorRole)
+			memberStore.On("GetProjectMember", mock.Anything, memberTestProjectID, "user-2").
+				Return(&supabase.ProjectMember{ProjectID: memberTestProjectID, UserID: "user-2", Role: tt.targetRole}, nil)
+			memberStore.On("RemoveProjectMember", mock.Anything, memberTestProjectID, "user-2").Return(nil)
+
+			w := httptest.NewRecorder()
+			c := newMemberContext(w, http.MethodDelete, "", "actor", "user-2")
+
+			RemoveProjectMemberHandler(newMemberTestProjectStore(), memberStore)(c)
+
+			assert.Equal(t, tt.want, c.Writer.Status(), w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// ownershipTransferTTL is how long the proposed owner has to accept a transfer
+const ownershipTransferTTL = 7 * 24 * time.Hour
+
+// OwnershipTransferHandlerConfig holds the dependencies of the ownership transfer handlers
+type OwnershipTransferHandlerConfig struct {
+	ProjectStore  ProjectStore
+	MemberStore   ProjectMemberStore
+	TransferStore OwnershipTransferStore
+	AuditStore    AuditLogStore
+}
+
+// CreateOwnershipTransferRequest matches the OpenAPI schema
+type CreateOwnershipTransferRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// OwnershipTransferResponse matches the OpenAPI schema
+type OwnershipTransferResponse struct {
+	ID         string    `json:"id"`
+	ProjectID  string    `json:"project_id"`
+	FromUserID string    `json:"from_user_id"`
+	ToUserID   string    `json:"to_user_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// AcceptOwnershipTransferResponse matches the OpenAPI schema
+type AcceptOwnershipTransferResponse struct {
+	ProjectID   string `json:"project_id"`
+	OwnerUserID string `json:"owner_user_id"`
+}
+
+func newOwnershipTransferResponse(transfer supabase.OwnershipTransfer) OwnershipTransferResponse {
+	return OwnershipTransferResponse{
+		ID:         transfer.ID,
+		ProjectID:  transfer.ProjectID,
+		FromUserID: transfer.FromUserID,
+		ToUserID:   transfer.ToUserID,
+		CreatedAt:  transfer.CreatedAt,
+		ExpiresAt:  transfer.ExpiresAt,
+	}
+}
+
+// CreateOwnershipTransferHandler handles POST /api/v1/projects/{id}/ownership-transfer
+// Only the recorded owner can propose a transfer, and only to an admin or owner member.
+// A new proposal replaces any pending one.
+func CreateOwnershipTransferHandler(cfg OwnershipTransferHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req CreateOwnershipTransferRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Only the recorded owner can give the project away
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleOwner)
+		if !ok {
+			return
+		}
+		if project.OwnerUserID != claims.Subject {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only the project owner can transfer ownership")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if req.UserID == claims.Subject {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("You already own this project")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) The new owner must already be an admin member
+		target, err := cfg.MemberStore.GetProjectMember(c.Request.Context(), project.ID, req.UserID)
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			log.Printf("GetProjectMember error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if err != nil || target == nil || !HasMinRole(target.Role, supabase.MemberRoleAdmin) {
+			apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("The new owner must be an admin member of the project")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Record the proposal
+		transfer, err := cfg.TransferStore.CreateOwnershipTransfer(c.Request.Context(), supabase.CreateOwnershipTransferParams{
+			ProjectID:  project.ID,
+			FromUserID: claims.Subject,
+			ToUserID:   req.UserID,
+			ExpiresAt:  time.Now().Add(ownershipTransferTTL),
+		})
+		if err != nil || transfer == nil {
+			if err != nil {
+				log.Printf("CreateOwnershipTransfer error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		auditOwnershipTransfer(c, cfg, claims, "project.ownership_transfer_initiated", http.StatusCreated, transfer)
+
+		c.JSON(http.StatusCreated, newOwnershipTransferResponse(*transfer))
+	}
+}
+
+// GetOwnershipTransferHandler handles GET /api/v1/projects/{id}/ownership-transfer
+func GetOwnershipTransferHandler(cfg OwnershipTransferHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 3) Load the pending transfer
+		transfer, ok := loadPendingTransfer(c, cfg, project.ID)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, newOwnershipTransferResponse(*transfer))
+	}
+}
+
+// CancelOwnershipTransferHandler handles DELETE /api/v1/projects/{id}/ownership-transfer
+// Either the owner or the proposed owner can cancel (decline) a pending transfer.
+func CancelOwnershipTransferHandler(cfg OwnershipTransferHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 3) Load the pending transfer and check the caller is a party to it
+		transfer, ok := loadPendingTransfer(c, cfg, project.ID)
+		if !ok {
+			return
+		}
+		if claims.Subject != transfer.FromUserID && claims.Subject != transfer.ToUserID {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only the owner or the proposed owner can cancel the transfer")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) Cancel it
+		if err := cfg.TransferStore.CancelOwnershipTransfer(c.Request.Context(), transfer.ID); err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "not found") {
+				apiErr := errors.NewAPIError(errors.ErrNotFound)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			log.Printf("CancelOwnershipTransfer error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		auditOwnershipTransfer(c, cfg, claims, "project.ownership_transfer_cancelled", http.StatusNoContent, transfer)
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// AcceptOwnershipTransferHandler handles POST /api/v1/projects/{id}/ownership-transfer/accept
+// The proposed owner accepts; owner_user_id, both members' roles and the audit entry change
+// atomically in the database. The previous owner stays on as admin.
+func AcceptOwnershipTransferHandler(cfg OwnershipTransferHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 3) Only the proposed owner can accept
+		transfer, ok := loadPendingTransfer(c, cfg, project.ID)
+		if !ok {
+			return
+		}
+		if claims.Subject != transfer.ToUserID {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only the proposed owner can accept the transfer")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) Complete the transfer
+		updated, err := cfg.TransferStore.AcceptOwnershipTransfer(c.Request.Context(), transfer.ID, claims.Subject)
+		if err != nil || updated == nil {
+			writeAcceptTransferError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, AcceptOwnershipTransferResponse{
+			ProjectID:   updated.ID,
+			OwnerUserID: updated.OwnerUserID,
+		})
+	}
+}
+
+// loadPendingTransfer returns the project's pending transfer.
+// It writes the error response and returns false when there is none.
+func loadPendingTransfer(c *gin.Context, cfg OwnershipTransferHandlerConfig, projectID string) (*supabase.OwnershipTransfer, bool) {
+	transfer, err := cfg.TransferStore.GetPendingOwnershipTransfer(c.Request.Context(), projectID, time.Now())
+	if err != nil || transfer == nil {
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			log.Printf("GetPendingOwnershipTransfer error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return nil, false
+		}
+		apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("No pending ownership transfer")
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, false
+	}
+	return transfer, true
+}
+
+// writeAcceptTransferError maps errors raised by the accept_project_ownership_transfer function
+func writeAcceptTransferError(c *gin.Context, err error) {
+	errMsg := ""
+	if err != nil {
+		errMsg = strings.ToLower(err.Error())
+		log.Printf("AcceptOwnershipTransfer error: %s", err.Error())
+	}
+
+	switch {
+	case strings.Contains(errMsg, "not found"):
+		apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("No pending ownership transfer")
+		c.JSON(apiErr.StatusCode(), apiErr)
+	case strings.Contains(errMsg, "projects_owner_name_unique") || strings.Contains(errMsg, "23505"):
+		apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("You already own a project with this name")
+		c.JSON(apiErr.StatusCode(), apiErr)
+	case strings.Contains(errMsg, "stale") || strings.Contains(errMsg, "must be an admin"):
+		apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("The transfer is no longer valid")
+		c.JSON(apiErr.StatusCode(), apiErr)
+	default:
+		apiErr := errors.NewAPIError(errors.ErrInternalError)
+		c.JSON(apiErr.StatusCode(), apiErr)
+	}
+}
+
+// auditOwnershipTransfer records a transfer step. Failures are logged only; the accepted
+// transfer is audited by the database function itself.
+func auditOwnershipTransfer(c *gin.Context, cfg OwnershipTransferHandlerConfig, claims *auth.SupabaseClaims, action string, statusCode int, transfer *supabase.OwnershipTransfer) {
+	actorID := claims.Subject
+	entry := supabase.AuditLog{
+		ProjectID:  transfer.ProjectID,
+		ActorType:  supabase.ActorTypeUser,
+		ActorID:    &actorID,
+		Action:     action,
+		Success:    true,
+		StatusCode: statusCode,
+		AuthMode:   supabase.AuthModeJWT,
+		Details: map[string]interface{}{
+			"transfer_id":  transfer.ID,
+			"from_user_id": transfer.FromUserID,
+			"to_user_id":   transfer.ToUserID,
+		},
+	}
+	if err := cfg.AuditStore.CreateAuditLog(c.Request.Context(), entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOwnershipTransferStore implements handlers.OwnershipTransferStore for testing.
+type MockOwnershipTransferStore struct {
+	mock.Mock
+}
+
+func (m *MockOwnershipTransferStore) CreateOwnershipTransfer(ctx context.Context, params supabase.CreateOwnershipTransferParams) (*supabase.OwnershipTransfer, error) {
+	args := m.Called(ctx, params)
+	transfer := args.Get(0)
+	if transfer == nil {
+		return nil, args.Error(1)
+	}
+	return transfer.(*supabase.OwnershipTransfer), args.Error(1)
+}
+
+func (m *MockOwnershipTransferStore) GetPendingOwnershipTransfer(ctx context.Context, projectID string, now time.Time) (*supabase.OwnershipTransfer, error) {
+	args := m.Called(ctx, projectID, now)
+	transfer := args.Get(0)
+	if transfer == nil {
+		return nil, args.Error(1)
+	}
+	return transfer.(*supabase.OwnershipTransfer), args.Error(1)
+}
+
+func (m *MockOwnershipTransferStore) CancelOwnershipTransfer(ctx context.Context, transferID string) error {
+	args := m.Called(ctx, transferID)
+	return args.Error(0)
+}
+
+func (m *MockOwnershipTransferStore) AcceptOwnershipTransfer(ctx context.Context, transferID, userID string) (*supabase.Project, error) {
+	args := m.Called(ctx, transferID, userID)
+	project := args.Get(0)
+	if project == nil {
+		return nil, args.Error(1)
+	}
+	return project.(*supabase.Project), args.Error(1)
+}
+
+// pendingTransfer is a transfer of the members test project from its owner to "admin-next"
+var pendingTransfer = &supabase.OwnershipTransfer{
+	ID:         "transfer-1",
+	ProjectID:  memberTestProjectID,
+	FromUserID: memberTestOwnerID,
+	ToUserID:   "admin-next",
+}
+
+func newTransferConfig(memberStore *MockProjectMemberStore, transferStore *MockOwnershipTransferStore) OwnershipTransferHandlerConfig {
+	auditStore := &MockAuditLogStore{}
+	auditStore.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+	return OwnershipTransferHandlerConfig{
+		ProjectStore:  newMemberTestProjectStore(),
+		MemberStore:   memberStore,
+		TransferStore: transferStore,
+		AuditStore:    auditStore,
+	}
+}
+
+func newTransferContext(w *httptest.ResponseRecorder, method, suffix, body, userID string) *gin.Context {
+	return newTokenContext(w, method, "/api/v1/projects/"+memberTestProjectID+"/ownership-transfer"+suffix, body, memberTestProjectID, userID)
+}
+
+// TestCreateOwnershipTransferHandler tests who can propose a transfer and to whom
+func TestCreateOwnershipTransferHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		actorID    string
+		actorRole  string
+		targetRole string
+		body       string
+		want       int
+	}{
+		{"owner to admin", memberTestOwnerID, "", supabase.MemberRoleAdmin, `{"user_id":"admin-next"}`, http.StatusCreated},
+		{"owner to viewer", memberTestOwnerID, "", supabase.MemberRoleViewer, `{"user_id":"admin-next"}`, http.StatusConflict},
+		{"owner to non-member", memberTestOwnerID, "", "", `{"user_id":"admin-next"}`, http.StatusConflict},
+		{"owner to self", memberTestOwnerID, "", "", `{"user_id":"` + memberTestOwnerID + `"}`, http.StatusBadRequest},
+		{"missing user", memberTestOwnerID, "", "", `{}`, http.StatusBadRequest},
+		{"co-owner", "co-owner", supabase.MemberRoleOwner, supabase.MemberRoleAdmin, `{"user_id":"admin-next"}`, http.StatusForbidden},
+		{"admin", "admin-x", supabase.MemberRoleAdmin, supabase.MemberRoleAdmin, `{"user_id":"admin-next"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberStore := newMemberStoreWithRole(memberTestProjectID, "admin-next", tt.targetRole)
+			if tt.actorRole != "" {
+				memberStore.On("GetProjectMember", mock.Anything, memberTestProjectID, tt.actorID).
+					Return(&supabase.ProjectMember{ProjectID: memberTestProjectID, UserID: tt.actorID, Role: tt.actorRole}, nil)
+			}
+			transferStore := &MockOwnershipTransferStore{}
+			transferStore.On("CreateOwnershipTransfer", mock.Anything, mock.MatchedBy(func(p supabase.CreateOwnershipTransferParams) bool {
+				return p.FromUserID == memberTestOwnerID && p.ToUserID == "admin-next" && p.ExpiresAt.After(time.Now())
+			})).Return(pendingTransfer, nil)
+
+			w := httptest.NewRecorder()
+			c := newTransferContext(w, http.MethodPost, "", tt.body, tt.actorID)
+
+			CreateOwnershipTransferHandler(newTransferConfig(memberStore, transferStore))(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusCreated {
+				assert.Contains(t, w.Body.String(), `"to_user_id":"admin-next"`)
+			} else {
+				transferStore.AssertNotCalled(t, "CreateOwnershipTransfer", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestAcceptOwnershipTransferHandler tests that only the proposed owner can accept and database rejections map to statuses
+func TestAcceptOwnershipTransferHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		actorID   string
+		acceptErr error
+		want      int
+	}{
+		{"proposed owner", "admin-next", nil, http.StatusOK},
+		{"other admin", "admin-other", nil, http.StatusForbidden},
+		{"target demoted", "admin-next", errors.New(`{"code":"23514","message":"ownership transfer target must be an admin member"}`), http.StatusConflict},
+		{"name taken", "admin-next", errors.New(`{"code":"23505","message":"duplicate key value violates unique constraint \"projects_owner_name_unique\""}`), http.StatusConflict},
+		{"expired meanwhile", "admin-next", errors.New(`{"code":"P0002","message":"ownership transfer not found"}`), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberStore := newMemberStoreWithRole(memberTestProjectID, tt.actorID, supabase.MemberRoleAdmin)
+			transferStore := &MockOwnershipTransferStore{}
+			transferStore.On("GetPendingOwnershipTransfer", mock.Anything, memberTestProjectID, mock.Anything).Return(pendingTransfer, nil)
+			if tt.acceptErr != nil {
+				transferStore.On("AcceptOwnershipTransfer", mock.Anything, "transfer-1", tt.actorID).Return(nil, tt.acceptErr)
+			} else {
+				transferStore.On("AcceptOwnershipTransfer", mock.Anything, "transfer-1", tt.actorID).
+					Return(&supabase.Project{ID: memberTestProjectID, OwnerUserID: tt.actorID}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			c := newTransferContext(w, http.MethodPost, "/accept", "", tt.actorID)
+
+			AcceptOwnershipTransferHandler(newTransferConfig(memberStore, transferStore))(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"owner_user_id":"admin-next"`)
+			}
+		})
+	}
+}
+
+// TestCancelOwnershipTransferHandler tests that either party can cancel and others cannot
+func TestCancelOwnershipTransferHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		actorID string
+		pending bool
+		want    int
+	}{
+		{"owner", memberTestOwnerID, true, http.StatusNoContent},
+		{"proposed owner declines", "admin-next", true, http.StatusNoContent},
+		{"other admin", "admin-other", true, http.StatusForbidden},
+		{"nothing pending", memberTestOwnerID, false, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberStore := newMemberStoreWithRole(memberTestProjectID, tt.actorID, supabase.MemberRoleAdmin)
+			transferStore := &MockOwnershipTransferStore{}
+			if tt.pending {
+				transferStore.On("GetPendingOwnershipTransfer", mock.Anything, memberTestProjectID, mock.Anything).Return(pendingTransfer, nil)
+			} else {
+				transferStore.On("GetPendingOwnershipTransfer", mock.Anything, memberTestProjectID, mock.Anything).
+					Return(nil, errors.New("ownership transfer not found"))
+			}
+			transferStore.On("CancelOwnershipTransfer", mock.Anything, "transfer-1").Return(nil)
+
+			w := httptest.NewRecorder()
+			c := newTransferContext(w, http.MethodDelete, "", "", tt.actorID)
+
+			CancelOwnershipTransferHandler(newTransferConfig(memberStore, transferStore))(c)
+
+			assert.Equal(t, tt.want, c.Writer.Status())
+		})
+	}
+}
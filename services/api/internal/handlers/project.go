@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -61,12 +63,7 @@ func CreateProjectHandler(store ProjectStore) gin.HandlerFunc {
 			// Log the error for debugging
 			log.Printf("CreateProject error: %s", err.Error())
 
-			// Check for various unique constraint violation patterns
-			if strings.Contains(errMsg, "duplicate") ||
-			   strings.Contains(errMsg, "unique constraint") ||
-			   strings.Contains(errMsg, "unique_violation") ||
-			   strings.Contains(errMsg, "23505") || // PostgreSQL unique violation code
-			   strings.Contains(errMsg, "projects_owner_name_unique") {
+			if isDuplicateProjectName(errMsg) {
 				apiErr := errors.NewAPIError(errors.ErrConflict)
 				c.JSON(apiErr.StatusCode(), apiErr)
 				return
@@ -92,29 +89,320 @@ func CreateProjectHandler(store ProjectStore) gin.HandlerFunc {
 	}
 }
 
+// isDuplicateProjectName reports whether a lowercased database error is the per-owner
+// unique project name constraint (or another unique violation) being hit
+func isDuplicateProjectName(errMsg string) bool {
+	return strings.Contains(errMsg, "duplicate") ||
+		strings.Contains(errMsg, "unique constraint") ||
+		strings.Contains(errMsg, "unique_violation") ||
+		strings.Contains(errMsg, "23505") || // PostgreSQL unique violation code
+		strings.Contains(errMsg, "projects_owner_name_unique")
+}
+
+// ProjectResponse matches the OpenAPI Project schema
+type ProjectResponse struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	OwnerUserID   string    `json:"owner_user_id"`
+	Role          string    `json:"role"`
+	RetentionDays *int      `json:"retention_days"`
+	SignedOnly    bool      `json:"signed_only"`
+	IsDemo        bool      `json:"is_demo"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// Set while the project is pending deletion
+	DeletedAt  *time.Time `json:"deleted_at"`
+	PurgeAfter *time.Time `json:"purge_after"`
+}
+
+// ListProjectsResponse matches the OpenAPI schema
+type ListProjectsResponse struct {
+	Projects []ProjectResponse `json:"projects"`
+}
+
+// UpdateProjectRequest matches the OpenAPI schema; omitted fields are left unchanged
+type UpdateProjectRequest struct {
+	Name          *string `json:"name" binding:"omitempty,min=1,max=64"`
+	RetentionDays *int    `json:"retention_days" binding:"omitempty,min=1,max=3650"`
+	SignedOnly    *bool   `json:"signed_only"`
+}
+
+func newProjectResponse(project supabase.Project, role string) ProjectResponse {
+	response := ProjectResponse{
+		ID:            project.ID,
+		Name:          project.Name,
+		OwnerUserID:   project.OwnerUserID,
+		Role:          role,
+		RetentionDays: project.RetentionDays,
+		CreatedAt:     project.CreatedAt,
+		UpdatedAt:     project.UpdatedAt,
+		DeletedAt:     project.DeletedAt,
+		PurgeAfter:    project.PurgeAfter,
+	}
+	if project.SignedOnly != nil {
+		response.SignedOnly = *project.SignedOnly
+	}
+	if project.IsDemo != nil {
+		response.IsDemo = *project.IsDemo
+	}
+	return response
+}
+
+// ListProjectsHandler handles GET /api/v1/projects
+// It lists the projects the caller is a member of, with the caller's role in each.
+// Projects pending deletion are only listed to owners, who can restore them; every other route
+// treats them as gone.
+func ListProjectsHandler(store ProjectStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) List memberships
+		projects, err := store.ListProjectsForUser(c.Request.Context(), claims.Subject)
+		if err != nil {
+			log.Printf("ListProjectsForUser error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Build response
+		response := ListProjectsResponse{Projects: make([]ProjectResponse, 0, len(projects))}
+		for _, p := range projects {
+			if p.Project.DeletedAt != nil && p.Role != supabase.MemberRoleOwner {
+				continue
+			}
+			response.Projects = append(response.Projects, newProjectResponse(p.Project, p.Role))
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetProjectHandler handles GET /api/v1/projects/{id}
+func GetProjectHandler(projectStore ProjectStore, memberStore ProjectMemberStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is a member
+		project, role, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleViewer)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, newProjectResponse(*project, role))
+	}
+}
+
+// UpdateProjectHandler handles PATCH /api/v1/projects/{id}
+// Renaming to a name the project owner already uses returns 409.
+func UpdateProjectHandler(projectStore ProjectStore, memberStore ProjectMemberStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req UpdateProjectRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if req.Name == nil && req.RetentionDays == nil && req.SignedOnly == nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Provide at least one of name, retention_days or signed_only")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and the caller is at least an admin
+		project, role, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 4) Update settings
+		updated, err := projectStore.UpdateProject(c.Request.Context(), project.ID, supabase.UpdateProjectParams{
+			Name:          req.Name,
+			RetentionDays: req.RetentionDays,
+			SignedOnly:    req.SignedOnly,
+		})
+		if err != nil || updated == nil {
+			if err != nil {
+				log.Printf("UpdateProject error: %s", err.Error())
+				errMsg := strings.ToLower(err.Error())
+				if isDuplicateProjectName(errMsg) {
+					apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("The project owner already has a project with this name")
+					c.JSON(apiErr.StatusCode(), apiErr)
+					return
+				}
+				if strings.Contains(errMsg, "not found") {
+					apiErr := errors.NewAPIError(errors.ErrNotFound)
+					c.JSON(apiErr.StatusCode(), apiErr)
+					return
+				}
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.JSON(http.StatusOK, newProjectResponse(*updated, role))
+	}
+}
+
+// DeleteProjectHandler handles DELETE /api/v1/projects/{id}
+// Only the project's recorded owner can delete it, matching the RLS delete policy. The project
+// is soft deleted: its keys and tokens stop working at once, and its data is purged after
+// gracePeriod unless it is restored first.
+func DeleteProjectHandler(projectStore ProjectStore, memberStore ProjectMemberStore, gracePeriod time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is its recorded owner
+		project, role, ok := authorizeProject(c, projectStore, memberStore, claims, c.Param("id"), supabase.MemberRoleOwner)
+		if !ok {
+			return
+		}
+		if project.OwnerUserID != claims.Subject {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only the project owner can delete the project")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Mark the project deleted
+		deleted, err := projectStore.SoftDeleteProject(c.Request.Context(), project.ID, time.Now().Add(gracePeriod))
+		if err != nil || deleted == nil {
+			if err != nil && strings.Contains(strings.ToLower(err.Error()), "not found") {
+				apiErr := errors.NewAPIError(errors.ErrNotFound)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			if err != nil {
+				log.Printf("SoftDeleteProject error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, newProjectResponse(*deleted, role))
+	}
+}
+
+// RestoreProjectHandler handles POST /api/v1/projects/{id}/restore
+// The recorded owner can restore a deleted project until it is purged.
+func RestoreProjectHandler(projectStore ProjectStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Load the project, including a deleted one
+		project, err := projectStore.GetProjectByID(c.Request.Context(), c.Param("id"))
+		if err != nil || project == nil {
+			if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+				log.Printf("GetProjectByID error: %s", err.Error())
+				apiErr := errors.NewAPIError(errors.ErrBadRequest)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			apiErr := errors.NewAPIError(errors.ErrNotFound)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Only the recorded owner can restore, and only a deleted project
+		if project.OwnerUserID != claims.Subject {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Only the project owner can restore the project")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if project.DeletedAt == nil {
+			apiErr := errors.NewAPIError(errors.ErrConflict).WithMessage("Project is not deleted")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) Restore it
+		restored, err := projectStore.RestoreProject(c.Request.Context(), project.ID)
+		if err != nil || restored == nil {
+			if err != nil && strings.Contains(strings.ToLower(err.Error()), "not found") {
+				apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("The grace period has ended and the project is being purged")
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			if err != nil {
+				log.Printf("RestoreProject error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.JSON(http.StatusOK, newProjectResponse(*restored, supabase.MemberRoleOwner))
+	}
+}
+
 // CreateProjectKeyRequest matches the OpenAPI schema
 type CreateProjectKeyRequest struct {
-	Label             string   `json:"label" binding:"required,min=1,max=64"`
-	RequireSignature  bool     `json:"require_signature"`
-	Env               *string  `json:"env"`
-	Scopes            []string `json:"scopes"`
+	Label            string     `json:"label" binding:"required,min=1,max=64"`
+	RequireSignature bool       `json:"require_signature"`
+	Env              *string    `json:"env"`
+	Scopes           []string   `json:"scopes"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	AllowedCIDRs     []string   `json:"allowed_cidrs" binding:"max=50"`
+	AllowedOrigins   []string   `json:"allowed_origins" binding:"max=50"`
 }
 
 // ProjectKeyResponse matches the OpenAPI ProjectKey schema
 type ProjectKeyResponse struct {
-	ID          string    `json:"id"`
-	Label       string    `json:"label"`
-	Env         *string   `json:"env"`
-	Scopes      []string  `json:"scopes"`
-	SecretLast4 string    `json:"secret_last4"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID            string     `json:"id"`
+	Label         string     `json:"label"`
+	Env           *string    `json:"env"`
+	Scopes        []string   `json:"scopes"`
+	SecretLast4   string     `json:"secret_last4"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	ExpiryWarning *string    `json:"expiry_warning,omitempty"`
+}
+
+// ListProjectKeysResponse matches the OpenAPI schema
+type ListProjectKeysResponse struct {
+	Keys []ListedProjectKey `json:"keys"`
+}
+
+// ListedProjectKey is a ProjectKey as returned by the key list (never includes the secret)
+type ListedProjectKey struct {
+	ProjectKeyResponse
+	PublicKey      string     `json:"public_key"`
+	SignedOnly     bool       `json:"signed_only"`
+	Disabled       bool       `json:"disabled"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	AllowedCIDRs   []string   `json:"allowed_cidrs"`
+	AllowedOrigins []string   `json:"allowed_origins"`
 }
 
 // CreateProjectKeyResponse matches the OpenAPI schema
 type CreateProjectKeyResponse struct {
-	ProjectKeyPublic string              `json:"project_key_public"`
-	ProjectSecret    *string             `json:"project_secret"`
-	Key              ProjectKeyResponse  `json:"key"`
+	ProjectKeyPublic string             `json:"project_key_public"`
+	ProjectSecret    *string            `json:"project_secret"`
+	Key              ProjectKeyResponse `json:"key"`
 }
 
 // Simple in-memory rate limiter for key creation
@@ -124,8 +412,8 @@ type rateLimiter struct {
 }
 
 type bucket struct {
-	count      int
-	resetAt    time.Time
+	count   int
+	resetAt time.Time
 }
 
 var (
@@ -141,8 +429,8 @@ var (
 )
 
 const (
-	maxKeysPerMinute = 3           // Burst protection: max 3 per minute
-	maxKeysPerDay    = 5           // Daily quota: max 5 per day
+	maxKeysPerMinute      = 3 // Burst protection: max 3 per minute
+	maxKeysPerDay         = 5 // Daily quota: max 5 per day
 	rateLimitWindowMinute = time.Minute
 	rateLimitWindowDay    = 24 * time.Hour
 )
@@ -171,20 +459,11 @@ func (rl *rateLimiter) allow(userID string, maxCount int, window time.Duration)
 }
 
 // CreateProjectKeyHandler handles POST /api/v1/projects/{id}/keys
-func CreateProjectKeyHandler(projectStore ProjectStore, keyStore ProjectKeyStore) gin.HandlerFunc {
+func CreateProjectKeyHandler(projectStore ProjectStore, memberStore ProjectMemberStore, keyStore ProjectKeyStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1) Ensure authentication
-		claimsAny, ok := c.Get(auth.ContextKeyClaims)
+		claims, ok := claimsFromContext(c)
 		if !ok {
-			apiErr := errors.NewAPIError(errors.ErrUnauthorized)
-			c.JSON(apiErr.StatusCode(), apiErr)
-			return
-		}
-
-		claims, ok := claimsAny.(*auth.SupabaseClaims)
-		if !ok || claims.Subject == "" {
-			apiErr := errors.NewAPIError(errors.ErrUnauthorized)
-			c.JSON(apiErr.StatusCode(), apiErr)
 			return
 		}
 
@@ -219,48 +498,51 @@ func CreateProjectKeyHandler(projectStore ProjectStore, keyStore ProjectKeyStore
 			return
 		}
 
-		// 5) Get project to verify it exists and check ownership
-		project, err := projectStore.GetProjectByID(c.Request.Context(), projectID)
+		// Set default values and validate env, scopes, expiry and allowlists before touching the database
+		env := crypto.EnvProd
+		if req.Env != nil && *req.Env != "" {
+			env = *req.Env
+		}
+		if !crypto.IsValidEnv(env) {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid env: must be one of prod, staging, dev")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		scopes, err := auth.NormalizeKeyScopes(req.Scopes)
 		if err != nil {
-			errMsg := strings.ToLower(err.Error())
-			if strings.Contains(errMsg, "not found") {
-				apiErr := errors.NewAPIError(errors.ErrNotFound)
-				c.JSON(apiErr.StatusCode(), apiErr)
-				return
-			}
-			// Other errors (e.g., invalid UUID format) are parameter errors
-			log.Printf("GetProjectByID error: %s", err.Error())
-			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid scopes: " + err.Error())
 			c.JSON(apiErr.StatusCode(), apiErr)
 			return
 		}
 
-		if project == nil {
-			apiErr := errors.NewAPIError(errors.ErrNotFound)
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid expires_at: must be in the future")
 			c.JSON(apiErr.StatusCode(), apiErr)
 			return
 		}
 
-		// 6) Verify user is the project owner
-		if project.OwnerUserID != claims.Subject {
-			apiErr := errors.NewAPIError(errors.ErrForbidden)
+		allowedCIDRs, err := auth.NormalizeCIDRs(req.AllowedCIDRs)
+		if err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid allowed_cidrs: " + err.Error())
 			c.JSON(apiErr.StatusCode(), apiErr)
 			return
 		}
 
-		// 7) Set default values
-		env := "prod"
-		if req.Env != nil && *req.Env != "" {
-			env = *req.Env
+		allowedOrigins, err := auth.NormalizeOrigins(req.AllowedOrigins)
+		if err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid allowed_origins: " + err.Error())
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
 		}
 
-		scopes := req.Scopes
-		if len(scopes) == 0 {
-			scopes = []string{"ingest"}
+		// 5) Verify the project exists and the caller is at least an admin
+		if _, _, ok := authorizeProject(c, projectStore, memberStore, claims, projectID, supabase.MemberRoleAdmin); !ok {
+			return
 		}
 
-		// 8) Generate keys
-		publicKey, err := crypto.GeneratePublicKey()
+		// 6) Generate keys
+		publicKey, err := crypto.GeneratePublicKey(env)
 		if err != nil {
 			log.Printf("Failed to generate public key: %s", err.Error())
 			apiErr := errors.NewAPIError(errors.ErrInternalError)
@@ -268,7 +550,7 @@ func CreateProjectKeyHandler(projectStore ProjectStore, keyStore ProjectKeyStore
 			return
 		}
 
-		secret, err := crypto.GenerateSecret()
+		secret, err := crypto.GenerateSecret(env)
 		if err != nil {
 			log.Printf("Failed to generate secret: %s", err.Error())
 			apiErr := errors.NewAPIError(errors.ErrInternalError)
@@ -276,20 +558,24 @@ func CreateProjectKeyHandler(projectStore ProjectStore, keyStore ProjectKeyStore
 			return
 		}
 
-		// 9) Hash secret and get last4
+		// 7) Hash secret and get last4
 		secretHash := crypto.HashSecret(secret)
 		secretLast4 := crypto.GetLast4(secret)
 
-		// 10) Create project key in database
+		// 8) Create project key in database
 		keyParams := supabase.CreateProjectKeyParams{
-			ProjectID:   projectID,
-			Label:       req.Label,
-			Env:         env,
-			SignedOnly:  req.RequireSignature,
-			PublicKey:   publicKey,
-			SecretHash:  secretHash,
-			SecretLast4: secretLast4,
-			CreatedBy:   claims.Subject,
+			ProjectID:      projectID,
+			Label:          req.Label,
+			Env:            env,
+			SignedOnly:     req.RequireSignature,
+			Scopes:         scopes,
+			PublicKey:      publicKey,
+			SecretHash:     secretHash,
+			SecretLast4:    secretLast4,
+			CreatedBy:      claims.Subject,
+			ExpiresAt:      req.ExpiresAt,
+			AllowedCIDRs:   allowedCIDRs,
+			AllowedOrigins: allowedOrigins,
 		}
 
 		projectKey, err := keyStore.CreateProjectKey(c.Request.Context(), keyParams)
@@ -306,7 +592,22 @@ func CreateProjectKeyHandler(projectStore ProjectStore, keyStore ProjectKeyStore
 			return
 		}
 
-		// 11) Build response with secret (shown only once)
+		// 9) A key stored without the requested scopes would act with different permissions;
+		// disable it rather than hand out its secret
+		for _, scope := range scopes {
+			if slices.Contains(projectKey.Scopes, scope) {
+				continue
+			}
+			log.Printf("CreateProjectKey error: key %s stored with scopes %v, requested %v", projectKey.ID, projectKey.Scopes, scopes)
+			if err := keyStore.DisableProjectKey(c.Request.Context(), projectKey.ID); err != nil {
+				log.Printf("DisableProjectKey error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 10) Build response with secret (shown only once)
 		c.Header("Cache-Control", "no-store")
 
 		response := CreateProjectKeyResponse{
@@ -316,12 +617,100 @@ func CreateProjectKeyHandler(projectStore ProjectStore, keyStore ProjectKeyStore
 				ID:          projectKey.ID,
 				Label:       projectKey.Label,
 				Env:         &projectKey.Env,
-				Scopes:      scopes,
+				Scopes:      projectKey.Scopes,
 				SecretLast4: secretLast4,
 				CreatedAt:   projectKey.CreatedAt,
+				ExpiresAt:   projectKey.ExpiresAt,
 			},
 		}
 
 		c.JSON(http.StatusCreated, response)
 	}
 }
+
+// expiryWarning returns a warning for keys that expire within window, or nil otherwise
+func expiryWarning(key supabase.ProjectKey, now time.Time, window time.Duration) *string {
+	if key.ExpiresAt == nil || key.Disabled {
+		return nil
+	}
+
+	var msg string
+	switch remaining := key.ExpiresAt.Sub(now); {
+	case remaining <= 0:
+		msg = "expired"
+	case remaining <= window:
+		days := int(remaining.Hours() / 24)
+		if days == 0 {
+			msg = "expires in less than a day"
+		} else if days == 1 {
+			msg = "expires in 1 day"
+		} else {
+			msg = fmt.Sprintf("expires in %d days", days)
+		}
+	default:
+		return nil
+	}
+
+	return &msg
+}
+
+// ListProjectKeysHandler handles GET /api/v1/projects/{id}/keys
+// Keys expiring within warningWindow carry an expiry_warning.
+func ListProjectKeysHandler(projectStore ProjectStore, memberStore ProjectMemberStore, keyStore ProjectKeyStore, warningWindow time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Extract project ID from URL
+		projectID := c.Param("id")
+		if projectID == "" {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and the caller manages its keys
+		if _, _, ok := authorizeProject(c, projectStore, memberStore, claims, projectID, supabase.MemberRoleAdmin); !ok {
+			return
+		}
+
+		// 4) List keys
+		keys, err := keyStore.ListProjectKeys(c.Request.Context(), projectID)
+		if err != nil {
+			log.Printf("ListProjectKeys error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Build response
+		now := time.Now()
+		response := ListProjectKeysResponse{Keys: make([]ListedProjectKey, 0, len(keys))}
+		for _, key := range keys {
+			env := key.Env
+			response.Keys = append(response.Keys, ListedProjectKey{
+				ProjectKeyResponse: ProjectKeyResponse{
+					ID:            key.ID,
+					Label:         key.Label,
+					Env:           &env,
+					Scopes:        key.Scopes,
+					SecretLast4:   key.SecretFingerprint,
+					CreatedAt:     key.CreatedAt,
+					ExpiresAt:     key.ExpiresAt,
+					ExpiryWarning: expiryWarning(key, now, warningWindow),
+				},
+				PublicKey:      key.PublicKey,
+				SignedOnly:     key.SignedOnly,
+				Disabled:       key.Disabled,
+				LastUsedAt:     key.LastUsedAt,
+				AllowedCIDRs:   key.AllowedCIDRs,
+				AllowedOrigins: key.AllowedOrigins,
+			})
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"github.com/libpulse/platform/services/api/internal/supabase"
 )
@@ -10,9 +11,16 @@ import (
 type ProjectStore interface {
 	GetProjectByID(ctx context.Context, projectID string) (*supabase.Project, error)
 	CreateProject(ctx context.Context, name string, ownerUserID string) (*supabase.Project, error)
+	ListProjectsForUser(ctx context.Context, userID string) ([]supabase.MemberProject, error)
+	UpdateProject(ctx context.Context, projectID string, params supabase.UpdateProjectParams) (*supabase.Project, error)
+	SoftDeleteProject(ctx context.Context, projectID string, purgeAfter time.Time) (*supabase.Project, error)
+	RestoreProject(ctx context.Context, projectID string) (*supabase.Project, error)
 }
 
 // ProjectKeyStore abstracts project key data access for handlers, enabling dependency injection and unit testing.
 type ProjectKeyStore interface {
 	CreateProjectKey(ctx context.Context, params supabase.CreateProjectKeyParams) (*supabase.ProjectKey, error)
+	ListProjectKeys(ctx context.Context, projectID string) ([]supabase.ProjectKey, error)
+	GetProjectKeyBySecretHash(ctx context.Context, secretHash string) (*supabase.ProjectKey, error)
+	DisableProjectKey(ctx context.Context, keyID string) error
 }
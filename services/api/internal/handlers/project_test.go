@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,6 +65,54 @@ func (m *MockProjectStore) CreateProject(
 	return project, args.Error(1)
 }
 
+// ListProjectsForUser mocks ProjectStore.ListProjectsForUser.
+func (m *MockProjectStore) ListProjectsForUser(ctx context.Context, userID string) ([]supabase.MemberProject, error) {
+	args := m.Called(ctx, userID)
+
+	var projects []supabase.MemberProject
+	if v := args.Get(0); v != nil {
+		projects = v.([]supabase.MemberProject)
+	}
+
+	return projects, args.Error(1)
+}
+
+// UpdateProject mocks ProjectStore.UpdateProject.
+func (m *MockProjectStore) UpdateProject(ctx context.Context, projectID string, params supabase.UpdateProjectParams) (*supabase.Project, error) {
+	args := m.Called(ctx, projectID, params)
+
+	var project *supabase.Project
+	if v := args.Get(0); v != nil {
+		project = v.(*supabase.Project)
+	}
+
+	return project, args.Error(1)
+}
+
+// SoftDeleteProject mocks ProjectStore.SoftDeleteProject.
+func (m *MockProjectStore) SoftDeleteProject(ctx context.Context, projectID string, purgeAfter time.Time) (*supabase.Project, error) {
+	args := m.Called(ctx, projectID, purgeAfter)
+
+	var project *supabase.Project
+	if v := args.Get(0); v != nil {
+		project = v.(*supabase.Project)
+	}
+
+	return project, args.Error(1)
+}
+
+// RestoreProject mocks ProjectStore.RestoreProject.
+func (m *MockProjectStore) RestoreProject(ctx context.Context, projectID string) (*supabase.Project, error) {
+	args := m.Called(ctx, projectID)
+
+	var project *supabase.Project
+	if v := args.Get(0); v != nil {
+		project = v.(*supabase.Project)
+	}
+
+	return project, args.Error(1)
+}
+
 // TestCreateProjectHandler_Success tests successful project creation
 func TestCreateProjectHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -426,6 +475,39 @@ func (m *MockProjectKeyStore) CreateProjectKey(
 	return key, args.Error(1)
 }
 
+func (m *MockProjectKeyStore) ListProjectKeys(
+	ctx context.Context,
+	projectID string,
+) ([]supabase.ProjectKey, error) {
+	args := m.Called(ctx, projectID)
+
+	var keys []supabase.ProjectKey
+	if v := args.Get(0); v != nil {
+		keys = v.([]supabase.ProjectKey)
+	}
+
+	return keys, args.Error(1)
+}
+
+func (m *MockProjectKeyStore) GetProjectKeyBySecretHash(
+	ctx context.Context,
+	secretHash string,
+) (*supabase.ProjectKey, error) {
+	args := m.Called(ctx, secretHash)
+
+	var key *supabase.ProjectKey
+	if v := args.Get(0); v != nil {
+		key = v.(*supabase.ProjectKey)
+	}
+
+	return key, args.Error(1)
+}
+
+func (m *MockProjectKeyStore) DisableProjectKey(ctx context.Context, keyID string) error {
+	args := m.Called(ctx, keyID)
+	return args.Error(0)
+}
+
 // TestCreateProjectKeyHandler_Success tests successful project key creation
 func TestCreateProjectKeyHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -451,6 +533,7 @@ func TestCreateProjectKeyHandler_Success(t *testing.T) {
 		Label:             "test-key",
 		Env:               "prod",
 		SignedOnly:        false,
+		Scopes:            []string{"ingest"},
 		PublicKey:         "pk_live_test",
 		SecretEnc:         "hash",
 		SecretFingerprint: "1234",
@@ -477,7 +560,7 @@ func TestCreateProjectKeyHandler_Success(t *testing.T) {
 	c.Set(auth.ContextKeyClaims, claims)
 
 	// Execute handler
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	// Assertions
@@ -506,7 +589,7 @@ func TestCreateProjectKeyHandler_NoAuth(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-123/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -529,7 +612,7 @@ func TestCreateProjectKeyHandler_InvalidClaimsType(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-123/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -554,7 +637,7 @@ func TestCreateProjectKeyHandler_MissingProjectID(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects//keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -579,7 +662,7 @@ func TestCreateProjectKeyHandler_InvalidRequestBody(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-123/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -604,7 +687,7 @@ func TestCreateProjectKeyHandler_MissingLabel(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-123/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -634,7 +717,7 @@ func TestCreateProjectKeyHandler_ProjectNotFound(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
@@ -668,7 +751,7 @@ func TestCreateProjectKeyHandler_InvalidProjectID(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -708,7 +791,7 @@ func TestCreateProjectKeyHandler_NotOwner(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, newMemberStoreWithRole(projectID, otherUserID, ""), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusForbidden, w.Code)
@@ -748,7 +831,7 @@ func TestCreateProjectKeyHandler_DatabaseError(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -779,6 +862,7 @@ func TestCreateProjectKeyHandler_WithCustomEnv(t *testing.T) {
 		ProjectID:         projectID,
 		Label:             "staging-key",
 		Env:               "staging",
+		Scopes:            []string{"ingest"},
 		CreatedBy:         userID,
 		CreatedAt:         time.Now(),
 	}
@@ -797,10 +881,529 @@ func TestCreateProjectKeyHandler_WithCustomEnv(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := CreateProjectKeyHandler(mockProjectStore, mockKeyStore)
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
 	handler(c)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "pk_test_")
+	assert.Contains(t, w.Body.String(), "psk_test_")
 	mockProjectStore.AssertExpectations(t)
 	mockKeyStore.AssertExpectations(t)
 }
+
+// TestCreateProjectKeyHandler_PersistsScopes tests that requested scopes are stored with the key
+func TestCreateProjectKeyHandler_PersistsScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockKeyStore := NewMockProjectKeyStore()
+
+	userID := "user-scopes-test"
+	projectID := "proj-456"
+
+	project := &supabase.Project{
+		ID:          projectID,
+		Name:        "test-project",
+		OwnerUserID: userID,
+	}
+
+	mockProjectStore.On("GetProjectByID", mock.Anything, projectID).Return(project, nil)
+
+	keyData := &supabase.ProjectKey{
+		ID:        "key-789",
+		ProjectID: projectID,
+		Label:     "consent-key",
+		Env:       "prod",
+		Scopes:    []string{"ingest", "consent:write"},
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	mockKeyStore.On("CreateProjectKey", mock.Anything, mock.MatchedBy(func(params supabase.CreateProjectKeyParams) bool {
+		return assert.ObjectsAreEqual([]string{"ingest", "consent:write"}, params.Scopes)
+	})).Return(keyData, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: projectID}}
+
+	claims := &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userID},
+	}
+	c.Set(auth.ContextKeyClaims, claims)
+
+	// Duplicate scopes are collapsed
+	requestBody := `{"label":"consent-key","scopes":["ingest","consent:write","ingest"]}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
+	handler(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"scopes":["ingest","consent:write"]`)
+	mockProjectStore.AssertExpectations(t)
+	mockKeyStore.AssertExpectations(t)
+}
+
+// TestCreateProjectKeyHandler_ScopesNotStored tests that a key stored without its scopes is
+// disabled and its secret withheld
+func TestCreateProjectKeyHandler_ScopesNotStored(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockKeyStore := NewMockProjectKeyStore()
+	mockKeyStore.On("CreateProjectKey", mock.Anything, mock.AnythingOfType("supabase.CreateProjectKeyParams")).
+		Return(&supabase.ProjectKey{ID: "key-noscopes", ProjectID: memberTestProjectID, Label: "ci", Env: "prod", Scopes: []string{"ingest"}}, nil)
+	mockKeyStore.On("DisableProjectKey", mock.Anything, "key-noscopes").Return(nil)
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/keys", `{"label":"ci","scopes":["ingest","consent:write"]}`, memberTestProjectID, memberTestOwnerID)
+	CreateProjectKeyHandler(newMemberTestProjectStore(), newMemberStoreWithRole(memberTestProjectID, memberTestOwnerID, supabase.MemberRoleOwner), mockKeyStore)(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "project_secret")
+	mockKeyStore.AssertExpectations(t)
+}
+
+// TestCreateProjectKeyHandler_UnknownScope tests rejection of scopes outside the defined set
+func TestCreateProjectKeyHandler_UnknownScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockKeyStore := NewMockProjectKeyStore()
+
+	userID := "user-badscope-test"
+	projectID := "proj-456"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: projectID}}
+
+	claims := &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userID},
+	}
+	c.Set(auth.ContextKeyClaims, claims)
+
+	requestBody := `{"label":"test-key","scopes":["admin"]}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
+	handler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "bad_request")
+	assert.Contains(t, w.Body.String(), "admin")
+	mockProjectStore.AssertNotCalled(t, "GetProjectByID")
+	mockKeyStore.AssertNotCalled(t, "CreateProjectKey")
+}
+
+// TestCreateProjectKeyHandler_InvalidEnv tests that env is validated before any database access
+func TestCreateProjectKeyHandler_InvalidEnv(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockKeyStore := NewMockProjectKeyStore()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: "proj-456"}}
+
+	claims := &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user-badenv-test"},
+	}
+	c.Set(auth.ContextKeyClaims, claims)
+
+	requestBody := `{"label":"test-key","env":"production"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-456/keys", bytes.NewBufferString(requestBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
+	handler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "bad_request")
+	mockProjectStore.AssertNotCalled(t, "GetProjectByID")
+	mockKeyStore.AssertNotCalled(t, "CreateProjectKey")
+}
+
+// TestCreateProjectKeyHandler_WithExpiry tests that expires_at is stored with the key
+func TestCreateProjectKeyHandler_WithExpiry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockKeyStore := NewMockProjectKeyStore()
+
+	userID := "user-expiry-test"
+	projectID := "proj-456"
+	expiresAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	project := &supabase.Project{
+		ID:          projectID,
+		Name:        "test-project",
+		OwnerUserID: userID,
+	}
+
+	mockProjectStore.On("GetProjectByID", mock.Anything, projectID).Return(project, nil)
+
+	keyData := &supabase.ProjectKey{
+		ID:        "key-789",
+		ProjectID: projectID,
+		Label:     "ci-key",
+		Env:       "prod",
+		Scopes:    []string{"ingest"},
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		ExpiresAt: &expiresAt,
+	}
+	mockKeyStore.On("CreateProjectKey", mock.Anything, mock.MatchedBy(func(params supabase.CreateProjectKeyParams) bool {
+		return params.ExpiresAt != nil && params.ExpiresAt.Equal(expiresAt)
+	})).Return(keyData, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: projectID}}
+
+	claims := &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userID},
+	}
+	c.Set(auth.ContextKeyClaims, claims)
+
+	requestBody := `{"label":"ci-key","expires_at":"` + expiresAt.Format(time.RFC3339) + `"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+projectID+"/keys", bytes.NewBufferString(requestBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
+	handler(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"expires_at"`)
+	mockProjectStore.AssertExpectations(t)
+	mockKeyStore.AssertExpectations(t)
+}
+
+// TestCreateProjectKeyHandler_ExpiryInPast tests rejection of an expires_at in the past
+func TestCreateProjectKeyHandler_ExpiryInPast(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockKeyStore := NewMockProjectKeyStore()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: "proj-456"}}
+
+	claims := &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user-pastexpiry-test"},
+	}
+	c.Set(auth.ContextKeyClaims, claims)
+
+	requestBody := `{"label":"ci-key","expires_at":"2020-01-01T00:00:00Z"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-456/keys", bytes.NewBufferString(requestBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler := CreateProjectKeyHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore)
+	handler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockProjectStore.AssertNotCalled(t, "GetProjectByID")
+	mockKeyStore.AssertNotCalled(t, "CreateProjectKey")
+}
+
+// TestListProjectKeysHandler_ExpiryWarning tests that only keys expiring within the window are flagged
+func TestListProjectKeysHandler_ExpiryWarning(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockKeyStore := NewMockProjectKeyStore()
+
+	userID := "user-list-test"
+	projectID := "proj-456"
+	soon := time.Now().Add(3*24*time.Hour + time.Hour)
+	later := time.Now().Add(30 * 24 * time.Hour)
+
+	project := &supabase.Project{
+		ID:          projectID,
+		Name:        "test-project",
+		OwnerUserID: userID,
+	}
+
+	mockProjectStore.On("GetProjectByID", mock.Anything, projectID).Return(project, nil)
+	mockKeyStore.On("ListProjectKeys", mock.Anything, projectID).Return([]supabase.ProjectKey{
+		{ID: "key-soon", ProjectID: projectID, Label: "ci", Env: "prod", Scopes: []string{"ingest"}, SecretFingerprint: "abcd", ExpiresAt: &soon},
+		{ID: "key-later", ProjectID: projectID, Label: "web", Env: "prod", Scopes: []string{"ingest"}, SecretFingerprint: "efgh", ExpiresAt: &later},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: projectID}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+projectID+"/keys", nil)
+
+	claims := &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userID},
+	}
+	c.Set(auth.ContextKeyClaims, claims)
+
+	handler := ListProjectKeysHandler(mockProjectStore, NewMockProjectMemberStore(), mockKeyStore, 7*24*time.Hour)
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"expiry_warning":"expires in 3 days"`)
+	assert.Equal(t, 1, strings.Count(w.Body.String(), "expiry_warning"))
+	assert.NotContains(t, w.Body.String(), "secret_enc")
+	mockProjectStore.AssertExpectations(t)
+	mockKeyStore.AssertExpectations(t)
+}
+
+// TestListProjectKeysHandler_NotOwner tests that non-owners cannot list keys
+func TestListProjectKeysHandler_NotOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockKeyStore := NewMockProjectKeyStore()
+
+	projectID := "proj-456"
+
+	project := &supabase.Project{
+		ID:          projectID,
+		Name:        "test-project",
+		OwnerUserID: "owner-123",
+	}
+
+	mockProjectStore.On("GetProjectByID", mock.Anything, projectID).Return(project, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: projectID}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+projectID+"/keys", nil)
+
+	claims := &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user-999"},
+	}
+	c.Set(auth.ContextKeyClaims, claims)
+
+	handler := ListProjectKeysHandler(mockProjectStore, newMemberStoreWithRole(projectID, "user-999", ""), mockKeyStore, 7*24*time.Hour)
+	handler(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockKeyStore.AssertNotCalled(t, "ListProjectKeys", mock.Anything, mock.Anything)
+}
+
+// TestListProjectKeysHandler_ViewerForbidden tests that viewers cannot see key metadata
+func TestListProjectKeysHandler_ViewerForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockKeyStore := NewMockProjectKeyStore()
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/keys", "", memberTestProjectID, "viewer-keys")
+	handler := ListProjectKeysHandler(newMemberTestProjectStore(), newMemberStoreWithRole(memberTestProjectID, "viewer-keys", supabase.MemberRoleViewer), mockKeyStore, 7*24*time.Hour)
+	handler(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockKeyStore.AssertNotCalled(t, "ListProjectKeys", mock.Anything, mock.Anything)
+}
+
+// TestListProjectsHandler tests that memberships are listed with the caller's role
+func TestListProjectsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := NewMockProjectStore()
+	signedOnly := true
+	mockStore.On("ListProjectsForUser", mock.Anything, "user-list").Return([]supabase.MemberProject{
+		{Role: supabase.MemberRoleOwner, Project: supabase.Project{ID: "proj-a", Name: "a", OwnerUserID: "user-list"}},
+		{Role: supabase.MemberRoleViewer, Project: supabase.Project{ID: "proj-b", Name: "b", OwnerUserID: "other", SignedOnly: &signedOnly}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects", "", "", "user-list")
+
+	ListProjectsHandler(mockStore)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"proj-a","name":"a","owner_user_id":"user-list","role":"owner"`)
+	assert.Contains(t, w.Body.String(), `"role":"viewer","retention_days":null,"signed_only":true`)
+}
+
+// TestListProjectsHandler_DeletedProjects tests that projects pending deletion are listed to
+// owners only
+func TestListProjectsHandler_DeletedProjects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deletedAt := time.Now().Add(-time.Hour)
+	deleted := supabase.Project{ID: "proj-deleted", Name: "gone", OwnerUserID: "owner-deleted", DeletedAt: &deletedAt}
+
+	for _, tt := range []struct {
+		role string
+		want bool
+	}{
+		{supabase.MemberRoleOwner, true},
+		{supabase.MemberRoleAdmin, false},
+		{supabase.MemberRoleViewer, false},
+	} {
+		t.Run(tt.role, func(t *testing.T) {
+			mockStore := NewMockProjectStore()
+			mockStore.On("ListProjectsForUser", mock.Anything, "user-"+tt.role).Return([]supabase.MemberProject{
+				{Role: tt.role, Project: deleted},
+				{Role: tt.role, Project: supabase.Project{ID: "proj-live", Name: "live", OwnerUserID: "owner-deleted"}},
+			}, nil)
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodGet, "/api/v1/projects", "", "", "user-"+tt.role)
+			ListProjectsHandler(mockStore)(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), `"id":"proj-live"`)
+			assert.Equal(t, tt.want, strings.Contains(w.Body.String(), `"id":"proj-deleted"`))
+		})
+	}
+}
+
+// TestGetProjectHandler tests that members see the project with their role and others are rejected
+func TestGetProjectHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID, "", memberTestProjectID, "viewer-get")
+	GetProjectHandler(newMemberTestProjectStore(), newMemberStoreWithRole(memberTestProjectID, "viewer-get", supabase.MemberRoleViewer))(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"role":"viewer"`)
+
+	w = httptest.NewRecorder()
+	c = newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID, "", memberTestProjectID, "stranger-get")
+	GetProjectHandler(newMemberTestProjectStore(), newMemberStoreWithRole(memberTestProjectID, "stranger-get", ""))(c)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// TestUpdateProjectHandler tests validation, roles and the unique name conflict
+func TestUpdateProjectHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		role      string
+		body      string
+		updateErr error
+		want      int
+	}{
+		{"admin renames", supabase.MemberRoleAdmin, `{"name":"renamed"}`, nil, http.StatusOK},
+		{"admin sets retention", supabase.MemberRoleAdmin, `{"retention_days":30,"signed_only":true}`, nil, http.StatusOK},
+		{"empty body", supabase.MemberRoleAdmin, `{}`, nil, http.StatusBadRequest},
+		{"empty name", supabase.MemberRoleAdmin, `{"name":""}`, nil, http.StatusBadRequest},
+		{"zero retention", supabase.MemberRoleAdmin, `{"retention_days":0}`, nil, http.StatusBadRequest},
+		{"viewer", supabase.MemberRoleViewer, `{"name":"renamed"}`, nil, http.StatusForbidden},
+		{"name taken", supabase.MemberRoleAdmin, `{"name":"taken"}`, errors.New(`{"code":"23505","message":"duplicate key value violates unique constraint \"projects_owner_name_unique\""}`), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectStore := newMemberTestProjectStore()
+			if tt.updateErr != nil {
+				projectStore.On("UpdateProject", mock.Anything, memberTestProjectID, mock.Anything).Return(nil, tt.updateErr)
+			} else {
+				projectStore.On("UpdateProject", mock.Anything, memberTestProjectID, mock.Anything).
+					Return(&supabase.Project{ID: memberTestProjectID, Name: "renamed", OwnerUserID: memberTestOwnerID}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodPatch, "/api/v1/projects/"+memberTestProjectID, tt.body, memberTestProjectID, "actor-update")
+
+			UpdateProjectHandler(projectStore, newMemberStoreWithRole(memberTestProjectID, "actor-update", tt.role))(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusBadRequest || tt.want == http.StatusForbidden {
+				projectStore.AssertNotCalled(t, "UpdateProject", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestDeleteProjectHandler tests that only the recorded owner can delete the project, which is soft deleted
+func TestDeleteProjectHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deletedAt := time.Now()
+	purgeAfter := deletedAt.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		actorID string
+		role    string
+		want    int
+	}{
+		{"recorded owner", memberTestOwnerID, "", http.StatusAccepted},
+		{"co-owner", "co-owner-delete", supabase.MemberRoleOwner, http.StatusForbidden},
+		{"admin", "admin-delete", supabase.MemberRoleAdmin, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectStore := newMemberTestProjectStore()
+			projectStore.On("SoftDeleteProject", mock.Anything, memberTestProjectID, mock.MatchedBy(func(at time.Time) bool {
+				return at.After(time.Now().Add(29 * 24 * time.Hour))
+			})).Return(&supabase.Project{ID: memberTestProjectID, OwnerUserID: memberTestOwnerID, DeletedAt: &deletedAt, PurgeAfter: &purgeAfter}, nil)
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodDelete, "/api/v1/projects/"+memberTestProjectID, "", memberTestProjectID, tt.actorID)
+
+			DeleteProjectHandler(projectStore, newMemberStoreWithRole(memberTestProjectID, tt.actorID, tt.role), 30*24*time.Hour)(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusAccepted {
+				assert.Contains(t, w.Body.String(), `"purge_after":"`)
+			} else {
+				projectStore.AssertNotCalled(t, "SoftDeleteProject", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestRestoreProjectHandler tests restoring deleted projects within the grace period
+func TestRestoreProjectHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projectID := "proj-restore"
+	ownerID := "owner-restore"
+	deletedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		actorID    string
+		deleted    bool
+		restoreErr error
+		want       int
+	}{
+		{"owner restores", ownerID, true, nil, http.StatusOK},
+		{"not deleted", ownerID, false, nil, http.StatusConflict},
+		{"grace period over", ownerID, true, errors.New(`{"code":"P0002","message":"project not found"}`), http.StatusNotFound},
+		{"not the owner", "admin-restore", true, nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &supabase.Project{ID: projectID, OwnerUserID: ownerID}
+			if tt.deleted {
+				project.DeletedAt = &deletedAt
+			}
+			projectStore := NewMockProjectStore()
+			projectStore.On("GetProjectByID", mock.Anything, projectID).Return(project, nil)
+			if tt.restoreErr != nil {
+				projectStore.On("RestoreProject", mock.Anything, projectID).Return(nil, tt.restoreErr)
+			} else {
+				projectStore.On("RestoreProject", mock.Anything, projectID).Return(&supabase.Project{ID: projectID, OwnerUserID: ownerID}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+projectID+"/restore", "", projectID, tt.actorID)
+
+			RestoreProjectHandler(projectStore)(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"deleted_at":null`)
+			}
+		})
+	}
+}
+
+// TestAuthorizeProject_DeletedProject tests that projects pending deletion are hidden from other routes
+func TestAuthorizeProject_DeletedProject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deletedAt := time.Now()
+	projectStore := NewMockProjectStore()
+	projectStore.On("GetProjectByID", mock.Anything, "proj-deleted").
+		Return(&supabase.Project{ID: "proj-deleted", OwnerUserID: "owner-deleted", DeletedAt: &deletedAt}, nil)
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/proj-deleted", "", "proj-deleted", "owner-deleted")
+
+	GetProjectHandler(projectStore, NewMockProjectMemberStore())(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// CreateProjectTokenRequest matches the OpenAPI schema
+type CreateProjectTokenRequest struct {
+	Label     string     `json:"label" binding:"required,min=1,max=64"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ProjectTokenResponse matches the OpenAPI ProjectToken schema (never includes the token)
+type ProjectTokenResponse struct {
+	ID         string     `json:"id"`
+	Label      string     `json:"label"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Revoked    bool       `json:"revoked"`
+}
+
+// CreateProjectTokenResponse matches the OpenAPI schema
+type CreateProjectTokenResponse struct {
+	ProjectToken string               `json:"project_token"`
+	Token        ProjectTokenResponse `json:"token"`
+}
+
+// ListProjectTokensResponse matches the OpenAPI schema
+type ListProjectTokensResponse struct {
+	Tokens []ProjectTokenResponse `json:"tokens"`
+}
+
+func newProjectTokenResponse(token supabase.ProjectToken) ProjectTokenResponse {
+	return ProjectTokenResponse{
+		ID:         token.ID,
+		Label:      token.Label,
+		Scopes:     token.Scopes,
+		CreatedBy:  token.CreatedBy,
+		CreatedAt:  token.CreatedAt,
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		Revoked:    token.Revoked,
+	}
+}
+
+// authorizeTokenAdmin resolves the claims and the :id project and checks the caller is at least an admin
+func authorizeTokenAdmin(c *gin.Context, projectStore ProjectStore, memberStore ProjectMemberStore) (*auth.SupabaseClaims, *supabase.Project, bool) {
+	claims, ok := claimsFromContext(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	projectID := c.Param("id")
+	if projectID == "" {
+		apiErr := errors.NewAPIError(errors.ErrBadRequest)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return nil, nil, false
+	}
+
+	project, _, ok := authorizeProject(c, projectStore, memberStore, claims, projectID, supabase.MemberRoleAdmin)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return claims, project, true
+}
+
+// CreateProjectTokenHandler handles POST /api/v1/projects/{id}/tokens
+// Tokens can only be minted from a human session, never by another token.
+func CreateProjectTokenHandler(projectStore ProjectStore, memberStore ProjectMemberStore, tokenStore ProjectTokenStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Reject PAT-authenticated callers
+		if _, isPAT := c.Get(auth.ContextKeyPAT); isPAT {
+			apiErr := errors.NewAPIError(errors.ErrForbidden).WithMessage("Project access tokens cannot create tokens")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req CreateProjectTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		scopes, err := auth.NormalizeTokenScopes(req.Scopes)
+		if err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid scopes: " + err.Error())
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest).WithMessage("Invalid expires_at: must be in the future")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Verify the project exists and the caller is at least an admin
+		claims, project, ok := authorizeTokenAdmin(c, projectStore, memberStore)
+		if !ok {
+			return
+		}
+
+		// 4) Generate the token; only its hash is stored
+		token, err := crypto.GeneratePAT()
+		if err != nil {
+			log.Printf("Failed to generate project token: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		created, err := tokenStore.CreateProjectToken(c.Request.Context(), supabase.CreateProjectTokenParams{
+			ProjectID: project.ID,
+			Label:     req.Label,
+			Scopes:    scopes,
+			TokenHash: crypto.HashSecret(token),
+			CreatedBy: claims.Subject,
+			ExpiresAt: req.ExpiresAt,
+		})
+		if err != nil || created == nil {
+			if err != nil {
+				log.Printf("CreateProjectToken error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Build response with the token (shown only once)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusCreated, CreateProjectTokenResponse{
+			ProjectToken: token,
+			Token:        newProjectTokenResponse(*created),
+		})
+	}
+}
+
+// ListProjectTokensHandler handles GET /api/v1/projects/{id}/tokens
+func ListProjectTokensHandler(projectStore ProjectStore, memberStore ProjectMemberStore, tokenStore ProjectTokenStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Verify the project exists and the caller is at least an admin
+		_, project, ok := authorizeTokenAdmin(c, projectStore, memberStore)
+		if !ok {
+			return
+		}
+
+		// 2) List tokens
+		tokens, err := tokenStore.ListProjectTokens(c.Request.Context(), project.ID)
+		if err != nil {
+			log.Printf("ListProjectTokens error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Build response
+		response := ListProjectTokensResponse{Tokens: make([]ProjectTokenResponse, 0, len(tokens))}
+		for _, token := range tokens {
+			response.Tokens = append(response.Tokens, newProjectTokenResponse(token))
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// RevokeProjectTokenHandler handles DELETE /api/v1/projects/{id}/tokens/{tokenId}
+func RevokeProjectTokenHandler(projectStore ProjectStore, memberStore ProjectMemberStore, tokenStore ProjectTokenStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Verify the project exists and the caller is at least an admin
+		_, project, ok := authorizeTokenAdmin(c, projectStore, memberStore)
+		if !ok {
+			return
+		}
+
+		// 2) Revoke the token
+		tokenID := c.Param("tokenId")
+		if tokenID == "" {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		if err := tokenStore.RevokeProjectToken(c.Request.Context(), project.ID, tokenID); err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "not found") {
+				apiErr := errors.NewAPIError(errors.ErrNotFound)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			log.Printf("RevokeProjectToken error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
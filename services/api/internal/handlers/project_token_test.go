@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/libpulse/platform/services/api/internal/auth"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProjectTokenStore implements handlers.ProjectTokenStore for testing.
+type MockProjectTokenStore struct {
+	mock.Mock
+}
+
+// NewMockProjectTokenStore creates a new mock ProjectTokenStore.
+func NewMockProjectTokenStore() *MockProjectTokenStore {
+	return &MockProjectTokenStore{}
+}
+
+func (m *MockProjectTokenStore) CreateProjectToken(
+	ctx context.Context,
+	params supabase.CreateProjectTokenParams,
+) (*supabase.ProjectToken, error) {
+	args := m.Called(ctx, params)
+	token := args.Get(0)
+	if token == nil {
+		return nil, args.Error(1)
+	}
+	return token.(*supabase.ProjectToken), args.Error(1)
+}
+
+func (m *MockProjectTokenStore) ListProjectTokens(ctx context.Context, projectID string) ([]supabase.ProjectToken, error) {
+	args := m.Called(ctx, projectID)
+	tokens := args.Get(0)
+	if tokens == nil {
+		return nil, args.Error(1)
+	}
+	return tokens.([]supabase.ProjectToken), args.Error(1)
+}
+
+func (m *MockProjectTokenStore) RevokeProjectToken(ctx context.Context, projectID, tokenID string) error {
+	args := m.Called(ctx, projectID, tokenID)
+	return args.Error(0)
+}
+
+// newTokenContext builds a test context for the token endpoints authenticated as userID
+func newTokenContext(w *httptest.ResponseRecorder, method, path, body, projectID, userID string) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: projectID}}
+	c.Request = httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set(auth.ContextKeyClaims, &auth.SupabaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userID},
+	})
+	return c
+}
+
+// TestCreateProjectTokenHandler_Success tests that the token is returned once and only its hash is stored
+func TestCreateProjectTokenHandler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockTokenStore := NewMockProjectTokenStore()
+
+	projectID := "proj-tok"
+	userID := "user-token-create"
+
+	mockProjectStore.On("GetProjectByID", mock.Anything, projectID).
+		Return(&supabase.Project{ID: projectID, OwnerUserID: userID}, nil)
+
+	var stored supabase.CreateProjectTokenParams
+	mockTokenStore.On("CreateProjectToken", mock.Anything, mock.AnythingOfType("supabase.CreateProjectTokenParams")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(supabase.CreateProjectTokenParams) }).
+		Return(&supabase.ProjectToken{ID: "tok-1", ProjectID: projectID, Label: "release", Scopes: []string{"read", "keys:write"}, CreatedBy: userID}, nil)
+
+	w := httptest.NewRecorder()
+	body := `{"label":"release","scopes":["read","keys:write","read"]}`
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+projectID+"/tokens", body, projectID, userID)
+
+	CreateProjectTokenHandler(mockProjectStore, NewMockProjectMemberStore(), mockTokenStore)(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var resp CreateProjectTokenResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NoError(t, crypto.ValidatePAT(resp.ProjectToken))
+	assert.Equal(t, crypto.HashSecret(resp.ProjectToken), stored.TokenHash)
+	assert.Equal(t, []string{"read", "keys:write"}, stored.Scopes)
+	assert.Equal(t, userID, stored.CreatedBy)
+	assert.Equal(t, "tok-1", resp.Token.ID)
+	mockTokenStore.AssertExpectations(t)
+}
+
+// TestCreateProjectTokenHandler_RejectsPAT tests that a token cannot mint further tokens
+func TestCreateProjectTokenHandler_RejectsPAT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockTokenStore := NewMockProjectTokenStore()
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/proj-tok/tokens", `{"label":"x","scopes":["read"]}`, "proj-tok", "user-token-pat")
+	c.Set(auth.ContextKeyPAT, &supabase.ProjectToken{ID: "tok-1", ProjectID: "proj-tok"})
+
+	CreateProjectTokenHandler(mockProjectStore, NewMockProjectMemberStore(), mockTokenStore)(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockProjectStore.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything)
+	mockTokenStore.AssertNotCalled(t, "CreateProjectToken", mock.Anything, mock.Anything)
+}
+
+// TestCreateProjectTokenHandler_InvalidScopes tests that scopes are required and validated
+func TestCreateProjectTokenHandler_InvalidScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, body := range []string{
+		`{"label":"x","scopes":[]}`,
+		`{"label":"x","scopes":["admin"]}`,
+		`{"label":"x","scopes":["ingest"]}`,
+	} {
+		mockProjectStore := NewMockProjectStore()
+		w := httptest.NewRecorder()
+		c := newTokenContext(w, http.MethodPost, "/api/v1/projects/proj-tok/tokens", body, "proj-tok", "user-token-scopes")
+
+		CreateProjectTokenHandler(mockProjectStore, NewMockProjectMemberStore(), NewMockProjectTokenStore())(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		mockProjectStore.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything)
+	}
+}
+
+// TestListProjectTokensHandler_Success tests that listed tokens never expose the hash
+func TestListProjectTokensHandler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockProjectStore := NewMockProjectStore()
+	mockTokenStore := NewMockProjectTokenStore()
+
+	projectID := "proj-tok"
+	userID := "user-token-list"
+	expires := time.Now().Add(24 * time.Hour)
+
+	mockProjectStore.On("GetProjectByID", mock.Anything, projectID).
+		Return(&supabase.Project{ID: projectID, OwnerUserID: userID}, nil)
+	mockTokenStore.On("ListProjectTokens", mock.Anything, projectID).Return([]supabase.ProjectToken{
+		{ID: "tok-1", ProjectID: projectID, Label: "release", Scopes: []string{"read"}, TokenHash: "secret-hash", ExpiresAt: &expires},
+		{ID: "tok-2", ProjectID: projectID, Label: "old", Scopes: []string{"read"}, Revoked: true},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+projectID+"/tokens", "", projectID, userID)
+
+	ListProjectTokensHandler(mockProjectStore, NewMockProjectMemberStore(), mockTokenStore)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, strings.Count(w.Body.String(), `"id":"tok-`))
+	assert.NotContains(t, w.Body.String(), "secret-hash")
+	mockTokenStore.AssertExpectations(t)
+}
+
+// TestRevokeProjectTokenHandler tests revocation and the not-found mapping
+func TestRevokeProjectTokenHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	projectID := "proj-tok"
+	userID := "user-token-revoke"
+
+	tests := []struct {
+		name     string
+		storeErr error
+		want     int
+	}{
+		{"revoked", nil, http.StatusNoContent},
+		{"not found", errors.New("project token not found"), http.StatusNotFound},
+		{"store error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProjectStore := NewMockProjectStore()
+			mockTokenStore := NewMockProjectTokenStore()
+			mockProjectStore.On("GetProjectByID", mock.Anything, projectID).
+				Return(&supabase.Project{ID: projectID, OwnerUserID: userID}, nil)
+			mockTokenStore.On("RevokeProjectToken", mock.Anything, projectID, "tok-1").Return(tt.storeErr)
+
+			w := httptest.NewRecorder()
+			c := newTokenContext(w, http.MethodDelete, "/api/v1/projects/"+projectID+"/tokens/tok-1", "", projectID, userID)
+			c.Params = append(c.Params, gin.Param{Key: "tokenId", Value: "tok-1"})
+
+			RevokeProjectTokenHandler(mockProjectStore, NewMockProjectMemberStore(), mockTokenStore)(c)
+
+			assert.Equal(t, tt.want, c.Writer.Status())
+			mockTokenStore.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/notify"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// Headers carrying the signature of a secret scanning report
+const (
+	HeaderSecretScanningKeyID     = "Github-Public-Key-Identifier"
+	HeaderSecretScanningSignature = "Github-Public-Key-Signature"
+)
+
+// maxSecretScanningBody caps the size of a leak report batch
+const maxSecretScanningBody = 1 << 20
+
+// Secret scanning report labels
+const (
+	labelTruePositive  = "true_positive"
+	labelFalsePositive = "false_positive"
+)
+
+// ReportSignatureVerifier abstracts signature verification of leak reports.
+type ReportSignatureVerifier interface {
+	Verify(ctx context.Context, keyID, signature string, payload []byte) error
+}
+
+// SecretScanningReport is a single leaked-credential match in the partner format
+type SecretScanningReport struct {
+	Token  string `json:"token"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+// SecretScanningResult tells the reporter whether a token was a real credential
+type SecretScanningResult struct {
+	TokenHash string `json:"token_hash"`
+	TokenType string `json:"token_type"`
+	Label     string `json:"label"`
+}
+
+// SecretScanningHandlerConfig holds the dependencies of SecretScanningHandler
+type SecretScanningHandlerConfig struct {
+	Verifier     ReportSignatureVerifier
+	KeyStore     ProjectKeyStore
+	ProjectStore ProjectStore
+	UserStore    UserStore
+	AuditStore   AuditLogStore
+	Notifier     notify.Notifier
+}
+
+// SecretScanningHandler handles POST /webhooks/secret-scanning
+// Every reported LibPulse secret that matches a stored key disables that key.
+func SecretScanningHandler(cfg SecretScanningHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		// 1) Read the raw body, the signature covers it byte for byte
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSecretScanningBody))
+		if err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 2) Verify the report signature
+		keyID := c.GetHeader(HeaderSecretScanningKeyID)
+		signature := c.GetHeader(HeaderSecretScanningSignature)
+		if err := cfg.Verifier.Verify(ctx, keyID, signature, body); err != nil {
+			log.Printf("secret scanning signature error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInvalidSignature)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 3) Parse reports
+		var reports []SecretScanningReport
+		if err := json.Unmarshal(body, &reports); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 4) Match and disable keys
+		results := make([]SecretScanningResult, 0, len(reports))
+		for _, report := range reports {
+			label, err := handleLeakReport(ctx, cfg, report)
+			if err != nil {
+				// Let the reporter retry the batch, disabling is idempotent
+				log.Printf("secret scanning report error: %s", err.Error())
+				apiErr := errors.NewAPIError(errors.ErrInternalError)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+
+			tokenHash := sha256.Sum256([]byte(report.Token))
+			results = append(results, SecretScanningResult{
+				TokenHash: hex.EncodeToString(tokenHash[:]),
+				TokenType: report.Type,
+				Label:     label,
+			})
+		}
+
+		c.JSON(http.StatusOK, results)
+	}
+}
+
+// handleLeakReport disables the key matching a reported secret and returns the report label
+func handleLeakReport(ctx context.Context, cfg SecretScanningHandlerConfig, report SecretScanningReport) (string, error) {
+	// Tokens that fail the offline checksum are not LibPulse secrets
+	if crypto.ValidateSecret(report.Token) != nil {
+		return labelFalsePositive, nil
+	}
+
+	key, err := cfg.KeyStore.GetProjectKeyBySecretHash(ctx, crypto.HashSecret(report.Token))
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return labelFalsePositive, nil
+		}
+		return "", err
+	}
+	if key == nil {
+		return labelFalsePositive, nil
+	}
+
+	// A key suspended by a project deletion is disabled for good, so a restore does not revive it
+	alreadyDisabled := key.Disabled
+	if !alreadyDisabled || key.SuspendedByDeletion {
+		if err := cfg.KeyStore.DisableProjectKey(ctx, key.ID); err != nil {
+			return "", err
+		}
+	}
+
+	entry := supabase.AuditLog{
+		ProjectID:  key.ProjectID,
+		ActorType:  supabase.ActorTypeSystem,
+		Action:     "project_key.leak_reported",
+		Success:    true,
+		StatusCode: http.StatusOK,
+		AuthMode:   supabase.AuthModeSystem,
+		Details: map[string]interface{}{
+			"key_id":           key.ID,
+			"source":           report.Source,
+			"url":              report.URL,
+			"already_disabled": alreadyDisabled,
+		},
+	}
+	if err := cfg.AuditStore.CreateAuditLog(ctx, entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+
+	if !alreadyDisabled {
+		notifyKeyLeaked(ctx, cfg, key, report)
+	}
+
+	return labelTruePositive, nil
+}
+
+// notifyKeyLeaked tells the project owner about a disabled key. Failures are logged only,
+// the key is already disabled.
+func notifyKeyLeaked(ctx context.Context, cfg SecretScanningHandlerConfig, key *supabase.ProjectKey, report SecretScanningReport) {
+	project, err := cfg.ProjectStore.GetProjectByID(ctx, key.ProjectID)
+	if err != nil || project == nil {
+		log.Printf("notify key leak: project lookup failed for key %s", key.ID)
+		return
+	}
+
+	owner, err := cfg.UserStore.GetUserByID(ctx, project.OwnerUserID)
+	if err != nil || owner == nil {
+		log.Printf("notify key leak: owner lookup failed for project %s", project.ID)
+		return
+	}
+
+	notice := notify.KeyLeakNotice{
+		OwnerEmail:  owner.Email,
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		KeyID:       key.ID,
+		KeyLabel:    key.Label,
+		Source:      report.Source,
+		URL:         report.URL,
+	}
+	if err := cfg.Notifier.NotifyKeyLeaked(ctx, notice); err != nil {
+		log.Printf("notify key leak error: %s", err.Error())
+	}
+}
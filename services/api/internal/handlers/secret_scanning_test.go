@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libpulse/platform/services/api/internal/notify"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditLogStore implements handlers.AuditLogStore for testing.
+type MockAuditLogStore struct {
+	mock.Mock
+}
+
+// CreateAuditLog mocks AuditLogStore.CreateAuditLog.
+func (m *MockAuditLogStore) CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+// MockVerifier implements handlers.ReportSignatureVerifier for testing.
+type MockVerifier struct {
+	mock.Mock
+}
+
+// Verify mocks ReportSignatureVerifier.Verify.
+func (m *MockVerifier) Verify(ctx context.Context, keyID, signature string, payload []byte) error {
+	args := m.Called(ctx, keyID, signature, payload)
+	return args.Error(0)
+}
+
+// MockNotifier implements notify.Notifier for testing.
+type MockNotifier struct {
+	mock.Mock
+}
+
+// NotifyKeyLeaked mocks Notifier.NotifyKeyLeaked.
+func (m *MockNotifier) NotifyKeyLeaked(ctx context.Context, notice notify.KeyLeakNotice) error {
+	args := m.Called(ctx, notice)
+	return args.Error(0)
+}
+
+// NotifyInvitation mocks Notifier.NotifyInvitation.
+func (m *MockNotifier) NotifyInvitation(ctx context.Context, notice notify.InvitationNotice) error {
+	args := m.Called(ctx, notice)
+	return args.Error(0)
+}
+
+type secretScanningMocks struct {
+	verifier *MockVerifier
+	keys     *MockProjectKeyStore
+	projects *MockProjectStore
+	users    *MockUserStore
+	audit    *MockAuditLogStore
+	notifier *MockNotifier
+}
+
+func newSecretScanningMocks() *secretScanningMocks {
+	return &secretScanningMocks{
+		verifier: &MockVerifier{},
+		keys:     NewMockProjectKeyStore(),
+		projects: NewMockProjectStore(),
+		users:    NewMockUserStore(),
+		audit:    &MockAuditLogStore{},
+		notifier: &MockNotifier{},
+	}
+}
+
+func (m *secretScanningMocks) handler() gin.HandlerFunc {
+	return SecretScanningHandler(SecretScanningHandlerConfig{
+		Verifier:     m.verifier,
+		KeyStore:     m.keys,
+		ProjectStore: m.projects,
+		UserStore:    m.users,
+		AuditStore:   m.audit,
+		Notifier:     m.notifier,
+	})
+}
+
+func newSecretScanningContext(w *httptest.ResponseRecorder, body string) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/webhooks/secret-scanning", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set(HeaderSecretScanningKeyID, "key-id")
+	c.Request.Header.Set(HeaderSecretScanningSignature, "sig")
+	return c
+}
+
+// TestSecretScanningHandler_DisablesLeakedKey tests the full leak report flow
+func TestSecretScanningHandler_DisablesLeakedKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := newSecretScanningMocks()
+
+	secret, _ := crypto.GenerateSecret(crypto.EnvProd)
+	body := `[{"token":"` + secret + `","type":"libpulse_secret","url":"https://github.com/o/r/blob/x","source":"content"}]`
+
+	key := &supabase.ProjectKey{ID: "key-1", ProjectID: "proj-1", Label: "ci"}
+
+	m.verifier.On("Verify", mock.Anything, "key-id", "sig", []byte(body)).Return(nil)
+	m.keys.On("GetProjectKeyBySecretHash", mock.Anything, crypto.HashSecret(secret)).Return(key, nil)
+	m.keys.On("DisableProjectKey", mock.Anything, "key-1").Return(nil)
+	m.audit.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry supabase.AuditLog) bool {
+		return entry.ProjectID == "proj-1" && entry.ActorType == supabase.ActorTypeSystem && entry.AuthMode == supabase.AuthModeSystem
+	})).Return(nil)
+	m.projects.On("GetProjectByID", mock.Anything, "proj-1").Return(&supabase.Project{ID: "proj-1", Name: "demo", OwnerUserID: "owner-1"}, nil)
+	m.users.On("GetUserByID", mock.Anything, "owner-1").Return(&supabase.User{ID: "owner-1", Email: "owner@example.com"}, nil)
+	m.notifier.On("NotifyKeyLeaked", mock.Anything, mock.MatchedBy(func(n notify.KeyLeakNotice) bool {
+		return n.OwnerEmail == "owner@example.com" && n.KeyID == "key-1"
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	c := newSecretScanningContext(w, body)
+	m.handler()(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"label":"true_positive"`)
+	assert.NotContains(t, w.Body.String(), secret)
+	m.keys.AssertExpectations(t)
+	m.audit.AssertExpectations(t)
+	m.notifier.AssertExpectations(t)
+}
+
+// TestSecretScanningHandler_InvalidSignature tests that unsigned reports are rejected
+func TestSecretScanningHandler_InvalidSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := newSecretScanningMocks()
+
+	body := `[{"token":"psk_live_x","type":"libpulse_secret"}]`
+	m.verifier.On("Verify", mock.Anything, "key-id", "sig", []byte(body)).Return(errors.New("bad signature"))
+
+	w := httptest.NewRecorder()
+	c := newSecretScanningContext(w, body)
+	m.handler()(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_signature")
+	m.keys.AssertNotCalled(t, "GetProjectKeyBySecretHash", mock.Anything, mock.Anything)
+}
+
+// TestSecretScanningHandler_FalsePositives tests malformed and unknown tokens
+func TestSecretScanningHandler_FalsePositives(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := newSecretScanningMocks()
+
+	unknown, _ := crypto.GenerateSecret(crypto.EnvProd)
+	body := `[{"token":"psk_live_notachecksummedsecret","type":"libpulse_secret"},{"token":"` + unknown + `","type":"libpulse_secret"}]`
+
+	m.verifier.On("Verify", mock.Anything, "key-id", "sig", []byte(body)).Return(nil)
+	m.keys.On("GetProjectKeyBySecretHash", mock.Anything, crypto.HashSecret(unknown)).Return(nil, errors.New("project key not found"))
+
+	w := httptest.NewRecorder()
+	c := newSecretScanningContext(w, body)
+	m.handler()(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "true_positive")
+	m.keys.AssertNumberOfCalls(t, "GetProjectKeyBySecretHash", 1)
+	m.keys.AssertNotCalled(t, "DisableProjectKey", mock.Anything, mock.Anything)
+	m.audit.AssertNotCalled(t, "CreateAuditLog", mock.Anything, mock.Anything)
+}
+
+// TestSecretScanningHandler_SuspendedKey tests that a key suspended by a project deletion is
+// disabled for good, so restoring the project does not re-enable it
+func TestSecretScanningHandler_SuspendedKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := newSecretScanningMocks()
+
+	secret, _ := crypto.GenerateSecret(crypto.EnvProd)
+	body := `[{"token":"` + secret + `","type":"libpulse_secret","url":"https://github.com/o/r/blob/x","source":"content"}]`
+
+	key := &supabase.ProjectKey{ID: "key-2", ProjectID: "proj-2", Label: "ci", Disabled: true, SuspendedByDeletion: true}
+
+	m.verifier.On("Verify", mock.Anything, "key-id", "sig", []byte(body)).Return(nil)
+	m.keys.On("GetProjectKeyBySecretHash", mock.Anything, crypto.HashSecret(secret)).Return(key, nil)
+	m.keys.On("DisableProjectKey", mock.Anything, "key-2").Return(nil)
+	m.audit.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	c := newSecretScanningContext(w, body)
+	m.handler()(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"label":"true_positive"`)
+	m.keys.AssertExpectations(t)
+	m.notifier.AssertNotCalled(t, "NotifyKeyLeaked", mock.Anything, mock.Anything)
+}
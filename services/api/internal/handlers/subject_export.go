@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+	"github.com/libpulse/platform/services/api/internal/utils/errors"
+)
+
+// SubjectExportHandlerConfig holds the dependencies of the data subject access export handlers
+type SubjectExportHandlerConfig struct {
+	ProjectStore ProjectStore
+	MemberStore  ProjectMemberStore
+	ExportStore  SubjectExportStore
+	Objects      SignedURLCreator
+	AuditStore   AuditLogStore
+	UserIDs      UserIDPseudonymizer
+	// URLTTL is how long a download URL stays valid; never past the bundle's own expiry
+	URLTTL time.Duration
+}
+
+// CreateSubjectExportRequest matches the OpenAPI schema. It carries exactly one of UserIDH and
+// UserID, a raw identifier hashed the way ingestion hashes it.
+type CreateSubjectExportRequest struct {
+	UserIDH string `json:"user_id_h" binding:"omitempty,max=128"`
+	UserID  string `json:"user_id" binding:"omitempty,max=256"`
+}
+
+// SubjectExportResponse matches the OpenAPI schema. It never includes the subject's identifier.
+type SubjectExportResponse struct {
+	ID                     string     `json:"id"`
+	ProjectID              string     `json:"project_id"`
+	Status                 string     `json:"status"`
+	RequestedBy            string     `json:"requested_by"`
+	CreatedAt              time.Time  `json:"created_at"`
+	StartedAt              *time.Time `json:"started_at,omitempty"`
+	CompletedAt            *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt              *time.Time `json:"expires_at,omitempty"`
+	EventsExported         int        `json:"events_exported"`
+	ConsentsExported       int        `json:"consents_exported"`
+	ConsentHistoryExported int        `json:"consent_history_exported"`
+	Error                  *string    `json:"error,omitempty"`
+	DownloadURL            *string    `json:"download_url,omitempty"`
+	DownloadURLExpiresAt   *time.Time `json:"download_url_expires_at,omitempty"`
+}
+
+func newSubjectExportResponse(export supabase.SubjectExport) SubjectExportResponse {
+	return SubjectExportResponse{
+		ID:                     export.ID,
+		ProjectID:              export.ProjectID,
+		Status:                 export.Status,
+		RequestedBy:            export.RequestedBy,
+		CreatedAt:              export.CreatedAt,
+		StartedAt:              export.StartedAt,
+		CompletedAt:            export.CompletedAt,
+		ExpiresAt:              export.ExpiresAt,
+		EventsExported:         export.EventsExported,
+		ConsentsExported:       export.ConsentsExported,
+		ConsentHistoryExported: export.ConsentHistoryExported,
+		Error:                  export.Error,
+	}
+}
+
+// CreateSubjectExportHandler handles POST /api/v1/projects/{id}/subject-exports
+// The bundle is built in the background; the response is the export to poll for its status.
+func CreateSubjectExportHandler(cfg SubjectExportHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Parse and validate request body
+		var req CreateSubjectExportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiErr := errors.NewAPIError(errors.ErrBadRequest)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+		if !validSubject(c, req.UserIDH, req.UserID) {
+			return
+		}
+
+		// 3) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 4) Queue the export
+		userIDH, ok := subjectUserIDH(c, cfg.UserIDs, project.ID, req.UserIDH, req.UserID)
+		if !ok {
+			return
+		}
+		subjectHash := crypto.HashSubject(userIDH)
+		export, err := cfg.ExportStore.CreateSubjectExport(c.Request.Context(), supabase.CreateSubjectExportParams{
+			ProjectID:   project.ID,
+			UserIDH:     userIDH,
+			SubjectHash: subjectHash,
+			RequestedBy: claims.Subject,
+		})
+		if err != nil || export == nil {
+			if err != nil {
+				log.Printf("CreateSubjectExport error: %s", err.Error())
+			}
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		// 5) Audit by subject hash only
+		auditSubjectExport(c, cfg, project.ID, claims.Subject, "subject.access_export_requested", http.StatusAccepted, export)
+
+		c.JSON(http.StatusAccepted, newSubjectExportResponse(*export))
+	}
+}
+
+// GetSubjectExportHandler handles GET /api/v1/projects/{id}/subject-exports/{exportId}
+// Once the bundle is ready, each call returns a fresh signed download URL.
+func GetSubjectExportHandler(cfg SubjectExportHandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1) Ensure authentication
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			return
+		}
+
+		// 2) Verify the project exists and the caller is at least an admin
+		project, _, ok := authorizeProject(c, cfg.ProjectStore, cfg.MemberStore, claims, c.Param("id"), supabase.MemberRoleAdmin)
+		if !ok {
+			return
+		}
+
+		// 3) Load the export
+		export, err := cfg.ExportStore.GetSubjectExport(c.Request.Context(), project.ID, c.Param("exportId"))
+		if err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "not found") {
+				apiErr := errors.NewAPIError(errors.ErrNotFound).WithMessage("Subject export not found")
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			log.Printf("GetSubjectExport error: %s", err.Error())
+			apiErr := errors.NewAPIError(errors.ErrInternalError)
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+
+		resp := newSubjectExportResponse(*export)
+
+		// 4) Sign a download URL while the bundle is available
+		now := time.Now()
+		if export.Status == supabase.SubjectExportStatusCompleted && export.ObjectPath != nil &&
+			export.ExpiresAt != nil && export.ExpiresAt.After(now) {
+			ttl := min(cfg.URLTTL, export.ExpiresAt.Sub(now))
+			url, err := cfg.Objects.CreateSignedURL(c.Request.Context(), supabase.SubjectExportBucket, *export.ObjectPath, ttl)
+			if err != nil {
+				log.Printf("CreateSignedURL error: %s", err.Error())
+				apiErr := errors.NewAPIError(errors.ErrInternalError)
+				c.JSON(apiErr.StatusCode(), apiErr)
+				return
+			}
+			urlExpiresAt := now.Add(ttl)
+			resp.DownloadURL = &url
+			resp.DownloadURLExpiresAt = &urlExpiresAt
+
+			// Handing out the bundle is an access to personal data
+			auditSubjectExport(c, cfg, project.ID, claims.Subject, "subject.access_export_downloaded", http.StatusOK, export)
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func auditSubjectExport(c *gin.Context, cfg SubjectExportHandlerConfig, projectID, actorID, action string, statusCode int, export *supabase.SubjectExport) {
+	entry := supabase.AuditLog{
+		ProjectID:  projectID,
+		ActorType:  supabase.ActorTypeUser,
+		ActorID:    &actorID,
+		Action:     action,
+		Success:    true,
+		StatusCode: statusCode,
+		AuthMode:   supabase.AuthModeJWT,
+		Details: map[string]interface{}{
+			"subject_export_id": export.ID,
+			"subject_hash":      export.SubjectHash,
+		},
+	}
+	if err := cfg.AuditStore.CreateAuditLog(c.Request.Context(), entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+}
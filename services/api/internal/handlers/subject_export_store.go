@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// SubjectExportStore abstracts data subject access exports for handlers, enabling dependency injection and unit testing.
+type SubjectExportStore interface {
+	CreateSubjectExport(ctx context.Context, params supabase.CreateSubjectExportParams) (*supabase.SubjectExport, error)
+	GetSubjectExport(ctx context.Context, projectID, exportID string) (*supabase.SubjectExport, error)
+}
+
+// SignedURLCreator issues temporary download URLs for stored objects.
+type SignedURLCreator interface {
+	CreateSignedURL(ctx context.Context, bucket, path string, expiresIn time.Duration) (string, error)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+)
+
+// MockSubjectExportStore implements handlers.SubjectExportStore for testing.
+type MockSubjectExportStore struct {
+	mock.Mock
+}
+
+func (m *MockSubjectExportStore) CreateSubjectExport(ctx context.Context, params supabase.CreateSubjectExportParams) (*supabase.SubjectExport, error) {
+	args := m.Called(ctx, params)
+	export := args.Get(0)
+	if export == nil {
+		return nil, args.Error(1)
+	}
+	return export.(*supabase.SubjectExport), args.Error(1)
+}
+
+func (m *MockSubjectExportStore) GetSubjectExport(ctx context.Context, projectID, exportID string) (*supabase.SubjectExport, error) {
+	args := m.Called(ctx, projectID, exportID)
+	export := args.Get(0)
+	if export == nil {
+		return nil, args.Error(1)
+	}
+	return export.(*supabase.SubjectExport), args.Error(1)
+}
+
+// MockSignedURLCreator implements handlers.SignedURLCreator for testing.
+type MockSignedURLCreator struct {
+	mock.Mock
+}
+
+func (m *MockSignedURLCreator) CreateSignedURL(ctx context.Context, bucket, path string, expiresIn time.Duration) (string, error) {
+	args := m.Called(ctx, bucket, path, expiresIn)
+	return args.String(0), args.Error(1)
+}
+
+// TestCreateSubjectExportHandler_Accepted tests that an export is queued and audited by hash only
+func TestCreateSubjectExportHandler_Accepted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	subject := "uh-export-me"
+	subjectHash := crypto.HashSubject(subject)
+
+	store := &MockSubjectExportStore{}
+	store.On("CreateSubjectExport", mock.Anything, supabase.CreateSubjectExportParams{
+		ProjectID: memberTestProjectID, UserIDH: subject, SubjectHash: subjectHash, RequestedBy: "admin-user",
+	}).Return(&supabase.SubjectExport{
+		ID: "se-1", ProjectID: memberTestProjectID, UserIDH: &subject, SubjectHash: subjectHash,
+		Status: supabase.SubjectExportStatusPending, RequestedBy: "admin-user", CreatedAt: time.Now(),
+	}, nil)
+	audit := &MockAuditLogStore{}
+	audit.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(e supabase.AuditLog) bool {
+		return e.Action == "subject.access_export_requested" && e.Details["subject_hash"] == subjectHash
+	})).Return(nil)
+
+	cfg := SubjectExportHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+		ExportStore:  store,
+		AuditStore:   audit,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/subject-exports", `{"user_id_h":"uh-export-me"}`, memberTestProjectID, "admin-user")
+	CreateSubjectExportHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	assert.NotContains(t, w.Body.String(), subject)
+	store.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+// TestCreateSubjectExportHandler_RawUserID tests that a raw user_id is hashed with the project's
+// salt before the export is queued, and that a request naming both identifiers is rejected
+func TestCreateSubjectExportHandler_RawUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rawID := "bob@example.com"
+	userIDH := "uh-salted-bob"
+	subjectHash := crypto.HashSubject(userIDH)
+
+	ids := &MockUserIDPseudonymizer{}
+	ids.On("UserIDH", mock.Anything, memberTestProjectID, rawID).Return(userIDH, nil)
+	store := &MockSubjectExportStore{}
+	store.On("CreateSubjectExport", mock.Anything, supabase.CreateSubjectExportParams{
+		ProjectID: memberTestProjectID, UserIDH: userIDH, SubjectHash: subjectHash, RequestedBy: "admin-user",
+	}).Return(&supabase.SubjectExport{
+		ID: "se-2", ProjectID: memberTestProjectID, UserIDH: &userIDH, SubjectHash: subjectHash,
+		Status: supabase.SubjectExportStatusPending, RequestedBy: "admin-user", CreatedAt: time.Now(),
+	}, nil)
+	audit := &MockAuditLogStore{}
+	audit.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(e supabase.AuditLog) bool {
+		for _, v := range e.Details {
+			if v == rawID {
+				return false
+			}
+		}
+		return e.Details["subject_hash"] == subjectHash
+	})).Return(nil)
+
+	cfg := SubjectExportHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+		ExportStore:  store,
+		AuditStore:   audit,
+		UserIDs:      ids,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/subject-exports", `{"user_id":"bob@example.com"}`, memberTestProjectID, "admin-user")
+	CreateSubjectExportHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.NotContains(t, w.Body.String(), rawID)
+	ids.AssertExpectations(t)
+	store.AssertExpectations(t)
+	audit.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	c = newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/subject-exports", `{"user_id_h":"uh-1","user_id":"bob"}`, memberTestProjectID, "admin-user")
+	CreateSubjectExportHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestCreateSubjectExportHandler_ViewerForbidden tests that viewers cannot export subject data
+func TestCreateSubjectExportHandler_ViewerForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &MockSubjectExportStore{}
+	cfg := SubjectExportHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "viewer-user", supabase.MemberRoleViewer),
+		ExportStore:  store,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodPost, "/api/v1/projects/"+memberTestProjectID+"/subject-exports", `{"user_id_h":"uh-1"}`, memberTestProjectID, "viewer-user")
+	CreateSubjectExportHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	store.AssertNotCalled(t, "CreateSubjectExport", mock.Anything, mock.Anything)
+}
+
+// TestGetSubjectExportHandler_SignsDownloadURL tests that a ready bundle gets a URL no longer-lived than the bundle
+func TestGetSubjectExportHandler_SignsDownloadURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := memberTestProjectID + "/se-1.zip"
+	expiresAt := time.Now().Add(5 * time.Minute)
+	store := &MockSubjectExportStore{}
+	store.On("GetSubjectExport", mock.Anything, memberTestProjectID, "se-1").Return(&supabase.SubjectExport{
+		ID: "se-1", ProjectID: memberTestProjectID, Status: supabase.SubjectExportStatusCompleted,
+		ObjectPath: &path, ExpiresAt: &expiresAt, EventsExported: 7,
+	}, nil)
+	objects := &MockSignedURLCreator{}
+	objects.On("CreateSignedURL", mock.Anything, supabase.SubjectExportBucket, path, mock.MatchedBy(func(ttl time.Duration) bool {
+		return ttl > 0 && ttl <= 5*time.Minute
+	})).Return("https://storage.example/signed?token=abc", nil)
+	audit := &MockAuditLogStore{}
+	audit.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(e supabase.AuditLog) bool {
+		return e.Action == "subject.access_export_downloaded"
+	})).Return(nil)
+
+	cfg := SubjectExportHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+		ExportStore:  store,
+		Objects:      objects,
+		AuditStore:   audit,
+		URLTTL:       15 * time.Minute,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/subject-exports/se-1", "", memberTestProjectID, "admin-user")
+	c.Params = append(c.Params, gin.Param{Key: "exportId", Value: "se-1"})
+	GetSubjectExportHandler(cfg)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"download_url":"https://storage.example/signed?token=abc"`)
+	assert.Contains(t, w.Body.String(), `"events_exported":7`)
+	objects.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+// TestGetSubjectExportHandler_NoURLUntilReady tests that pending and expired exports have no download URL
+func TestGetSubjectExportHandler_NoURLUntilReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &MockSubjectExportStore{}
+	store.On("GetSubjectExport", mock.Anything, memberTestProjectID, "se-pending").Return(&supabase.SubjectExport{
+		ID: "se-pending", ProjectID: memberTestProjectID, Status: supabase.SubjectExportStatusPending,
+	}, nil)
+	store.On("GetSubjectExport", mock.Anything, memberTestProjectID, "se-missing").Return(nil, errors.New("subject export not found"))
+	objects := &MockSignedURLCreator{}
+
+	cfg := SubjectExportHandlerConfig{
+		ProjectStore: newMemberTestProjectStore(),
+		MemberStore:  newMemberStoreWithRole(memberTestProjectID, "admin-user", supabase.MemberRoleAdmin),
+		ExportStore:  store,
+		Objects:      objects,
+		URLTTL:       15 * time.Minute,
+	}
+
+	w := httptest.NewRecorder()
+	c := newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/subject-exports/se-pending", "", memberTestProjectID, "admin-user")
+	c.Params = append(c.Params, gin.Param{Key: "exportId", Value: "se-pending"})
+	GetSubjectExportHandler(cfg)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "download_url")
+	objects.AssertNotCalled(t, "CreateSignedURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	w = httptest.NewRecorder()
+	c = newTokenContext(w, http.MethodGet, "/api/v1/projects/"+memberTestProjectID+"/subject-exports/se-missing", "", memberTestProjectID, "admin-user")
+	c.Params = append(c.Params, gin.Param{Key: "exportId", Value: "se-missing"})
+	GetSubjectExportHandler(cfg)(c)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// ProjectTokenStore abstracts project access token data access for handlers, enabling dependency injection and unit testing.
+type ProjectTokenStore interface {
+	CreateProjectToken(ctx context.Context, params supabase.CreateProjectTokenParams) (*supabase.ProjectToken, error)
+	ListProjectTokens(ctx context.Context, projectID string) ([]supabase.ProjectToken, error)
+	RevokeProjectToken(ctx context.Context, projectID, tokenID string) error
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// OwnershipTransferStore abstracts ownership transfer data access for handlers, enabling dependency injection and unit testing.
+type OwnershipTransferStore interface {
+	CreateOwnershipTransfer(ctx context.Context, params supabase.CreateOwnershipTransferParams) (*supabase.OwnershipTransfer, error)
+	GetPendingOwnershipTransfer(ctx context.Context, projectID string, now time.Time) (*supabase.OwnershipTransfer, error)
+	CancelOwnershipTransfer(ctx context.Context, transferID string) error
+	AcceptOwnershipTransfer(ctx context.Context, transferID, userID string) (*supabase.Project, error)
+}
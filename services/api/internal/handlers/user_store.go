@@ -9,4 +9,5 @@ import (
 // UserStore abstracts user data access for handlers, enabling dependency injection and unit testing.
 type UserStore interface {
 	GetUserByID(ctx context.Context, id string) (*supabase.User, error)
+	GetUserIDByEmail(ctx context.Context, email string) (string, error)
 }
@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/demo"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// DemoProjectStore finds or creates the demo project.
+type DemoProjectStore interface {
+	GetOrCreateDemoProject(ctx context.Context, ownerUserID, name string) (*supabase.Project, error)
+}
+
+// DemoEventStore writes the generated events and removes the previous pass's.
+type DemoEventStore interface {
+	InsertEvents(ctx context.Context, events []supabase.Event) error
+	DeleteEventsWithoutPrefix(ctx context.Context, projectID, keepPrefix string, limit int) (int, error)
+}
+
+// DemoSeeder fills the public demo project with synthetic telemetry. Each pass generates a fresh
+// window of events ending now and then deletes every event whose id was not generated by this
+// pass, so the dashboard always shows recent data and never an empty project.
+type DemoSeeder struct {
+	Projects       DemoProjectStore
+	Events         DemoEventStore
+	OwnerUserID    string
+	ProjectName    string
+	Days           int
+	Users          int
+	SessionsPerDay int
+	BatchSize      int
+}
+
+// SeedOnce replaces the demo project's events with a newly generated set.
+func (d *DemoSeeder) SeedOnce(ctx context.Context) error {
+	project, err := d.Projects.GetOrCreateDemoProject(ctx, d.OwnerUserID, d.ProjectName)
+	if err != nil {
+		return fmt.Errorf("demo seed: demo project: %w", err)
+	}
+
+	now := time.Now()
+	runID := now.UTC().Format("20060102T150405")
+	events := demo.Generate(project.ID, demo.Options{
+		Days:           d.Days,
+		Users:          d.Users,
+		SessionsPerDay: d.SessionsPerDay,
+		Seed:           uint64(now.UnixNano()),
+		Now:            now,
+		RunID:          runID,
+	})
+
+	for start := 0; start < len(events); start += d.BatchSize {
+		end := min(start+d.BatchSize, len(events))
+		if err := d.Events.InsertEvents(ctx, events[start:end]); err != nil {
+			// The previous events are still in place; the next pass cleans up the partial insert
+			return fmt.Errorf("demo seed: insert events: %w", err)
+		}
+	}
+
+	// Events are matched by id rather than by time: the generated history spans the past Days
+	removed := 0
+	for {
+		deleted, err := d.Events.DeleteEventsWithoutPrefix(ctx, project.ID, demo.IDPrefix(runID), d.BatchSize)
+		if err != nil {
+			return fmt.Errorf("demo seed: delete previous events: %w", err)
+		}
+		removed += deleted
+		if deleted < d.BatchSize {
+			break
+		}
+	}
+
+	log.Printf("demo seed: project %s now has %d generated events (%d previous removed)", project.ID, len(events), removed)
+	return nil
+}
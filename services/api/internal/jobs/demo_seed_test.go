@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+type fakeDemoStore struct {
+	inserted   int
+	batches    int
+	ids        []string
+	leftOver   int
+	keepPrefix string
+	ownerUsed  string
+}
+
+func (f *fakeDemoStore) GetOrCreateDemoProject(ctx context.Context, ownerUserID, name string) (*supabase.Project, error) {
+	f.ownerUsed = ownerUserID
+	return &supabase.Project{ID: "proj-demo", Name: name}, nil
+}
+
+func (f *fakeDemoStore) InsertEvents(ctx context.Context, events []supabase.Event) error {
+	f.batches++
+	f.inserted += len(events)
+	for _, event := range events {
+		f.ids = append(f.ids, event.EventID)
+	}
+	return nil
+}
+
+func (f *fakeDemoStore) DeleteEventsWithoutPrefix(ctx context.Context, projectID, keepPrefix string, limit int) (int, error) {
+	f.keepPrefix = keepPrefix
+	n := min(f.leftOver, limit)
+	f.leftOver -= n
+	return n, nil
+}
+
+func TestDemoSeeder_SeedOnce(t *testing.T) {
+	store := &fakeDemoStore{leftOver: 1200}
+	d := &DemoSeeder{Projects: store, Events: store, OwnerUserID: "owner-demo", ProjectName: "LibPulse Demo",
+		Days: 2, Users: 5, SessionsPerDay: 10, BatchSize: 500}
+
+	if err := d.SeedOnce(context.Background()); err != nil {
+		t.Fatalf("SeedOnce error: %v", err)
+	}
+
+	if store.ownerUsed != "owner-demo" {
+		t.Errorf("demo project owner = %q", store.ownerUsed)
+	}
+	if store.inserted == 0 || store.batches != (store.inserted+499)/500 {
+		t.Errorf("inserted %d events in %d batches", store.inserted, store.batches)
+	}
+	if store.leftOver != 0 {
+		t.Errorf("%d previous events left", store.leftOver)
+	}
+	// Previous events are removed, but none of the ones this pass just inserted
+	if store.keepPrefix == "" {
+		t.Fatal("cleanup kept no prefix")
+	}
+	for _, id := range store.ids {
+		if !strings.HasPrefix(id, store.keepPrefix) {
+			t.Fatalf("cleanup deletes event %s inserted by this pass (kept prefix %q)", id, store.keepPrefix)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// maxErasureAttempts is how many runs may fail on a request before it is given up
+const maxErasureAttempts = 5
+
+// ErasureStore abstracts the erasure request and subject data operations of the erasure worker.
+type ErasureStore interface {
+	ListOpenErasureRequests(ctx context.Context, limit int) ([]supabase.ErasureRequest, error)
+	SaveErasureRequest(ctx context.Context, request supabase.ErasureRequest) error
+	DeleteSubjectEvents(ctx context.Context, projectID, userIDH string, limit int) (int, error)
+	DeleteSubjectConsent(ctx context.Context, projectID, userIDH string) (int, int, error)
+}
+
+// ErasureAuditStore records the outcome of each erasure request.
+type ErasureAuditStore interface {
+	CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error
+}
+
+// ErasureWorker carries out data subject erasure requests: it deletes the subject's events in
+// batches of BatchSize, then their consent state and history. A request found running was
+// interrupted and is resumed; deletion is idempotent, so repeating work is harmless. When a request
+// finishes, successfully or after maxErasureAttempts failures, its user_id_h is cleared.
+type ErasureWorker struct {
+	Store     ErasureStore
+	Audit     ErasureAuditStore
+	BatchSize int
+}
+
+// ProcessOnce handles the open erasure requests, oldest first.
+func (w *ErasureWorker) ProcessOnce(ctx context.Context) error {
+	requests, err := w.Store.ListOpenErasureRequests(ctx, 100)
+	if err != nil {
+		return fmt.Errorf("erasure: list requests: %w", err)
+	}
+
+	failed := 0
+	for _, request := range requests {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.process(ctx, request); err != nil {
+			failed++
+			log.Printf("erasure error: request %s: %s", request.ID, err.Error())
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("erasure failed for %d of %d requests", failed, len(requests))
+	}
+	return nil
+}
+
+func (w *ErasureWorker) process(ctx context.Context, request supabase.ErasureRequest) error {
+	if request.UserIDH == nil || *request.UserIDH == "" {
+		// Cannot happen given the table constraints; close it rather than retry forever
+		return w.finish(ctx, request, fmt.Errorf("request has no subject"))
+	}
+
+	now := time.Now()
+	request.Status = supabase.ErasureStatusRunning
+	request.Attempts++
+	if request.StartedAt == nil {
+		request.StartedAt = &now
+	}
+	if err := w.Store.SaveErasureRequest(ctx, request); err != nil {
+		return err
+	}
+
+	runErr := w.erase(ctx, &request)
+	if runErr != nil && request.Attempts < maxErasureAttempts {
+		// Keep the progress made and retry on the next run
+		message := runErr.Error()
+		request.Status = supabase.ErasureStatusPending
+		request.Error = &message
+		if err := w.Store.SaveErasureRequest(ctx, request); err != nil {
+			log.Printf("SaveErasureRequest error: %s", err.Error())
+		}
+		return runErr
+	}
+
+	if err := w.finish(ctx, request, runErr); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// erase deletes the subject's data, adding what was deleted to the request's counters
+func (w *ErasureWorker) erase(ctx context.Context, request *supabase.ErasureRequest) error {
+	for {
+		deleted, err := w.Store.DeleteSubjectEvents(ctx, request.ProjectID, *request.UserIDH, w.BatchSize)
+		if err != nil {
+			return fmt.Errorf("delete events: %w", err)
+		}
+		request.EventsDeleted += deleted
+		if deleted < w.BatchSize {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	consents, history, err := w.Store.DeleteSubjectConsent(ctx, request.ProjectID, *request.UserIDH)
+	if err != nil {
+		return fmt.Errorf("delete consent: %w", err)
+	}
+	request.ConsentsDeleted += consents
+	request.ConsentHistoryDeleted += history
+	return nil
+}
+
+// finish closes the request, clears the identifier and records the outcome in the audit log
+func (w *ErasureWorker) finish(ctx context.Context, request supabase.ErasureRequest, runErr error) error {
+	now := time.Now()
+	request.UserIDH = nil
+	request.CompletedAt = &now
+	request.Status = supabase.ErasureStatusCompleted
+	request.Error = nil
+	action := "subject.erasure_completed"
+	statusCode := http.StatusOK
+	if runErr != nil {
+		message := runErr.Error()
+		request.Status = supabase.ErasureStatusFailed
+		request.Error = &message
+		action = "subject.erasure_failed"
+		statusCode = http.StatusInternalServerError
+	}
+
+	if err := w.Store.SaveErasureRequest(ctx, request); err != nil {
+		return err
+	}
+
+	entry := supabase.AuditLog{
+		ProjectID:  request.ProjectID,
+		ActorType:  supabase.ActorTypeSystem,
+		Action:     action,
+		Success:    runErr == nil,
+		StatusCode: statusCode,
+		AuthMode:   supabase.AuthModeSystem,
+		Details: map[string]interface{}{
+			"erasure_id":              request.ID,
+			"subject_hash":            request.SubjectHash,
+			"events_deleted":          request.EventsDeleted,
+			"consents_deleted":        request.ConsentsDeleted,
+			"consent_history_deleted": request.ConsentHistoryDeleted,
+		},
+	}
+	if err := w.Audit.CreateAuditLog(ctx, entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+type fakeErasureStore struct {
+	open      []supabase.ErasureRequest
+	saved     []supabase.ErasureRequest
+	events    int
+	consentOK bool
+	audits    []supabase.AuditLog
+}
+
+func (f *fakeErasureStore) ListOpenErasureRequests(ctx context.Context, limit int) ([]supabase.ErasureRequest, error) {
+	return f.open, nil
+}
+
+func (f *fakeErasureStore) SaveErasureRequest(ctx context.Context, request supabase.ErasureRequest) error {
+	f.saved = append(f.saved, request)
+	return nil
+}
+
+func (f *fakeErasureStore) DeleteSubjectEvents(ctx context.Context, projectID, userIDH string, limit int) (int, error) {
+	n := min(f.events, limit)
+	f.events -= n
+	return n, nil
+}
+
+func (f *fakeErasureStore) DeleteSubjectConsent(ctx context.Context, projectID, userIDH string) (int, int, error) {
+	if !f.consentOK {
+		return 0, 0, errors.New("connection reset")
+	}
+	return 1, 3, nil
+}
+
+func (f *fakeErasureStore) CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error {
+	f.audits = append(f.audits, entry)
+	return nil
+}
+
+func (f *fakeErasureStore) last() supabase.ErasureRequest {
+	return f.saved[len(f.saved)-1]
+}
+
+func openErasureRequest(attempts int) supabase.ErasureRequest {
+	subject := "uh-erase-me"
+	return supabase.ErasureRequest{
+		ID: "er-1", ProjectID: "proj-1", UserIDH: &subject, SubjectHash: "hash-of-subject",
+		Status: supabase.ErasureStatusPending, Attempts: attempts,
+	}
+}
+
+func TestErasureWorker_Completes(t *testing.T) {
+	store := &fakeErasureStore{open: []supabase.ErasureRequest{openErasureRequest(0)}, events: 25, consentOK: true}
+	w := &ErasureWorker{Store: store, Audit: store, BatchSize: 10}
+
+	if err := w.ProcessOnce(context.Background()); err != nil {
+		t.Fatalf("ProcessOnce error: %v", err)
+	}
+
+	if store.saved[0].Status != supabase.ErasureStatusRunning {
+		t.Errorf("first save status = %s, want running", store.saved[0].Status)
+	}
+	done := store.last()
+	if done.Status != supabase.ErasureStatusCompleted || done.UserIDH != nil || done.CompletedAt == nil {
+		t.Fatalf("request not completed and cleared: %+v", done)
+	}
+	if done.EventsDeleted != 25 || done.ConsentsDeleted != 1 || done.ConsentHistoryDeleted != 3 {
+		t.Errorf("counters = %d/%d/%d, want 25/1/3", done.EventsDeleted, done.ConsentsDeleted, done.ConsentHistoryDeleted)
+	}
+
+	if len(store.audits) != 1 || store.audits[0].Action != "subject.erasure_completed" {
+		t.Fatalf("unexpected audit entries: %+v", store.audits)
+	}
+	for key, value := range store.audits[0].Details {
+		if s, ok := value.(string); ok && strings.Contains(s, "uh-erase-me") {
+			t.Errorf("audit detail %s contains the subject identifier", key)
+		}
+	}
+}
+
+func TestErasureWorker_RetriesThenFails(t *testing.T) {
+	store := &fakeErasureStore{open: []supabase.ErasureRequest{openErasureRequest(0)}, events: 5}
+	w := &ErasureWorker{Store: store, Audit: store, BatchSize: 10}
+
+	if err := w.ProcessOnce(context.Background()); err == nil {
+		t.Fatal("ProcessOnce error = nil, want the consent failure")
+	}
+	retry := store.last()
+	if retry.Status != supabase.ErasureStatusPending || retry.UserIDH == nil || retry.Error == nil || retry.EventsDeleted != 5 {
+		t.Fatalf("failed attempt should stay pending with progress kept: %+v", retry)
+	}
+	if len(store.audits) != 0 {
+		t.Errorf("audited before the request finished: %+v", store.audits)
+	}
+
+	// Last allowed attempt
+	store.open = []supabase.ErasureRequest{openErasureRequest(maxErasureAttempts - 1)}
+	_ = w.ProcessOnce(context.Background())
+	failed := store.last()
+	if failed.Status != supabase.ErasureStatusFailed || failed.UserIDH != nil {
+		t.Fatalf("request should fail and drop the identifier: %+v", failed)
+	}
+	if len(store.audits) != 1 || store.audits[0].Action != "subject.erasure_failed" || store.audits[0].Success {
+		t.Errorf("unexpected audit entries: %+v", store.audits)
+	}
+}
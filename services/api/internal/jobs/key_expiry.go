@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// KeyExpiryStore abstracts the project key operations needed by the expiry sweeper.
+type KeyExpiryStore interface {
+	DisableExpiredProjectKeys(ctx context.Context, now time.Time) (int, error)
+}
+
+// KeyExpirySweeper marks project keys past their expires_at as disabled.
+// Ingestion already rejects expired keys on its own; the sweeper keeps the disabled flag
+// (and therefore dashboards and RLS-backed reads) consistent with that.
+type KeyExpirySweeper struct {
+	Store KeyExpiryStore
+}
+
+// SweepOnce disables every key that has expired as of now.
+func (s *KeyExpirySweeper) SweepOnce(ctx context.Context) error {
+	disabled, err := s.Store.DisableExpiredProjectKeys(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("key expiry sweep: %w", err)
+	}
+	if disabled > 0 {
+		log.Printf("key expiry sweep: disabled %d expired project keys", disabled)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// ProjectPurgeStore lists and removes projects whose deletion grace period has ended.
+type ProjectPurgeStore interface {
+	ListPurgeableProjects(ctx context.Context, now time.Time) ([]supabase.Project, error)
+	PurgeProject(ctx context.Context, projectID string, now time.Time) (bool, error)
+}
+
+// ProjectPurgeEventStore deletes the events of a project in bounded batches.
+type ProjectPurgeEventStore interface {
+	DeleteProjectEvents(ctx context.Context, projectID string, limit int) (int, error)
+}
+
+// ProjectPurgeObjectStore removes files kept in Storage for a project.
+type ProjectPurgeObjectStore interface {
+	DeleteFolder(ctx context.Context, bucket, folder string) (int, error)
+}
+
+// ProjectPurger hard-deletes projects whose deletion grace period has ended,
+// together with their events, keys, tokens, members and consent records.
+// Events are deleted BatchSize rows at a time before the project row itself, so purging
+// a large project never runs as one long cascading statement. Subject-export bundles in Storage
+// are not covered by the cascade, so they are removed first.
+type ProjectPurger struct {
+	Projects  ProjectPurgeStore
+	Events    ProjectPurgeEventStore
+	Objects   ProjectPurgeObjectStore
+	BatchSize int
+}
+
+// PurgeOnce removes every project whose purge_after has passed as of now. A failing project
+// does not stop the pass; PurgeOnce reports how many failed once the others are done.
+func (p *ProjectPurger) PurgeOnce(ctx context.Context) error {
+	now := time.Now()
+	projects, err := p.Projects.ListPurgeableProjects(ctx, now)
+	if err != nil {
+		return fmt.Errorf("project purge: list projects: %w", err)
+	}
+
+	purged, failed := 0, 0
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		ok, err := p.purgeProject(ctx, project.ID, now)
+		if err != nil {
+			failed++
+			log.Printf("project purge error: project %s: %s", project.ID, err.Error())
+			continue
+		}
+		if ok {
+			purged++
+		}
+	}
+
+	if purged > 0 {
+		log.Printf("project purge: removed %d deleted projects", purged)
+	}
+	if failed > 0 {
+		return fmt.Errorf("project purge failed for %d of %d projects", failed, len(projects))
+	}
+	return nil
+}
+
+// purgeProject deletes the project's export bundles, its events batch by batch, then the
+// project row. A failed step leaves the row in place so the next pass retries.
+func (p *ProjectPurger) purgeProject(ctx context.Context, projectID string, now time.Time) (bool, error) {
+	if _, err := p.Objects.DeleteFolder(ctx, supabase.SubjectExportBucket, projectID+"/"); err != nil {
+		return false, fmt.Errorf("delete export bundles: %w", err)
+	}
+
+	for {
+		deleted, err := p.Events.DeleteProjectEvents(ctx, projectID, p.BatchSize)
+		if err != nil {
+			return false, fmt.Errorf("delete events: %w", err)
+		}
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if deleted < p.BatchSize {
+			break
+		}
+	}
+
+	return p.Projects.PurgeProject(ctx, projectID, now)
+}
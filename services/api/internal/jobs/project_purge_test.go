@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+type fakeProjectPurgeStore struct {
+	projects  []supabase.Project
+	remaining map[string]int
+	failOn    string
+	batches   map[string]int
+	purged    []string
+	// events left when each project row was deleted
+	leftAtPurge map[string]int
+	// export bundles per project folder, and the folders still present at purge
+	bundles        map[string]int
+	bundlesFailOn  string
+	folders        []string
+	bundlesAtPurge map[string]int
+}
+
+func (f *fakeProjectPurgeStore) DeleteFolder(ctx context.Context, bucket, folder string) (int, error) {
+	f.folders = append(f.folders, bucket+"/"+folder)
+	if folder == f.bundlesFailOn+"/" {
+		return 0, errors.New("storage unavailable")
+	}
+	projectID := folder[:len(folder)-1]
+	n := f.bundles[projectID]
+	f.bundles[projectID] = 0
+	return n, nil
+}
+
+func (f *fakeProjectPurgeStore) ListPurgeableProjects(ctx context.Context, now time.Time) ([]supabase.Project, error) {
+	return f.projects, nil
+}
+
+func (f *fakeProjectPurgeStore) PurgeProject(ctx context.Context, projectID string, now time.Time) (bool, error) {
+	f.purged = append(f.purged, projectID)
+	f.leftAtPurge[projectID] = f.remaining[projectID]
+	f.bundlesAtPurge[projectID] = f.bundles[projectID]
+	return true, nil
+}
+
+func (f *fakeProjectPurgeStore) DeleteProjectEvents(ctx context.Context, projectID string, limit int) (int, error) {
+	f.batches[projectID]++
+	if projectID == f.failOn {
+		return 0, errors.New("statement timeout")
+	}
+	n := f.remaining[projectID]
+	if n > limit {
+		n = limit
+	}
+	f.remaining[projectID] -= n
+	return n, nil
+}
+
+func TestProjectPurger_PurgeOnce(t *testing.T) {
+	store := &fakeProjectPurgeStore{
+		projects:       []supabase.Project{{ID: "proj-large"}, {ID: "proj-empty"}, {ID: "proj-broken"}},
+		remaining:      map[string]int{"proj-large": 25, "proj-broken": 5},
+		failOn:         "proj-broken",
+		batches:        map[string]int{},
+		leftAtPurge:    map[string]int{},
+		bundles:        map[string]int{},
+		bundlesAtPurge: map[string]int{},
+	}
+	p := &ProjectPurger{Projects: store, Events: store, Objects: store, BatchSize: 10}
+
+	if err := p.PurgeOnce(context.Background()); err == nil {
+		t.Error("PurgeOnce error = nil, want the proj-broken failure reported")
+	}
+
+	// proj-large: 10+10+5, proj-empty: 0, proj-broken: error
+	for id, want := range map[string]int{"proj-large": 3, "proj-empty": 1, "proj-broken": 1} {
+		if store.batches[id] != want {
+			t.Errorf("%s DeleteProjectEvents calls = %d, want %d", id, store.batches[id], want)
+		}
+	}
+
+	if len(store.purged) != 2 || store.purged[0] != "proj-large" || store.purged[1] != "proj-empty" {
+		t.Fatalf("purged projects = %v, want [proj-large proj-empty]", store.purged)
+	}
+	if store.leftAtPurge["proj-large"] != 0 {
+		t.Errorf("proj-large row deleted with %d events left", store.leftAtPurge["proj-large"])
+	}
+}
+
+func TestProjectPurger_PurgeOnce_DeletesExportBundles(t *testing.T) {
+	store := &fakeProjectPurgeStore{
+		projects:       []supabase.Project{{ID: "proj-a"}, {ID: "proj-storage-down"}},
+		remaining:      map[string]int{"proj-storage-down": 3},
+		batches:        map[string]int{},
+		leftAtPurge:    map[string]int{},
+		bundles:        map[string]int{"proj-a": 2, "proj-storage-down": 1},
+		bundlesFailOn:  "proj-storage-down",
+		bundlesAtPurge: map[string]int{},
+	}
+	p := &ProjectPurger{Projects: store, Events: store, Objects: store, BatchSize: 10}
+
+	if err := p.PurgeOnce(context.Background()); err == nil {
+		t.Error("PurgeOnce error = nil, want the storage failure reported")
+	}
+
+	want := []string{supabase.SubjectExportBucket + "/proj-a/", supabase.SubjectExportBucket + "/proj-storage-down/"}
+	if len(store.folders) != 2 || store.folders[0] != want[0] || store.folders[1] != want[1] {
+		t.Fatalf("deleted folders = %v, want %v", store.folders, want)
+	}
+	if len(store.purged) != 1 || store.purged[0] != "proj-a" {
+		t.Fatalf("purged projects = %v, want [proj-a]", store.purged)
+	}
+	if store.bundlesAtPurge["proj-a"] != 0 {
+		t.Errorf("proj-a row deleted with %d export bundles left", store.bundlesAtPurge["proj-a"])
+	}
+	// The project whose bundles could not be removed keeps its events and row for the next pass
+	if store.remaining["proj-storage-down"] != 3 {
+		t.Errorf("proj-storage-down events left = %d, want 3", store.remaining["proj-storage-down"])
+	}
+}
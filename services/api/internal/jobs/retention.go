@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/metrics"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// RetentionProjectStore lists the projects whose events are subject to retention.
+type RetentionProjectStore interface {
+	ListActiveProjects(ctx context.Context) ([]supabase.Project, error)
+}
+
+// RetentionEventStore deletes events older than a cutoff in bounded batches.
+type RetentionEventStore interface {
+	DeleteEventsBefore(ctx context.Context, projectID string, cutoff time.Time, limit int) (int, error)
+}
+
+// RetentionAuditStore records how much data each pass removed.
+type RetentionAuditStore interface {
+	CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error
+}
+
+// RetentionWorker deletes events whose event_ts is older than each project's retention_days.
+// Projects without a setting use DefaultDays, and no project keeps data longer than MaxDays.
+// Events are deleted BatchSize rows at a time so no single statement holds locks for long.
+type RetentionWorker struct {
+	Projects    RetentionProjectStore
+	Events      RetentionEventStore
+	Audit       RetentionAuditStore
+	DefaultDays int
+	MaxDays     int
+	BatchSize   int
+}
+
+// RunOnce enforces retention for every active project as of now. A failing project does not
+// stop the pass; RunOnce reports how many failed once the others are done.
+func (w *RetentionWorker) RunOnce(ctx context.Context) error {
+	projects, err := w.Projects.ListActiveProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("retention: list projects: %w", err)
+	}
+
+	now := time.Now()
+	failed := 0
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		days := w.RetentionDays(project)
+		cutoff := now.Add(-time.Duration(days) * 24 * time.Hour)
+		deleted, err := w.purgeProject(ctx, project.ID, cutoff)
+		if deleted > 0 {
+			metrics.RetentionEventsPurged.Add(project.ID, int64(deleted))
+			w.audit(ctx, project.ID, days, cutoff, deleted)
+		}
+		if err != nil {
+			failed++
+			metrics.RetentionErrors.Add(1)
+			log.Printf("retention error: project %s: %s", project.ID, err.Error())
+			continue
+		}
+		if deleted > 0 {
+			log.Printf("retention: deleted %d events of project %s older than %d days", deleted, project.ID, days)
+		}
+	}
+
+	metrics.RetentionRuns.Add(1)
+	if failed > 0 {
+		return fmt.Errorf("retention failed for %d of %d projects", failed, len(projects))
+	}
+	return nil
+}
+
+// RetentionDays returns the effective retention of a project
+func (w *RetentionWorker) RetentionDays(project supabase.Project) int {
+	days := w.DefaultDays
+	if project.RetentionDays != nil && *project.RetentionDays > 0 {
+		days = *project.RetentionDays
+	}
+	if w.MaxDays > 0 && days > w.MaxDays {
+		days = w.MaxDays
+	}
+	return days
+}
+
+// purgeProject deletes batches until fewer than BatchSize rows remain before cutoff.
+// It returns the rows deleted so far even when a later batch fails.
+func (w *RetentionWorker) purgeProject(ctx context.Context, projectID string, cutoff time.Time) (int, error) {
+	total := 0
+	for {
+		deleted, err := w.Events.DeleteEventsBefore(ctx, projectID, cutoff, w.BatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+		if deleted < w.BatchSize || ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
+
+// audit records a retention pass that removed data. Failures are logged only.
+func (w *RetentionWorker) audit(ctx context.Context, projectID string, days int, cutoff time.Time, deleted int) {
+	entry := supabase.AuditLog{
+		ProjectID:  projectID,
+		ActorType:  supabase.ActorTypeSystem,
+		Action:     "events.retention_purged",
+		Success:    true,
+		StatusCode: http.StatusOK,
+		AuthMode:   supabase.AuthModeSystem,
+		Details: map[string]interface{}{
+			"retention_days": days,
+			"cutoff":         cutoff.UTC().Format(time.RFC3339),
+			"events_deleted": deleted,
+		},
+	}
+	if err := w.Audit.CreateAuditLog(ctx, entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+type fakeRetentionStore struct {
+	projects []supabase.Project
+	// remaining events per project older than any cutoff
+	remaining map[string]int
+	failOn    string
+	cutoffs   map[string]time.Time
+	calls     int
+	audits    []supabase.AuditLog
+}
+
+func (f *fakeRetentionStore) ListActiveProjects(ctx context.Context) ([]supabase.Project, error) {
+	return f.projects, nil
+}
+
+func (f *fakeRetentionStore) DeleteEventsBefore(ctx context.Context, projectID string, cutoff time.Time, limit int) (int, error) {
+	f.calls++
+	f.cutoffs[projectID] = cutoff
+	if projectID == f.failOn {
+		return 0, errors.New("statement timeout")
+	}
+	n := f.remaining[projectID]
+	if n > limit {
+		n = limit
+	}
+	f.remaining[projectID] -= n
+	return n, nil
+}
+
+func (f *fakeRetentionStore) CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error {
+	f.audits = append(f.audits, entry)
+	return nil
+}
+
+func TestRetentionWorker_RunOnce(t *testing.T) {
+	thirty, tooLong := 30, 5000
+	store := &fakeRetentionStore{
+		projects: []supabase.Project{
+			{ID: "proj-default"},
+			{ID: "proj-30", RetentionDays: &thirty},
+			{ID: "proj-capped", RetentionDays: &tooLong},
+			{ID: "proj-broken"},
+		},
+		remaining: map[string]int{"proj-default": 25, "proj-30": 0, "proj-capped": 10},
+		failOn:    "proj-broken",
+		cutoffs:   map[string]time.Time{},
+	}
+	w := &RetentionWorker{Projects: store, Events: store, Audit: store, DefaultDays: 90, MaxDays: 365, BatchSize: 10}
+
+	if err := w.RunOnce(context.Background()); err == nil {
+		t.Error("RunOnce error = nil, want the proj-broken failure reported")
+	}
+
+	if store.remaining["proj-default"] != 0 || store.remaining["proj-capped"] != 0 {
+		t.Fatalf("events left after retention: %v", store.remaining)
+	}
+	// proj-default: 10+10+5, proj-30: 0, proj-capped: 10+0, proj-broken: error
+	if store.calls != 7 {
+		t.Errorf("DeleteEventsBefore calls = %d, want 7", store.calls)
+	}
+
+	for id, days := range map[string]int{"proj-default": 90, "proj-30": 30, "proj-capped": 365} {
+		age := time.Since(store.cutoffs[id])
+		want := time.Duration(days) * 24 * time.Hour
+		if age < want || age > want+time.Minute {
+			t.Errorf("%s cutoff age = %s, want %d days", id, age, days)
+		}
+	}
+
+	if len(store.audits) != 2 {
+		t.Fatalf("audit entries = %d, want 2 (only projects with deletions)", len(store.audits))
+	}
+	if store.audits[0].ProjectID != "proj-default" || store.audits[0].Details["events_deleted"] != 25 {
+		t.Errorf("unexpected audit entry: %+v", store.audits[0])
+	}
+}
@@ -0,0 +1,160 @@
+package jobs
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/metrics"
+)
+
+// leaseClockSkew is added to every lease so replicas with slightly different clocks still see
+// the lease as held when their own timer for the same run fires.
+const leaseClockSkew = time.Minute
+
+// LeaseStore hands out the per-job leases that keep replicas from running the same job twice.
+type LeaseStore interface {
+	AcquireJobLease(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error)
+}
+
+// RunHistoryStore records every job run.
+type RunHistoryStore interface {
+	StartJobRun(ctx context.Context, jobName, holder string) (int64, error)
+	FinishJobRun(ctx context.Context, runID int64, runErr error) error
+}
+
+// Job is a unit of background work run on a schedule.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Timeout bounds a single run; its context is cancelled when it elapses.
+	Timeout time.Duration
+	// Jitter delays each run by a random amount up to this long so replicas do not all hit
+	// the database at the same instant.
+	Jitter time.Duration
+	// RunAtStart also runs the job (subject to its lease) as soon as the scheduler starts.
+	RunAtStart bool
+	Run        func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on their schedules. Every replica runs a scheduler, but a run only
+// happens on the replica that acquires the job's lease, so each job runs once per slot across
+// the deployment. The lease is kept (not released) after the run and lasts for the timeout plus
+// the jitter window, which covers every replica's timer for that slot; the holder simply renews
+// it on the next slot, and when the holder disappears the lease expires and another replica
+// takes over.
+type Scheduler struct {
+	Leases  LeaseStore
+	History RunHistoryStore
+	// Holder identifies this replica in leases and run history.
+	Holder string
+	Jobs   []Job
+}
+
+// NewHolderID returns an identifier for this process, unique across replicas and restarts.
+func NewHolderID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = crand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Run schedules every job until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.Jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// loop waits for each scheduled time of job and runs it. A run that overruns its next slot
+// causes that slot to be skipped rather than queued.
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	if job.RunAtStart {
+		_, _ = s.RunJob(ctx, job)
+	}
+
+	for {
+		next := job.Schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("job %s: schedule never fires, not scheduling", job.Name)
+			return
+		}
+
+		delay := time.Until(next)
+		if job.Jitter > 0 {
+			delay += rand.N(job.Jitter)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		_, _ = s.RunJob(ctx, job)
+	}
+}
+
+// RunJob runs job once if this replica acquires its lease and records the run in the history.
+// It reports whether the job ran here, and the job's error if it did.
+func (s *Scheduler) RunJob(ctx context.Context, job Job) (bool, error) {
+	ttl := job.Timeout + job.Jitter + leaseClockSkew
+	acquired, err := s.Leases.AcquireJobLease(ctx, job.Name, s.Holder, ttl)
+	if err != nil {
+		log.Printf("job %s: acquire lease error: %s", job.Name, err.Error())
+		return false, err
+	}
+	if !acquired {
+		metrics.JobSkips.Add(job.Name, 1)
+		return false, nil
+	}
+
+	// History is best effort; a run is never skipped because it could not be recorded
+	runID, err := s.History.StartJobRun(ctx, job.Name, s.Holder)
+	if err != nil {
+		log.Printf("job %s: StartJobRun error: %s", job.Name, err.Error())
+	}
+
+	started := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	runErr := job.Run(runCtx)
+	if runErr == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		runErr = fmt.Errorf("timed out after %s", job.Timeout)
+	}
+	cancel()
+
+	metrics.JobRuns.Add(job.Name, 1)
+	if runErr != nil {
+		metrics.JobFailures.Add(job.Name, 1)
+		log.Printf("job %s: failed after %s: %s", job.Name, time.Since(started).Round(time.Millisecond), runErr.Error())
+	}
+
+	if runID != 0 {
+		// Record the outcome even when ctx was cancelled by shutdown
+		finishCtx, finishCancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		if err := s.History.FinishJobRun(finishCtx, runID, runErr); err != nil {
+			log.Printf("job %s: FinishJobRun error: %s", job.Name, err.Error())
+		}
+		finishCancel()
+	}
+
+	return true, runErr
+}
@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeJobStore struct {
+	// holder of each job's lease; leases never expire in the fake
+	leases   map[string]string
+	leaseTTL time.Duration
+	started  []string
+	finished map[int64]error
+}
+
+func newFakeJobStore() *fakeJobStore {
+	return &fakeJobStore{leases: map[string]string{}, finished: map[int64]error{}}
+}
+
+func (f *fakeJobStore) AcquireJobLease(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error) {
+	f.leaseTTL = ttl
+	if current, ok := f.leases[jobName]; ok && current != holder {
+		return false, nil
+	}
+	f.leases[jobName] = holder
+	return true, nil
+}
+
+func (f *fakeJobStore) StartJobRun(ctx context.Context, jobName, holder string) (int64, error) {
+	f.started = append(f.started, holder)
+	return int64(len(f.started)), nil
+}
+
+func (f *fakeJobStore) FinishJobRun(ctx context.Context, runID int64, runErr error) error {
+	f.finished[runID] = runErr
+	return nil
+}
+
+func TestScheduler_RunJob_OnlyLeaseHolderRuns(t *testing.T) {
+	store := newFakeJobStore()
+	replicaA := &Scheduler{Leases: store, History: store, Holder: "replica-a"}
+	replicaB := &Scheduler{Leases: store, History: store, Holder: "replica-b"}
+
+	runs := 0
+	job := Job{Name: "retention", Schedule: Every(time.Hour), Timeout: time.Minute, Jitter: 30 * time.Second,
+		Run: func(ctx context.Context) error { runs++; return nil }}
+
+	ran, err := replicaA.RunJob(context.Background(), job)
+	if !ran || err != nil {
+		t.Fatalf("replica-a RunJob = %v, %v; want true, nil", ran, err)
+	}
+	ran, err = replicaB.RunJob(context.Background(), job)
+	if ran || err != nil {
+		t.Fatalf("replica-b RunJob = %v, %v; want false, nil", ran, err)
+	}
+	// The holder renews its own lease on the next slot
+	if ran, _ := replicaA.RunJob(context.Background(), job); !ran {
+		t.Fatal("replica-a could not renew its lease")
+	}
+
+	if runs != 2 {
+		t.Errorf("runs = %d, want 2", runs)
+	}
+	if want := time.Minute + 30*time.Second + leaseClockSkew; store.leaseTTL != want {
+		t.Errorf("lease ttl = %s, want %s", store.leaseTTL, want)
+	}
+	if len(store.started) != 2 || store.finished[1] != nil || store.finished[2] != nil {
+		t.Errorf("unexpected history: started %v finished %v", store.started, store.finished)
+	}
+}
+
+func TestScheduler_RunJob_RecordsFailureAndTimeout(t *testing.T) {
+	store := newFakeJobStore()
+	s := &Scheduler{Leases: store, History: store, Holder: "replica-a"}
+
+	failing := Job{Name: "purge", Schedule: Every(time.Hour), Timeout: time.Minute,
+		Run: func(ctx context.Context) error { return errors.New("boom") }}
+	if _, err := s.RunJob(context.Background(), failing); err == nil || err.Error() != "boom" {
+		t.Fatalf("RunJob error = %v, want boom", err)
+	}
+	if store.finished[1] == nil {
+		t.Error("failed run recorded as success")
+	}
+
+	// A job that ignores its context still has the timeout reported
+	slow := Job{Name: "slow", Schedule: Every(time.Hour), Timeout: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error { time.Sleep(30 * time.Millisecond); return nil }}
+	if _, err := s.RunJob(context.Background(), slow); err == nil {
+		t.Fatal("RunJob error = nil, want timeout")
+	}
+	if store.finished[2] == nil {
+		t.Error("timed out run recorded as success")
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	base := time.Date(2026, 10, 18, 10, 7, 30, 0, time.UTC) // a Sunday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"@every 5m", time.Date(2026, 10, 18, 10, 10, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 18, 10, 15, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2026, 10, 19, 3, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"5,10 10 * * *", time.Date(2026, 10, 18, 10, 10, 0, 0, time.UTC)},
+		// both day fields restricted: the 1st of the month or any Wednesday
+		{"0 0 1 * 3", time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) error: %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(base); !got.Equal(tt.want) {
+			t.Errorf("ParseSchedule(%q).Next = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "5-1 * * * *", "*/0 * * * *", "@every 1ms", "@every soon"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) error = nil, want invalid", spec)
+		}
+	}
+
+	if s, _ := ParseSchedule("0 0 31 2 *"); !s.Next(base).IsZero() {
+		t.Error("impossible schedule should never fire")
+	}
+}
@@ -0,0 +1,264 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/archive"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+// maxSubjectExportAttempts is how many runs may fail on an export before it is given up
+const maxSubjectExportAttempts = 5
+
+// SubjectExportStore abstracts the export request operations of the subject export worker.
+type SubjectExportStore interface {
+	ListOpenSubjectExports(ctx context.Context, limit int) ([]supabase.SubjectExport, error)
+	ListExpiredSubjectExports(ctx context.Context, now time.Time, limit int) ([]supabase.SubjectExport, error)
+	SaveSubjectExport(ctx context.Context, export supabase.SubjectExport) error
+}
+
+// SubjectEventStore reads the events of one end user.
+type SubjectEventStore interface {
+	ListSubjectEvents(ctx context.Context, projectID, userIDH, afterEventID string, limit int) ([]supabase.Event, error)
+}
+
+// SubjectConsentStore reads the consent state and history of one end user.
+type SubjectConsentStore interface {
+	ListSubjectConsent(ctx context.Context, projectID, userIDH string) ([]supabase.Consent, error)
+	ListSubjectConsentHistory(ctx context.Context, projectID, userIDH string) ([]supabase.ConsentHistoryEntry, error)
+}
+
+// SubjectObjectStore holds the generated bundles.
+type SubjectObjectStore interface {
+	UploadObject(ctx context.Context, bucket, path, contentType string, body io.Reader) error
+	DeleteObjects(ctx context.Context, bucket string, paths []string) error
+}
+
+// SubjectExportWorker carries out data subject access exports: it writes every event, consent
+// state and consent history entry of the subject to a zip bundle, uploads it to the private
+// subject-exports bucket and keeps it for TTL. Failed exports are retried like erasure requests;
+// when an export finishes its user_id_h is cleared. Expired bundles are deleted on every run.
+type SubjectExportWorker struct {
+	Exports   SubjectExportStore
+	Events    SubjectEventStore
+	Consents  SubjectConsentStore
+	Objects   SubjectObjectStore
+	Audit     ErasureAuditStore
+	BatchSize int
+	TTL       time.Duration
+}
+
+// ProcessOnce removes expired bundles, then builds the open exports, oldest first.
+func (w *SubjectExportWorker) ProcessOnce(ctx context.Context) error {
+	if err := w.expire(ctx); err != nil {
+		// Expiry is retried on the next run; do not hold up pending exports for it
+		log.Printf("subject export error: expire bundles: %s", err.Error())
+	}
+
+	exports, err := w.Exports.ListOpenSubjectExports(ctx, 100)
+	if err != nil {
+		return fmt.Errorf("subject export: list exports: %w", err)
+	}
+
+	failed := 0
+	for _, export := range exports {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.process(ctx, export); err != nil {
+			failed++
+			log.Printf("subject export error: export %s: %s", export.ID, err.Error())
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("subject export failed for %d of %d exports", failed, len(exports))
+	}
+	return nil
+}
+
+// expire deletes the bundles of completed exports past their expiry
+func (w *SubjectExportWorker) expire(ctx context.Context) error {
+	expired, err := w.Exports.ListExpiredSubjectExports(ctx, time.Now(), 100)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range expired {
+		if export.ObjectPath != nil {
+			if err := w.Objects.DeleteObjects(ctx, supabase.SubjectExportBucket, []string{*export.ObjectPath}); err != nil {
+				return err
+			}
+		}
+		export.Status = supabase.SubjectExportStatusExpired
+		export.ObjectPath = nil
+		if err := w.Exports.SaveSubjectExport(ctx, export); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *SubjectExportWorker) process(ctx context.Context, export supabase.SubjectExport) error {
+	if export.UserIDH == nil || *export.UserIDH == "" {
+		// Cannot happen given the table constraints; close it rather than retry forever
+		return w.finish(ctx, export, fmt.Errorf("export has no subject"))
+	}
+
+	now := time.Now()
+	export.Status = supabase.SubjectExportStatusRunning
+	export.Attempts++
+	if export.StartedAt == nil {
+		export.StartedAt = &now
+	}
+	if err := w.Exports.SaveSubjectExport(ctx, export); err != nil {
+		return err
+	}
+
+	runErr := w.build(ctx, &export)
+	if runErr != nil && export.Attempts < maxSubjectExportAttempts {
+		message := runErr.Error()
+		export.Status = supabase.SubjectExportStatusPending
+		export.Error = &message
+		if err := w.Exports.SaveSubjectExport(ctx, export); err != nil {
+			log.Printf("SaveSubjectExport error: %s", err.Error())
+		}
+		return runErr
+	}
+
+	if err := w.finish(ctx, export, runErr); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// build writes the bundle to a temporary file and uploads it, setting the export's counters and
+// object path. Each attempt starts from scratch, so the counters are reset.
+func (w *SubjectExportWorker) build(ctx context.Context, export *supabase.SubjectExport) error {
+	f, err := os.CreateTemp("", "libpulse-subject-export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	userIDH := *export.UserIDH
+	aw := archive.NewSubjectWriter(f, export.ProjectID, userIDH, time.Now())
+
+	export.EventsExported, export.ConsentsExported, export.ConsentHistoryExported = 0, 0, 0
+
+	if err := aw.Create(archive.FileEvents); err != nil {
+		return err
+	}
+	after := ""
+	for {
+		events, err := w.Events.ListSubjectEvents(ctx, export.ProjectID, userIDH, after, w.BatchSize)
+		if err != nil {
+			return fmt.Errorf("list events: %w", err)
+		}
+		for _, event := range events {
+			if err := aw.Write(event); err != nil {
+				return err
+			}
+		}
+		export.EventsExported += len(events)
+		if len(events) < w.BatchSize {
+			break
+		}
+		after = events[len(events)-1].EventID
+	}
+
+	consents, err := w.Consents.ListSubjectConsent(ctx, export.ProjectID, userIDH)
+	if err != nil {
+		return fmt.Errorf("list consent: %w", err)
+	}
+	if err := aw.Create(archive.FileConsent); err != nil {
+		return err
+	}
+	for _, consent := range consents {
+		if err := aw.Write(archive.ConsentRecord{UserIDH: consent.UserIDH, State: consent.State, UpdatedAt: consent.UpdatedAt}); err != nil {
+			return err
+		}
+	}
+	export.ConsentsExported = len(consents)
+
+	history, err := w.Consents.ListSubjectConsentHistory(ctx, export.ProjectID, userIDH)
+	if err != nil {
+		return fmt.Errorf("list consent history: %w", err)
+	}
+	if err := aw.Create(archive.FileConsentHistory); err != nil {
+		return err
+	}
+	for _, entry := range history {
+		if err := aw.Write(entry); err != nil {
+			return err
+		}
+	}
+	export.ConsentHistoryExported = len(history)
+
+	if err := aw.Close(); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	path := export.ProjectID + "/" + export.ID + ".zip"
+	if err := w.Objects.UploadObject(ctx, supabase.SubjectExportBucket, path, "application/zip", f); err != nil {
+		return fmt.Errorf("upload bundle: %w", err)
+	}
+	export.ObjectPath = &path
+	return nil
+}
+
+// finish closes the export, clears the identifier and records the outcome in the audit log
+func (w *SubjectExportWorker) finish(ctx context.Context, export supabase.SubjectExport, runErr error) error {
+	now := time.Now()
+	expiresAt := now.Add(w.TTL)
+	export.UserIDH = nil
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	export.Status = supabase.SubjectExportStatusCompleted
+	export.Error = nil
+	action := "subject.access_exported"
+	statusCode := http.StatusOK
+	if runErr != nil {
+		message := runErr.Error()
+		export.Status = supabase.SubjectExportStatusFailed
+		export.ExpiresAt = nil
+		export.ObjectPath = nil
+		export.Error = &message
+		action = "subject.access_export_failed"
+		statusCode = http.StatusInternalServerError
+	}
+
+	if err := w.Exports.SaveSubjectExport(ctx, export); err != nil {
+		return err
+	}
+
+	entry := supabase.AuditLog{
+		ProjectID:  export.ProjectID,
+		ActorType:  supabase.ActorTypeSystem,
+		Action:     action,
+		Success:    runErr == nil,
+		StatusCode: statusCode,
+		AuthMode:   supabase.AuthModeSystem,
+		Details: map[string]interface{}{
+			"subject_export_id":        export.ID,
+			"subject_hash":             export.SubjectHash,
+			"events_exported":          export.EventsExported,
+			"consents_exported":        export.ConsentsExported,
+			"consent_history_exported": export.ConsentHistoryExported,
+		},
+	}
+	if err := w.Audit.CreateAuditLog(ctx, entry); err != nil {
+		log.Printf("CreateAuditLog error: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/archive"
+	"github.com/libpulse/platform/services/api/internal/supabase"
+)
+
+type fakeSubjectExportStore struct {
+	open      []supabase.SubjectExport
+	expired   []supabase.SubjectExport
+	saved     []supabase.SubjectExport
+	events    []supabase.Event
+	uploadErr error
+	uploads   map[string][]byte
+	deleted   []string
+	audits    []supabase.AuditLog
+}
+
+func (f *fakeSubjectExportStore) ListOpenSubjectExports(ctx context.Context, limit int) ([]supabase.SubjectExport, error) {
+	return f.open, nil
+}
+
+func (f *fakeSubjectExportStore) ListExpiredSubjectExports(ctx context.Context, now time.Time, limit int) ([]supabase.SubjectExport, error) {
+	return f.expired, nil
+}
+
+func (f *fakeSubjectExportStore) SaveSubjectExport(ctx context.Context, export supabase.SubjectExport) error {
+	f.saved = append(f.saved, export)
+	return nil
+}
+
+func (f *fakeSubjectExportStore) ListSubjectEvents(ctx context.Context, projectID, userIDH, afterEventID string, limit int) ([]supabase.Event, error) {
+	var page []supabase.Event
+	for _, e := range f.events {
+		if e.EventID > afterEventID && len(page) < limit {
+			page = append(page, e)
+		}
+	}
+	return page, nil
+}
+
+func (f *fakeSubjectExportStore) ListSubjectConsent(ctx context.Context, projectID, userIDH string) ([]supabase.Consent, error) {
+	return []supabase.Consent{{ProjectID: projectID, UserIDH: userIDH, State: "granted"}}, nil
+}
+
+func (f *fakeSubjectExportStore) ListSubjectConsentHistory(ctx context.Context, projectID, userIDH string) ([]supabase.ConsentHistoryEntry, error) {
+	return []supabase.ConsentHistoryEntry{
+		{ID: 1, ProjectID: projectID, UserIDH: userIDH, NextState: "denied"},
+		{ID: 2, ProjectID: projectID, UserIDH: userIDH, NextState: "granted"},
+	}, nil
+}
+
+func (f *fakeSubjectExportStore) UploadObject(ctx context.Context, bucket, path, contentType string, body io.Reader) error {
+	if f.uploadErr != nil {
+		return f.uploadErr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if f.uploads == nil {
+		f.uploads = map[string][]byte{}
+	}
+	f.uploads[bucket+"/"+path] = data
+	return nil
+}
+
+func (f *fakeSubjectExportStore) DeleteObjects(ctx context.Context, bucket string, paths []string) error {
+	f.deleted = append(f.deleted, paths...)
+	return nil
+}
+
+func (f *fakeSubjectExportStore) CreateAuditLog(ctx context.Context, entry supabase.AuditLog) error {
+	f.audits = append(f.audits, entry)
+	return nil
+}
+
+func (f *fakeSubjectExportStore) last() supabase.SubjectExport {
+	return f.saved[len(f.saved)-1]
+}
+
+func newSubjectExportWorker(store *fakeSubjectExportStore) *SubjectExportWorker {
+	return &SubjectExportWorker{
+		Exports: store, Events: store, Consents: store, Objects: store, Audit: store,
+		BatchSize: 2, TTL: time.Hour,
+	}
+}
+
+func openSubjectExport(attempts int) supabase.SubjectExport {
+	subject := "uh-export-me"
+	return supabase.SubjectExport{
+		ID: "se-1", ProjectID: "proj-1", UserIDH: &subject, SubjectHash: "hash-of-subject",
+		Status: supabase.SubjectExportStatusPending, Attempts: attempts,
+	}
+}
+
+func TestSubjectExportWorker_Completes(t *testing.T) {
+	store := &fakeSubjectExportStore{
+		open:   []supabase.SubjectExport{openSubjectExport(0)},
+		events: []supabase.Event{{EventID: "e1"}, {EventID: "e2"}, {EventID: "e3"}},
+	}
+
+	if err := newSubjectExportWorker(store).ProcessOnce(context.Background()); err != nil {
+		t.Fatalf("ProcessOnce error: %v", err)
+	}
+
+	done := store.last()
+	if done.Status != supabase.SubjectExportStatusCompleted || done.UserIDH != nil || done.ExpiresAt == nil {
+		t.Fatalf("export not completed and cleared: %+v", done)
+	}
+	if done.ObjectPath == nil || *done.ObjectPath != "proj-1/se-1.zip" {
+		t.Fatalf("object path = %v, want proj-1/se-1.zip", done.ObjectPath)
+	}
+	if done.EventsExported != 3 || done.ConsentsExported != 1 || done.ConsentHistoryExported != 2 {
+		t.Errorf("counters = %d/%d/%d, want 3/1/2", done.EventsExported, done.ConsentsExported, done.ConsentHistoryExported)
+	}
+
+	data, ok := store.uploads[supabase.SubjectExportBucket+"/proj-1/se-1.zip"]
+	if !ok {
+		t.Fatalf("bundle not uploaded: %v", store.uploads)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("bundle is not a zip: %v", err)
+	}
+	files := map[string]bool{}
+	for _, f := range zr.File {
+		files[f.Name] = true
+	}
+	for _, name := range []string{archive.FileManifest, archive.FileEvents, archive.FileConsent, archive.FileConsentHistory} {
+		if !files[name] {
+			t.Errorf("bundle has no %s", name)
+		}
+	}
+
+	if len(store.audits) != 1 || store.audits[0].Action != "subject.access_exported" || store.audits[0].Details["subject_hash"] != "hash-of-subject" {
+		t.Fatalf("unexpected audit entries: %+v", store.audits)
+	}
+}
+
+func TestSubjectExportWorker_RetriesThenFails(t *testing.T) {
+	store := &fakeSubjectExportStore{open: []supabase.SubjectExport{openSubjectExport(0)}, uploadErr: errors.New("storage unavailable")}
+	if err := newSubjectExportWorker(store).ProcessOnce(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	retry := store.last()
+	if retry.Status != supabase.SubjectExportStatusPending || retry.UserIDH == nil || retry.Error == nil {
+		t.Fatalf("export should stay pending with its subject: %+v", retry)
+	}
+	if len(store.audits) != 0 {
+		t.Fatalf("retries must not be audited: %+v", store.audits)
+	}
+
+	store = &fakeSubjectExportStore{open: []supabase.SubjectExport{openSubjectExport(maxSubjectExportAttempts - 1)}, uploadErr: errors.New("storage unavailable")}
+	_ = newSubjectExportWorker(store).ProcessOnce(context.Background())
+	failed := store.last()
+	if failed.Status != supabase.SubjectExportStatusFailed || failed.UserIDH != nil || failed.ObjectPath != nil {
+		t.Fatalf("export should be failed and cleared: %+v", failed)
+	}
+	if len(store.audits) != 1 || store.audits[0].Action != "subject.access_export_failed" {
+		t.Fatalf("unexpected audit entries: %+v", store.audits)
+	}
+}
+
+func TestSubjectExportWorker_DeletesExpiredBundles(t *testing.T) {
+	path := "proj-1/se-old.zip"
+	store := &fakeSubjectExportStore{expired: []supabase.SubjectExport{
+		{ID: "se-old", ProjectID: "proj-1", Status: supabase.SubjectExportStatusCompleted, ObjectPath: &path},
+	}}
+
+	if err := newSubjectExportWorker(store).ProcessOnce(context.Background()); err != nil {
+		t.Fatalf("ProcessOnce error: %v", err)
+	}
+
+	if len(store.deleted) != 1 || store.deleted[0] != path {
+		t.Fatalf("deleted = %v, want [%s]", store.deleted, path)
+	}
+	if got := store.last(); got.Status != supabase.SubjectExportStatusExpired || got.ObjectPath != nil {
+		t.Fatalf("export not marked expired: %+v", got)
+	}
+}
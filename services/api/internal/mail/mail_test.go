@@ -0,0 +1,114 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// smtpStandIn is a minimal local SMTP server that records the last message it received
+type smtpStandIn struct {
+	ln   net.Listener
+	from string
+	rcpt string
+	data chan string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpStandIn{ln: ln, data: make(chan string, 1)}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *smtpStandIn) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP stand-in")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = cmd
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.rcpt = cmd
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data <- b.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	srv := newSMTPStandIn(t)
+	sender := &SMTPSender{Addr: srv.ln.Addr().String(), From: "noreply@libpulse.dev"}
+
+	err := sender.Send(context.Background(), Message{
+		To:      "dev@example.com",
+		Subject: "You're invited",
+		Body:    "Line one\nLine two",
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	data := <-srv.data
+	if srv.from != "MAIL FROM:<noreply@libpulse.dev>" {
+		t.Errorf("MAIL FROM = %q", srv.from)
+	}
+	if srv.rcpt != "RCPT TO:<dev@example.com>" {
+		t.Errorf("RCPT TO = %q", srv.rcpt)
+	}
+	for _, want := range []string{"Subject: You're invited\r\n", "To: dev@example.com\r\n", "\r\n\r\nLine one\r\nLine two"} {
+		if !strings.Contains(data, want) {
+			t.Errorf("message missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestSMTPSender_RejectsHeaderInjection(t *testing.T) {
+	sender := &SMTPSender{Addr: "127.0.0.1:1", From: "noreply@libpulse.dev"}
+	err := sender.Send(context.Background(), Message{To: "dev@example.com\r\nBcc: x@example.com", Subject: "s"})
+	if err == nil {
+		t.Fatal("Send accepted a recipient with a header injection")
+	}
+}
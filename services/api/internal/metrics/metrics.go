@@ -0,0 +1,23 @@
+// Package metrics publishes service counters through expvar.
+// They are served as JSON at /debug/vars when LIBPULSE_EXPOSE_METRICS is enabled.
+package metrics
+
+import "expvar"
+
+var (
+	// RetentionEventsPurged counts events deleted by the retention job, keyed by project ID
+	RetentionEventsPurged = expvar.NewMap("retention_events_purged")
+	// RetentionRuns counts completed retention passes
+	RetentionRuns = expvar.NewInt("retention_runs")
+	// RetentionErrors counts projects whose retention pass failed
+	RetentionErrors = expvar.NewInt("retention_errors")
+)
+
+var (
+	// JobRuns counts scheduled job runs on this replica, keyed by job name
+	JobRuns = expvar.NewMap("job_runs")
+	// JobFailures counts scheduled job runs that returned an error or timed out, keyed by job name
+	JobFailures = expvar.NewMap("job_failures")
+	// JobSkips counts scheduled runs left to another replica holding the job lease, keyed by job name
+	JobSkips = expvar.NewMap("job_skips")
+)
@@ -0,0 +1,82 @@
+// Package notify delivers notifications to project owners about events that need their attention.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/libpulse/platform/services/api/internal/mail"
+)
+
+// KeyLeakNotice describes a project key that was disabled after a leaked-credential report
+type KeyLeakNotice struct {
+	OwnerEmail  string
+	ProjectID   string
+	ProjectName string
+	KeyID       string
+	KeyLabel    string
+	Source      string
+	URL         string
+}
+
+// InvitationNotice describes an invitation to join a project
+type InvitationNotice struct {
+	Email       string
+	ProjectName string
+	Role        string
+	AcceptURL   string
+	ExpiresAt   time.Time
+}
+
+// Notifier abstracts how project owners and invitees are notified
+type Notifier interface {
+	NotifyKeyLeaked(ctx context.Context, notice KeyLeakNotice) error
+	NotifyInvitation(ctx context.Context, notice InvitationNotice) error
+}
+
+// LogNotifier writes notifications to the service log. It is used when no SMTP server is configured,
+// and never logs secrets such as invitation tokens.
+type LogNotifier struct{}
+
+// NotifyKeyLeaked logs the notice
+func (LogNotifier) NotifyKeyLeaked(ctx context.Context, notice KeyLeakNotice) error {
+	log.Printf("notify %s: project key %q (%s) of project %q was disabled after a leak report (source=%s url=%s)",
+		notice.OwnerEmail, notice.KeyLabel, notice.KeyID, notice.ProjectName, notice.Source, notice.URL)
+	return nil
+}
+
+// NotifyInvitation logs the notice without the accept link, which carries the invitation token
+func (LogNotifier) NotifyInvitation(ctx context.Context, notice InvitationNotice) error {
+	log.Printf("notify %s: invited to project %q as %s (expires %s)",
+		notice.Email, notice.ProjectName, notice.Role, notice.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// MailNotifier sends notifications as email through a mail.Sender
+type MailNotifier struct {
+	Sender mail.Sender
+}
+
+// NotifyKeyLeaked emails the project owner
+func (n MailNotifier) NotifyKeyLeaked(ctx context.Context, notice KeyLeakNotice) error {
+	return n.Sender.Send(ctx, mail.Message{
+		To:      notice.OwnerEmail,
+		Subject: fmt.Sprintf("[LibPulse] Project key %q was disabled", notice.KeyLabel),
+		Body: fmt.Sprintf("The project key %q (%s) of project %q was found in a public location and has been disabled.\n\n"+
+			"Source: %s\nLocation: %s\n\nCreate a new key and update the affected SDK configuration.\n",
+			notice.KeyLabel, notice.KeyID, notice.ProjectName, notice.Source, notice.URL),
+	})
+}
+
+// NotifyInvitation emails the invitee the accept link
+func (n MailNotifier) NotifyInvitation(ctx context.Context, notice InvitationNotice) error {
+	return n.Sender.Send(ctx, mail.Message{
+		To:      notice.Email,
+		Subject: fmt.Sprintf("[LibPulse] You're invited to %s", notice.ProjectName),
+		Body: fmt.Sprintf("You have been invited to join the LibPulse project %q as %s.\n\n"+
+			"Accept the invitation: %s\n\nThe link expires on %s and can be used once.\n",
+			notice.ProjectName, notice.Role, notice.AcceptURL, notice.ExpiresAt.UTC().Format("2 Jan 2006 15:04 MST")),
+	})
+}
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogNotifier_NotifyInvitation_OmitsToken(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	const token = "lpinv_secret-token-value"
+	err := LogNotifier{}.NotifyInvitation(context.Background(), InvitationNotice{
+		Email:       "invitee@example.com",
+		ProjectName: "Acme CLI",
+		Role:        "viewer",
+		AcceptURL:   "https://app.example/invitations/accept?token=" + token,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("NotifyInvitation error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "invitee@example.com") {
+		t.Errorf("log output does not name the invitee: %q", out)
+	}
+	if strings.Contains(out, token) || strings.Contains(out, "token=") {
+		t.Errorf("log output leaks the invitation token: %q", out)
+	}
+}
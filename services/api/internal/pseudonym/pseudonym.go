@@ -0,0 +1,95 @@
+// Package pseudonym hashes raw end user identifiers sent at ingestion into user_id_h values, so
+// every SDK produces the same hash for the same user. Each project has its own random salt,
+// created on first use and stored encrypted; raw identifiers are never stored.
+package pseudonym
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+)
+
+// SaltStore persists sealed project salts.
+type SaltStore interface {
+	GetProjectSalt(ctx context.Context, projectID string) (string, error)
+	CreateProjectSalt(ctx context.Context, projectID, saltSealed string) error
+}
+
+// Pseudonymizer hashes user identifiers with their project's salt. Salts never change, so opened
+// salts are cached for the life of the process.
+type Pseudonymizer struct {
+	Store SaltStore
+
+	mu    sync.RWMutex
+	salts map[string][]byte
+}
+
+// New creates a Pseudonymizer backed by store
+func New(store SaltStore) *Pseudonymizer {
+	return &Pseudonymizer{Store: store, salts: map[string][]byte{}}
+}
+
+// UserIDH returns the user_id_h of a raw user identifier in the given project
+func (p *Pseudonymizer) UserIDH(ctx context.Context, projectID, userID string) (string, error) {
+	salt, err := p.salt(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	return crypto.PseudonymizeUserID(salt, userID), nil
+}
+
+func (p *Pseudonymizer) salt(ctx context.Context, projectID string) ([]byte, error) {
+	p.mu.RLock()
+	salt, ok := p.salts[projectID]
+	p.mu.RUnlock()
+	if ok {
+		return salt, nil
+	}
+
+	sealed, err := p.Store.GetProjectSalt(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if sealed == "" {
+		if sealed, err = p.create(ctx, projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	salt, err = crypto.OpenSalt(projectID, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.salts[projectID] = salt
+	p.mu.Unlock()
+	return salt, nil
+}
+
+// create stores a new salt for the project and returns the sealed salt that won, which is another
+// instance's when two create one at the same time
+func (p *Pseudonymizer) create(ctx context.Context, projectID string) (string, error) {
+	salt, err := crypto.GenerateSalt()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := crypto.SealSalt(projectID, salt)
+	if err != nil {
+		return "", err
+	}
+	if err := p.Store.CreateProjectSalt(ctx, projectID, sealed); err != nil {
+		return "", err
+	}
+
+	stored, err := p.Store.GetProjectSalt(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if stored == "" {
+		return "", errors.New("project salt was not stored")
+	}
+	return stored, nil
+}
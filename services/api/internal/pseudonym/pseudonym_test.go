@@ -0,0 +1,81 @@
+package pseudonym
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libpulse/platform/services/api/internal/utils/crypto"
+)
+
+func init() {
+	crypto.Init("test-pepper")
+}
+
+type fakeSaltStore struct {
+	salts   map[string]string
+	gets    int
+	creates int
+	// racer, when set, stores a competing salt just before our insert lands
+	racer string
+}
+
+func (f *fakeSaltStore) GetProjectSalt(ctx context.Context, projectID string) (string, error) {
+	f.gets++
+	return f.salts[projectID], nil
+}
+
+func (f *fakeSaltStore) CreateProjectSalt(ctx context.Context, projectID, saltSealed string) error {
+	f.creates++
+	if f.racer != "" {
+		f.salts[projectID] = f.racer
+	}
+	if _, exists := f.salts[projectID]; !exists {
+		f.salts[projectID] = saltSealed
+	}
+	return nil
+}
+
+func TestPseudonymizer_CreatesAndCachesSalt(t *testing.T) {
+	store := &fakeSaltStore{salts: map[string]string{}}
+	p := New(store)
+
+	h1, err := p.UserIDH(context.Background(), "proj-1", "alice")
+	if err != nil {
+		t.Fatalf("UserIDH error: %v", err)
+	}
+	h2, err := p.UserIDH(context.Background(), "proj-1", "alice")
+	if err != nil {
+		t.Fatalf("UserIDH error: %v", err)
+	}
+	if h1 != h2 {
+		t.Error("same user hashed differently")
+	}
+	if store.creates != 1 || store.gets != 2 {
+		t.Errorf("creates=%d gets=%d, want 1 and 2 (second call served from cache)", store.creates, store.gets)
+	}
+
+	// Another instance with an empty cache derives the same hash from the stored salt
+	h3, err := New(store).UserIDH(context.Background(), "proj-1", "alice")
+	if err != nil || h3 != h1 {
+		t.Errorf("fresh instance hash = %q (%v), want %q", h3, err, h1)
+	}
+
+	h4, _ := p.UserIDH(context.Background(), "proj-2", "alice")
+	if h4 == h1 {
+		t.Error("projects must not share hashes")
+	}
+}
+
+func TestPseudonymizer_UsesConcurrentlyCreatedSalt(t *testing.T) {
+	winner, _ := crypto.GenerateSalt()
+	sealed, _ := crypto.SealSalt("proj-1", winner)
+	store := &fakeSaltStore{salts: map[string]string{}, racer: sealed}
+
+	h, err := New(store).UserIDH(context.Background(), "proj-1", "alice")
+	if err != nil {
+		t.Fatalf("UserIDH error: %v", err)
+	}
+	if want := crypto.PseudonymizeUserID(winner, "alice"); h != want {
+		t.Errorf("hash = %q, want the one from the winning salt %q", h, want)
+	}
+}
@@ -0,0 +1,165 @@
+// Package secretscan verifies leaked-credential reports sent in the GitHub secret scanning
+// partner format.
+package secretscan
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultKeysURL is where GitHub publishes the keys it signs secret scanning reports with
+const DefaultKeysURL = "https://api.github.com/meta/public_keys/secret_scanning"
+
+// minRefreshInterval bounds how often an unknown key identifier can trigger a refetch
+const minRefreshInterval = time.Minute
+
+// ErrInvalidSignature is returned when a report is not signed by a known key
+var ErrInvalidSignature = errors.New("invalid report signature")
+
+// publicKeysResponse is the document served at the keys URL
+type publicKeysResponse struct {
+	PublicKeys []struct {
+		KeyIdentifier string `json:"key_identifier"`
+		Key           string `json:"key"`
+		IsCurrent     bool   `json:"is_current"`
+	} `json:"public_keys"`
+}
+
+// Verifier checks report signatures against the reporter's published ECDSA keys.
+// Keys are cached and refetched when a report references an unknown key identifier; the fetch
+// runs outside mu and concurrent refetches share a single request.
+type Verifier struct {
+	KeysURL    string
+	httpClient *http.Client
+	fetches    singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]*ecdsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier creates a Verifier that fetches keys from keysURL
+func NewVerifier(keysURL string) *Verifier {
+	return &Verifier{
+		KeysURL:    keysURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		keys:       make(map[string]*ecdsa.PublicKey),
+	}
+}
+
+// Verify checks that signature (base64 ASN.1 ECDSA over SHA-256 of payload) was made by keyID
+func (v *Verifier) Verify(ctx context.Context, keyID, signature string, payload []byte) error {
+	if keyID == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	key, err := v.publicKey(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(payload)
+	if !ecdsa.VerifyASN1(key, digest[:], sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// publicKey returns the cached key for keyID, refetching the key document if it is unknown
+func (v *Verifier) publicKey(ctx context.Context, keyID string) (*ecdsa.PublicKey, error) {
+	v.mu.Lock()
+	key, ok := v.keys[keyID]
+	recent := time.Since(v.fetchedAt) < minRefreshInterval
+	v.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+	if recent {
+		return nil, ErrInvalidSignature
+	}
+
+	_, err, _ := v.fetches.Do("keys", func() (interface{}, error) {
+		keys, err := v.fetchKeys(ctx)
+
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		v.fetchedAt = time.Now()
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	key, ok = v.keys[keyID]
+	v.mu.Unlock()
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	return key, nil
+}
+
+// fetchKeys downloads and parses the key document
+func (v *Verifier) fetchKeys(ctx context.Context) (map[string]*ecdsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.KeysURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("fetch secret scanning keys: status=%d body=%s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var doc publicKeysResponse
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*ecdsa.PublicKey, len(doc.PublicKeys))
+	for _, k := range doc.PublicKeys {
+		block, _ := pem.Decode([]byte(k.Key))
+		if block == nil {
+			continue
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if ecKey, ok := parsed.(*ecdsa.PublicKey); ok {
+			keys[k.KeyIdentifier] = ecKey
+		}
+	}
+
+	return keys, nil
+}
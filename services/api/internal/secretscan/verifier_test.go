@@ -0,0 +1,140 @@
+package secretscan
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newKeyServer serves a key document containing key under keyID
+func newKeyServer(t *testing.T, keyID string, key *ecdsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(keyHandler(t, keyID, key))
+}
+
+// keyHandler serves the key document of newKeyServer
+func keyHandler(t *testing.T, keyID string, key *ecdsa.PrivateKey) http.HandlerFunc {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey error: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	doc := map[string]interface{}{
+		"public_keys": []map[string]interface{}{
+			{"key_identifier": keyID, "key": string(pemKey), "is_current": true},
+		},
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(doc)
+	}
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) string {
+	t.Helper()
+
+	digest := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("SignASN1 error: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	server := newKeyServer(t, "kid-1", key)
+	defer server.Close()
+
+	v := NewVerifier(server.URL)
+	payload := []byte(`[{"token":"psk_live_x","type":"libpulse_secret"}]`)
+
+	if err := v.Verify(context.Background(), "kid-1", sign(t, key, payload), payload); err != nil {
+		t.Errorf("Verify valid signature = %v, want nil", err)
+	}
+
+	// Tampered payload
+	if err := v.Verify(context.Background(), "kid-1", sign(t, key, payload), []byte(`[]`)); err == nil {
+		t.Errorf("Verify accepted a tampered payload")
+	}
+
+	// Signed by another key
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err := v.Verify(context.Background(), "kid-1", sign(t, other, payload), payload); err == nil {
+		t.Errorf("Verify accepted a signature from an unknown key")
+	}
+
+	// Unknown key identifier
+	if err := v.Verify(context.Background(), "kid-2", sign(t, key, payload), payload); err == nil {
+		t.Errorf("Verify accepted an unknown key identifier")
+	}
+}
+
+func TestVerifier_UnknownKeyRefetchIsShared(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	handler := keyHandler(t, "kid-1", key)
+	var fetches int32
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			started <- struct{}{}
+			<-release
+		}
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	v := NewVerifier(server.URL)
+	if _, err := v.publicKey(context.Background(), "kid-1"); err != nil {
+		t.Fatalf("publicKey(kid-1) error: %v", err)
+	}
+	v.mu.Lock()
+	v.fetchedAt = time.Now().Add(-2 * minRefreshInterval)
+	v.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v.publicKey(context.Background(), "kid-2")
+		}()
+	}
+	<-started
+
+	// A known key is served while the refetch is still waiting on the network
+	done := make(chan error, 1)
+	go func() {
+		_, err := v.publicKey(context.Background(), "kid-1")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("publicKey(kid-1) error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("publicKey(kid-1) blocked behind the refetch")
+	}
+
+	close(release)
+	wg.Wait()
+	if got := atomic.LoadInt32(&fetches); got != 2 {
+		t.Errorf("fetches = %d, want 2 (initial load and one shared refetch)", got)
+	}
+}
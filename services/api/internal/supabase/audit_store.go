@@ -0,0 +1,55 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Audit log actor types, matching the audit_logs_actor_type_check constraint
+const (
+	ActorTypeAdmin  = "admin"
+	ActorTypeUser   = "user"
+	ActorTypeSystem = "system"
+)
+
+// Audit log auth modes, matching the audit_logs_auth_mode_check constraint
+const (
+	AuthModePAT    = "PAT"
+	AuthModeHMAC   = "HMAC"
+	AuthModePKOnly = "PK_ONLY"
+	AuthModeSystem = "SYSTEM"
+	AuthModeJWT    = "JWT"
+)
+
+// AuditLog structure for database operations
+type AuditLog struct {
+	ProjectID  string                 `json:"project_id"`
+	ActorType  string                 `json:"actor_type"`
+	ActorID    *string                `json:"actor_id,omitempty"`
+	Action     string                 `json:"action"`
+	Success    bool                   `json:"success"`
+	StatusCode int                    `json:"status_code"`
+	AuthMode   string                 `json:"auth_mode"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	RequestID  *string                `json:"request_id,omitempty"`
+	IPHash     *string                `json:"ip_hash,omitempty"`
+	UserAgent  *string                `json:"user_agent,omitempty"`
+}
+
+// AuditLogStore provides audit log data access
+type AuditLogStore struct {
+	Client *Client
+}
+
+// CreateAuditLog => POST /rest/v1/audit_logs
+func (s *AuditLogStore) CreateAuditLog(ctx context.Context, entry AuditLog) error {
+	if entry.ProjectID == "" {
+		return errors.New("project id cannot be empty")
+	}
+	if entry.Action == "" {
+		return errors.New("action cannot be empty")
+	}
+
+	return s.Client.doRest(ctx, http.MethodPost, "/audit_logs", entry, "return=minimal", nil)
+}
@@ -1,6 +1,7 @@
 package supabase
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -114,3 +115,52 @@ func (c *Client) GetUserByID(ctx context.Context, id string) (*User, error) {
 
 	return u, nil
 }
+
+// doRest performs a PostgREST request against BaseRestURL with the service role headers.
+// path is appended to BaseRestURL (e.g. "/project_keys?id=eq.<id>"). When payload is non-nil
+// it is sent as JSON, and when out is non-nil the response body is decoded into it.
+// Error responses are returned with the raw body so callers can detect constraint violations.
+func (c *Client) doRest(ctx context.Context, method, path string, payload interface{}, prefer string, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseRestURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	// Supabase REST API headers
+	req.Header.Set("apikey", c.ServiceRoleKey)
+	req.Header.Set("Authorization", "Bearer "+c.ServiceRoleKey)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if prefer != "" {
+		req.Header.Set("Prefer", prefer)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		bodyStr := string(bodyBytes)
+		log.Printf("supabase rest api error: status=%d body=%s", resp.StatusCode, bodyStr)
+		return errors.New(bodyStr)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,122 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Consent structure for database operations (mirrors the user_consent table)
+type Consent struct {
+	ProjectID string    `json:"project_id"`
+	UserIDH   string    `json:"user_id_h"`
+	State     string    `json:"state"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ConsentHistoryEntry structure for database operations (mirrors the user_consent_history table)
+type ConsentHistoryEntry struct {
+	ID        int64           `json:"id"`
+	ProjectID string          `json:"project_id"`
+	UserIDH   string          `json:"user_id_h"`
+	PrevState *string         `json:"prev_state,omitempty"`
+	NextState string          `json:"next_state"`
+	TS        time.Time       `json:"ts"`
+	Version   *string         `json:"version,omitempty"`
+	Meta      json.RawMessage `json:"meta,omitempty"`
+}
+
+// ConsentStore provides consent state data access
+type ConsentStore struct {
+	Client *Client
+}
+
+// ListConsents => GET /rest/v1/user_consent?project_id=eq.<id>
+// Pages are fetched until exhausted so the PostgREST row cap does not truncate the list.
+func (s *ConsentStore) ListConsents(ctx context.Context, projectID string) ([]Consent, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	const pageSize = 1000
+
+	var consents []Consent
+	for offset := 0; ; offset += pageSize {
+		var page []Consent
+		path := "/user_consent?project_id=eq." + url.QueryEscape(projectID) + "&select=*&order=user_id_h&limit=" + strconv.Itoa(pageSize) + "&offset=" + strconv.Itoa(offset)
+		if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &page); err != nil {
+			return nil, err
+		}
+		consents = append(consents, page...)
+		if len(page) < pageSize {
+			return consents, nil
+		}
+	}
+}
+
+// ImportConsents copies consent state into the project and returns how many rows were written
+// => POST /rest/v1/rpc/import_consents
+// Existing state is replaced only by a later updated_at, so newer consent in the project is kept.
+func (s *ConsentStore) ImportConsents(ctx context.Context, projectID string, consents []Consent) (int, error) {
+	if projectID == "" {
+		return 0, errors.New("project id cannot be empty")
+	}
+	if len(consents) == 0 {
+		return 0, errors.New("consents cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id": projectID,
+		"p_consents":   consents,
+	}
+
+	var applied int
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/import_consents", payload, "", &applied); err != nil {
+		return 0, err
+	}
+
+	return applied, nil
+}
+
+// ListSubjectConsent => GET /rest/v1/user_consent?project_id=eq.<id>&user_id_h=eq.<user>
+func (s *ConsentStore) ListSubjectConsent(ctx context.Context, projectID, userIDH string) ([]Consent, error) {
+	if projectID == "" || userIDH == "" {
+		return nil, errors.New("project id and user id hash cannot be empty")
+	}
+
+	var consents []Consent
+	path := "/user_consent?project_id=eq." + url.QueryEscape(projectID) + "&user_id_h=eq." + url.QueryEscape(userIDH) + "&select=*"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &consents); err != nil {
+		return nil, err
+	}
+
+	return consents, nil
+}
+
+// ListSubjectConsentHistory => GET /rest/v1/user_consent_history?project_id=eq.<id>&user_id_h=eq.<user>
+// Entries are returned oldest first, fetching pages until exhausted.
+func (s *ConsentStore) ListSubjectConsentHistory(ctx context.Context, projectID, userIDH string) ([]ConsentHistoryEntry, error) {
+	if projectID == "" || userIDH == "" {
+		return nil, errors.New("project id and user id hash cannot be empty")
+	}
+
+	const pageSize = 1000
+
+	var entries []ConsentHistoryEntry
+	for offset := 0; ; offset += pageSize {
+		var page []ConsentHistoryEntry
+		path := "/user_consent_history?project_id=eq." + url.QueryEscape(projectID) + "&user_id_h=eq." + url.QueryEscape(userIDH) +
+			"&select=*&order=id.asc&limit=" + strconv.Itoa(pageSize) + "&offset=" + strconv.Itoa(offset)
+		if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &page); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if len(page) < pageSize {
+			return entries, nil
+		}
+	}
+}
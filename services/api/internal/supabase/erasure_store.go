@@ -0,0 +1,184 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Erasure request statuses, matching the erasure_requests_status_check constraint
+const (
+	ErasureStatusPending   = "pending"
+	ErasureStatusRunning   = "running"
+	ErasureStatusCompleted = "completed"
+	ErasureStatusFailed    = "failed"
+)
+
+// ErasureRequest structure for database operations
+type ErasureRequest struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	// UserIDH is only set while the request is open
+	UserIDH               *string    `json:"user_id_h,omitempty"`
+	SubjectHash           string     `json:"subject_hash"`
+	Status                string     `json:"status"`
+	RequestedBy           string     `json:"requested_by"`
+	CreatedAt             time.Time  `json:"created_at"`
+	StartedAt             *time.Time `json:"started_at,omitempty"`
+	CompletedAt           *time.Time `json:"completed_at,omitempty"`
+	Attempts              int        `json:"attempts"`
+	EventsDeleted         int        `json:"events_deleted"`
+	ConsentsDeleted       int        `json:"consents_deleted"`
+	ConsentHistoryDeleted int        `json:"consent_history_deleted"`
+	Error                 *string    `json:"error,omitempty"`
+}
+
+// CreateErasureRequestParams contains parameters for creating an erasure request
+type CreateErasureRequestParams struct {
+	ProjectID   string
+	UserIDH     string
+	SubjectHash string
+	RequestedBy string
+}
+
+// ErasureStore provides data subject erasure data access
+type ErasureStore struct {
+	Client *Client
+}
+
+// CreateErasureRequest => POST /rest/v1/erasure_requests
+func (s *ErasureStore) CreateErasureRequest(ctx context.Context, params CreateErasureRequestParams) (*ErasureRequest, error) {
+	if params.ProjectID == "" || params.UserIDH == "" || params.SubjectHash == "" {
+		return nil, errors.New("project id, user id hash and subject hash cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"project_id":   params.ProjectID,
+		"user_id_h":    params.UserIDH,
+		"subject_hash": params.SubjectHash,
+		"requested_by": params.RequestedBy,
+	}
+
+	var requests []ErasureRequest
+	if err := s.Client.doRest(ctx, http.MethodPost, "/erasure_requests?select=*", payload, "return=representation", &requests); err != nil {
+		return nil, err
+	}
+
+	if len(requests) == 0 {
+		return nil, errors.New("no erasure request returned from database")
+	}
+
+	return &requests[0], nil
+}
+
+// GetErasureRequest => GET /rest/v1/erasure_requests?id=eq.<id>&project_id=eq.<project>
+func (s *ErasureStore) GetErasureRequest(ctx context.Context, projectID, requestID string) (*ErasureRequest, error) {
+	if projectID == "" || requestID == "" {
+		return nil, errors.New("project id and request id cannot be empty")
+	}
+
+	var requests []ErasureRequest
+	path := "/erasure_requests?id=eq." + url.QueryEscape(requestID) + "&project_id=eq." + url.QueryEscape(projectID) + "&select=*"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &requests); err != nil {
+		return nil, err
+	}
+
+	if len(requests) == 0 {
+		return nil, errors.New("erasure request not found")
+	}
+
+	return &requests[0], nil
+}
+
+// ListOpenErasureRequests => GET /rest/v1/erasure_requests?status=in.(pending,running)
+// Oldest requests come first.
+func (s *ErasureStore) ListOpenErasureRequests(ctx context.Context, limit int) ([]ErasureRequest, error) {
+	var requests []ErasureRequest
+	path := "/erasure_requests?status=in.(pending,running)&select=*&order=created_at.asc&limit=" + strconv.Itoa(limit)
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &requests); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// SaveErasureRequest => PATCH /rest/v1/erasure_requests?id=eq.<id>
+// It writes the request's progress: status, counters, timestamps, error and user_id_h, which is
+// cleared (set to NULL) when nil.
+func (s *ErasureStore) SaveErasureRequest(ctx context.Context, request ErasureRequest) error {
+	if request.ID == "" {
+		return errors.New("request id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"status":                  request.Status,
+		"user_id_h":               request.UserIDH,
+		"attempts":                request.Attempts,
+		"events_deleted":          request.EventsDeleted,
+		"consents_deleted":        request.ConsentsDeleted,
+		"consent_history_deleted": request.ConsentHistoryDeleted,
+		"error":                   request.Error,
+		"started_at":              formatOptionalTime(request.StartedAt),
+		"completed_at":            formatOptionalTime(request.CompletedAt),
+	}
+
+	return s.Client.doRest(ctx, http.MethodPatch, "/erasure_requests?id=eq."+url.QueryEscape(request.ID), payload, "return=minimal", nil)
+}
+
+// DeleteSubjectEvents deletes up to limit events of one end user and returns how many were
+// deleted => POST /rest/v1/rpc/delete_subject_events
+func (s *ErasureStore) DeleteSubjectEvents(ctx context.Context, projectID, userIDH string, limit int) (int, error) {
+	if projectID == "" || userIDH == "" {
+		return 0, errors.New("project id and user id hash cannot be empty")
+	}
+	if limit <= 0 {
+		return 0, errors.New("limit must be positive")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id": projectID,
+		"p_user_id_h":  userIDH,
+		"p_limit":      limit,
+	}
+
+	var deleted int
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/delete_subject_events", payload, "", &deleted); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// DeleteSubjectConsent deletes one end user's consent state and history and returns how many
+// rows of each were deleted => POST /rest/v1/rpc/delete_subject_consent
+func (s *ErasureStore) DeleteSubjectConsent(ctx context.Context, projectID, userIDH string) (int, int, error) {
+	if projectID == "" || userIDH == "" {
+		return 0, 0, errors.New("project id and user id hash cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id": projectID,
+		"p_user_id_h":  userIDH,
+	}
+
+	var result struct {
+		ConsentsDeleted       int `json:"consents_deleted"`
+		ConsentHistoryDeleted int `json:"consent_history_deleted"`
+	}
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/delete_subject_consent", payload, "", &result); err != nil {
+		return 0, 0, err
+	}
+
+	return result.ConsentsDeleted, result.ConsentHistoryDeleted, nil
+}
+
+// formatOptionalTime renders t for a PostgREST payload, or null when t is nil
+func formatOptionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
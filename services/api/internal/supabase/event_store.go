@@ -0,0 +1,337 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event structure for database operations (mirrors the events table)
+type Event struct {
+	ProjectID   string          `json:"project_id"`
+	EventID     string          `json:"event_id"`
+	Env         string          `json:"env,omitempty"`
+	EventType   string          `json:"event_type"`
+	EventTS     time.Time       `json:"event_ts"`
+	IngestedAt  *time.Time      `json:"ingested_at,omitempty"`
+	Op          string          `json:"op"`
+	Variant     *string         `json:"variant,omitempty"`
+	Surface     *string         `json:"surface,omitempty"`
+	Version     string          `json:"version"`
+	ArgsSig     *string         `json:"args_sig,omitempty"`
+	ArgsCount   *int            `json:"args_count,omitempty"`
+	Success     *bool           `json:"success,omitempty"`
+	Severity    *string         `json:"severity,omitempty"`
+	Code        *string         `json:"code,omitempty"`
+	Message     *string         `json:"message,omitempty"`
+	Stack       *string         `json:"stack,omitempty"`
+	DurationMS  *int            `json:"duration_ms,omitempty"`
+	UserIDH     string          `json:"user_id_h"`
+	SessionID   *string         `json:"session_id,omitempty"`
+	TraceID     *string         `json:"trace_id,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	SDKName     string          `json:"sdk_name"`
+	SDKVersion  string          `json:"sdk_version"`
+	SDKLanguage *string         `json:"sdk_language,omitempty"`
+	SDKRuntime  *string         `json:"sdk_runtime,omitempty"`
+	SDKPayload  json.RawMessage `json:"sdk_payload,omitempty"`
+}
+
+// eventColumns lists the insertable events columns. PostgREST requires every object in a bulk
+// insert to share the same keys; passing ?columns= lets omitted optional fields fall back to NULL.
+const eventColumns = "project_id,event_id,env,event_type,event_ts,op,variant,surface,version,args_sig,args_count," +
+	"success,severity,code,message,stack,duration_ms,user_id_h,session_id,trace_id,payload," +
+	"sdk_name,sdk_version,sdk_language,sdk_runtime,sdk_payload"
+
+// EventStore provides event-related data access
+type EventStore struct {
+	Client *Client
+}
+
+// InsertEvents => POST /rest/v1/events
+// Events already stored under the same (project_id, event_id) are ignored, so SDK retries are idempotent.
+func (s *EventStore) InsertEvents(ctx context.Context, events []Event) error {
+	if len(events) == 0 {
+		return errors.New("events cannot be empty")
+	}
+
+	return s.Client.doRest(ctx, http.MethodPost, "/events?on_conflict=project_id,event_id&columns="+eventColumns, events, "resolution=ignore-duplicates,return=minimal", nil)
+}
+
+// ListEvents => GET /rest/v1/events?project_id=eq.<id>&event_id=gt.<after>&order=event_id.asc
+// It returns up to limit events of the project ordered by event_id, starting after afterEventID
+// (all events when empty). Paging by key keeps deep pages as cheap as the first.
+func (s *EventStore) ListEvents(ctx context.Context, projectID, afterEventID string, limit int) ([]Event, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	return s.listEvents(ctx, "project_id=eq."+url.QueryEscape(projectID), afterEventID, limit)
+}
+
+// ListSubjectEvents => GET /rest/v1/events?project_id=eq.<id>&user_id_h=eq.<user>&event_id=gt.<after>
+// It pages through one end user's events like ListEvents.
+func (s *EventStore) ListSubjectEvents(ctx context.Context, projectID, userIDH, afterEventID string, limit int) ([]Event, error) {
+	if projectID == "" || userIDH == "" {
+		return nil, errors.New("project id and user id hash cannot be empty")
+	}
+
+	return s.listEvents(ctx, "project_id=eq."+url.QueryEscape(projectID)+"&user_id_h=eq."+url.QueryEscape(userIDH), afterEventID, limit)
+}
+
+func (s *EventStore) listEvents(ctx context.Context, filter, afterEventID string, limit int) ([]Event, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	path := "/events?" + filter + "&select=*&order=event_id.asc&limit=" + strconv.Itoa(limit)
+	if afterEventID != "" {
+		path += "&event_id=gt." + url.QueryEscape(afterEventID)
+	}
+
+	var events []Event
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// EventFields lists the events columns that can be read back
+var EventFields = append(strings.Split(eventColumns, ","), "ingested_at")
+
+// EventCursor is the position of an event in event_ts, event_id order
+type EventCursor struct {
+	EventTS time.Time
+	EventID string
+}
+
+// EventQuery filters and pages an event search. Empty filters match everything.
+type EventQuery struct {
+	ProjectID string
+	// From is inclusive, To exclusive
+	From      *time.Time
+	To        *time.Time
+	EventType string
+	Op        string
+	Version   string
+	Severity  string
+	Success   *bool
+	SDKName   string
+	SessionID string
+	TraceID   string
+	UserIDH   string
+	// Before continues a search after the last event of the previous page
+	Before *EventCursor
+	// Fields to return; all of EventFields when empty
+	Fields []string
+	Limit  int
+}
+
+// SearchEvents => GET /rest/v1/events?project_id=eq.<id>&...&order=event_ts.desc,event_id.desc
+// Events come newest first. Each row holds only the requested fields, so rows are returned raw.
+func (s *EventStore) SearchEvents(ctx context.Context, q EventQuery) ([]json.RawMessage, error) {
+	if q.ProjectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+	if q.Limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	params := url.Values{}
+	params.Set("project_id", "eq."+q.ProjectID)
+	selectFields := "*"
+	if len(q.Fields) > 0 {
+		selectFields = strings.Join(q.Fields, ",")
+	}
+	params.Set("select", selectFields)
+	params.Set("order", "event_ts.desc,event_id.desc")
+	params.Set("limit", strconv.Itoa(q.Limit))
+
+	var ts []string
+	if q.From != nil {
+		ts = append(ts, "gte."+q.From.UTC().Format(time.RFC3339Nano))
+	}
+	if q.To != nil {
+		ts = append(ts, "lt."+q.To.UTC().Format(time.RFC3339Nano))
+	}
+	for _, f := range ts {
+		params.Add("event_ts", f)
+	}
+
+	for column, value := range map[string]string{
+		"event_type": q.EventType,
+		"op":         q.Op,
+		"version":    q.Version,
+		"severity":   q.Severity,
+		"sdk_name":   q.SDKName,
+		"session_id": q.SessionID,
+		"trace_id":   q.TraceID,
+		"user_id_h":  q.UserIDH,
+	} {
+		if value != "" {
+			params.Set(column, "eq."+value)
+		}
+	}
+	if q.Success != nil {
+		params.Set("success", "is."+strconv.FormatBool(*q.Success))
+	}
+
+	if q.Before != nil {
+		cursorTS := quoteFilterValue(q.Before.EventTS.UTC().Format(time.RFC3339Nano))
+		cursorID := quoteFilterValue(q.Before.EventID)
+		params.Set("or", "(event_ts.lt."+cursorTS+",and(event_ts.eq."+cursorTS+",event_id.lt."+cursorID+"))")
+	}
+
+	var rows []json.RawMessage
+	if err := s.Client.doRest(ctx, http.MethodGet, "/events?"+params.Encode(), nil, "", &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// quoteFilterValue quotes a value inside a PostgREST logical filter, where , . : ( ) are reserved
+func quoteFilterValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
+
+// TimeseriesQuery selects the events aggregated by EventTimeseries
+type TimeseriesQuery struct {
+	ProjectID string
+	// From is inclusive, To exclusive
+	From time.Time
+	To   time.Time
+	// Bucket is minute, hour or day, truncated in Timezone (an IANA name)
+	Bucket   string
+	Timezone string
+	// GroupBy is an optional dimension: op, version, event_type, surface, variant or sdk_language
+	GroupBy string
+	// Optional filters
+	EventType string
+	Op        string
+	Version   string
+}
+
+// TimeseriesRow is one non-empty bucket of one group
+type TimeseriesRow struct {
+	BucketStart time.Time `json:"bucket_start"`
+	// GroupValue is nil without GroupBy, and for events lacking the dimension
+	GroupValue *string `json:"group_value"`
+	EventCount int64   `json:"event_count"`
+	UserCount  int64   `json:"user_count"`
+}
+
+// EventTimeseries => POST /rest/v1/rpc/event_timeseries
+// Rows come ordered by bucket and group; empty buckets are not returned.
+func (s *EventStore) EventTimeseries(ctx context.Context, q TimeseriesQuery) ([]TimeseriesRow, error) {
+	if q.ProjectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	optional := func(v string) interface{} {
+		if v == "" {
+			return nil
+		}
+		return v
+	}
+	payload := map[string]interface{}{
+		"p_project_id": q.ProjectID,
+		"p_from":       q.From.UTC().Format(time.RFC3339Nano),
+		"p_to":         q.To.UTC().Format(time.RFC3339Nano),
+		"p_bucket":     q.Bucket,
+		"p_timezone":   q.Timezone,
+		"p_group_by":   optional(q.GroupBy),
+		"p_event_type": optional(q.EventType),
+		"p_op":         optional(q.Op),
+		"p_version":    optional(q.Version),
+	}
+
+	var rows []TimeseriesRow
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/event_timeseries", payload, "", &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// DeleteProjectEvents deletes up to limit events of the project regardless of age and returns
+// how many were deleted => POST /rest/v1/rpc/delete_project_events
+func (s *EventStore) DeleteProjectEvents(ctx context.Context, projectID string, limit int) (int, error) {
+	if projectID == "" {
+		return 0, errors.New("project id cannot be empty")
+	}
+	if limit <= 0 {
+		return 0, errors.New("limit must be positive")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id": projectID,
+		"p_limit":      limit,
+	}
+
+	var deleted int
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/delete_project_events", payload, "", &deleted); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// DeleteEventsWithoutPrefix deletes up to limit events of the project whose event_id does not
+// start with keepPrefix and returns how many were deleted => POST /rest/v1/rpc/delete_events_without_prefix
+func (s *EventStore) DeleteEventsWithoutPrefix(ctx context.Context, projectID, keepPrefix string, limit int) (int, error) {
+	if projectID == "" {
+		return 0, errors.New("project id cannot be empty")
+	}
+	if keepPrefix == "" {
+		return 0, errors.New("prefix cannot be empty")
+	}
+	if limit <= 0 {
+		return 0, errors.New("limit must be positive")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id":  projectID,
+		"p_keep_prefix": keepPrefix,
+		"p_limit":       limit,
+	}
+
+	var deleted int
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/delete_events_without_prefix", payload, "", &deleted); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// DeleteEventsBefore deletes up to limit events of the project whose event_ts is before cutoff and
+// returns how many were deleted => POST /rest/v1/rpc/delete_events_before
+func (s *EventStore) DeleteEventsBefore(ctx context.Context, projectID string, cutoff time.Time, limit int) (int, error) {
+	if projectID == "" {
+		return 0, errors.New("project id cannot be empty")
+	}
+	if limit <= 0 {
+		return 0, errors.New("limit must be positive")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id": projectID,
+		"p_before":     cutoff.UTC().Format(time.RFC3339),
+		"p_limit":      limit,
+	}
+
+	var deleted int
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/delete_events_before", payload, "", &deleted); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
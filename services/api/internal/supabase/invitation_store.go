@@ -0,0 +1,153 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ProjectInvitation structure for database operations
+type ProjectInvitation struct {
+	ID         string     `json:"id"`
+	ProjectID  string     `json:"project_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	TokenHash  string     `json:"token_hash,omitempty"`
+	InvitedBy  string     `json:"invited_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	AcceptedBy *string    `json:"accepted_by,omitempty"`
+	DeclinedAt *time.Time `json:"declined_at,omitempty"`
+}
+
+// CreateInvitationParams contains parameters for creating a project invitation
+type CreateInvitationParams struct {
+	ProjectID string
+	Email     string
+	Role      string
+	TokenHash string
+	InvitedBy string
+	ExpiresAt time.Time
+}
+
+// invitationColumns excludes the token hash so it never leaves the store
+const invitationColumns = "id,project_id,email,role,invited_by,created_at,expires_at,accepted_at,accepted_by,declined_at"
+
+// InvitationStore provides project invitation data access
+type InvitationStore struct {
+	Client *Client
+}
+
+// CreateInvitation => POST /rest/v1/project_invitations
+func (s *InvitationStore) CreateInvitation(ctx context.Context, params CreateInvitationParams) (*ProjectInvitation, error) {
+	if params.ProjectID == "" || params.Email == "" || params.TokenHash == "" {
+		return nil, errors.New("project id, email and token hash cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"project_id": params.ProjectID,
+		"email":      params.Email,
+		"role":       params.Role,
+		"token_hash": params.TokenHash,
+		"invited_by": params.InvitedBy,
+		"expires_at": params.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+
+	var invitations []ProjectInvitation
+	path := "/project_invitations?select=" + invitationColumns
+	if err := s.Client.doRest(ctx, http.MethodPost, path, payload, "return=representation", &invitations); err != nil {
+		return nil, err
+	}
+
+	if len(invitations) == 0 {
+		return nil, errors.New("no project invitation returned from database")
+	}
+
+	return &invitations[0], nil
+}
+
+// ListPendingInvitations => GET /rest/v1/project_invitations?project_id=eq.<id>&accepted_at=is.null&declined_at=is.null&expires_at=gt.<now>
+func (s *InvitationStore) ListPendingInvitations(ctx context.Context, projectID string, now time.Time) ([]ProjectInvitation, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	var invitations []ProjectInvitation
+	path := "/project_invitations?project_id=eq." + url.QueryEscape(projectID) +
+		"&accepted_at=is.null&declined_at=is.null&expires_at=gt." + url.QueryEscape(now.UTC().Format(time.RFC3339)) +
+		"&select=" + invitationColumns + "&order=created_at.desc"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &invitations); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation => DELETE /rest/v1/project_invitations?id=eq.<id>&project_id=eq.<project>&accepted_at=is.null
+func (s *InvitationStore) RevokeInvitation(ctx context.Context, projectID, invitationID string) error {
+	if projectID == "" || invitationID == "" {
+		return errors.New("project id and invitation id cannot be empty")
+	}
+
+	var invitations []ProjectInvitation
+	path := "/project_invitations?id=eq." + url.QueryEscape(invitationID) + "&project_id=eq." + url.QueryEscape(projectID) +
+		"&accepted_at=is.null&select=id"
+	if err := s.Client.doRest(ctx, http.MethodDelete, path, nil, "return=representation", &invitations); err != nil {
+		return err
+	}
+
+	if len(invitations) == 0 {
+		return errors.New("project invitation not found")
+	}
+
+	return nil
+}
+
+// ClaimInvitation marks the pending invitation with tokenHash as accepted by userID.
+// The update only matches pending, unexpired invitations, so a token can be redeemed once.
+func (s *InvitationStore) ClaimInvitation(ctx context.Context, tokenHash, userID string, now time.Time) (*ProjectInvitation, error) {
+	return s.resolvePending(ctx, tokenHash, now, map[string]interface{}{
+		"accepted_at": now.UTC().Format(time.RFC3339),
+		"accepted_by": userID,
+	})
+}
+
+// DeclineInvitation marks the pending invitation with tokenHash as declined
+func (s *InvitationStore) DeclineInvitation(ctx context.Context, tokenHash string, now time.Time) (*ProjectInvitation, error) {
+	return s.resolvePending(ctx, tokenHash, now, map[string]interface{}{
+		"declined_at": now.UTC().Format(time.RFC3339),
+	})
+}
+
+// ReleaseInvitation returns a claimed invitation to pending, used when adding the member failed
+func (s *InvitationStore) ReleaseInvitation(ctx context.Context, invitationID string) error {
+	payload := map[string]interface{}{
+		"accepted_at": nil,
+		"accepted_by": nil,
+	}
+	return s.Client.doRest(ctx, http.MethodPatch, "/project_invitations?id=eq."+url.QueryEscape(invitationID), payload, "return=minimal", nil)
+}
+
+// resolvePending => PATCH /rest/v1/project_invitations?token_hash=eq.<hash>&accepted_at=is.null&declined_at=is.null&expires_at=gt.<now>
+func (s *InvitationStore) resolvePending(ctx context.Context, tokenHash string, now time.Time, payload map[string]interface{}) (*ProjectInvitation, error) {
+	if tokenHash == "" {
+		return nil, errors.New("token hash cannot be empty")
+	}
+
+	var invitations []ProjectInvitation
+	path := "/project_invitations?token_hash=eq." + url.QueryEscape(tokenHash) +
+		"&accepted_at=is.null&declined_at=is.null&expires_at=gt." + url.QueryEscape(now.UTC().Format(time.RFC3339)) +
+		"&select=" + invitationColumns
+	if err := s.Client.doRest(ctx, http.MethodPatch, path, payload, "return=representation", &invitations); err != nil {
+		return nil, err
+	}
+
+	if len(invitations) == 0 {
+		return nil, errors.New("project invitation not found")
+	}
+
+	return &invitations[0], nil
+}
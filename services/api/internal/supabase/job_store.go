@@ -0,0 +1,79 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// JobRun structure for database operations
+type JobRun struct {
+	ID         int64      `json:"id"`
+	JobName    string     `json:"job_name"`
+	Holder     string     `json:"holder"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Success    *bool      `json:"success,omitempty"`
+	Error      *string    `json:"error,omitempty"`
+}
+
+// JobStore provides job lease and run history data access for the scheduler
+type JobStore struct {
+	Client *Client
+}
+
+// AcquireJobLease => POST /rest/v1/rpc/acquire_job_lease
+// It reports whether holder now holds the lease on jobName for ttl.
+func (s *JobStore) AcquireJobLease(ctx context.Context, jobName, holder string, ttl time.Duration) (bool, error) {
+	if jobName == "" || holder == "" {
+		return false, errors.New("job name and holder cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"p_job_name":    jobName,
+		"p_holder":      holder,
+		"p_ttl_seconds": int(ttl.Seconds()),
+	}
+
+	var acquired bool
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/acquire_job_lease", payload, "", &acquired); err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// StartJobRun => POST /rest/v1/job_runs
+// It returns the id of the history row to finish.
+func (s *JobStore) StartJobRun(ctx context.Context, jobName, holder string) (int64, error) {
+	payload := map[string]interface{}{
+		"job_name": jobName,
+		"holder":   holder,
+	}
+
+	var runs []JobRun
+	if err := s.Client.doRest(ctx, http.MethodPost, "/job_runs?select=id", payload, "return=representation", &runs); err != nil {
+		return 0, err
+	}
+
+	if len(runs) == 0 {
+		return 0, errors.New("no job run returned from database")
+	}
+
+	return runs[0].ID, nil
+}
+
+// FinishJobRun => PATCH /rest/v1/job_runs?id=eq.<id>
+func (s *JobStore) FinishJobRun(ctx context.Context, runID int64, runErr error) error {
+	payload := map[string]interface{}{
+		"finished_at": time.Now().UTC().Format(time.RFC3339Nano),
+		"success":     runErr == nil,
+	}
+	if runErr != nil {
+		payload["error"] = runErr.Error()
+	}
+
+	return s.Client.doRest(ctx, http.MethodPatch, "/job_runs?id=eq."+strconv.FormatInt(runID, 10), payload, "return=minimal", nil)
+}
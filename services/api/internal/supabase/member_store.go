@@ -0,0 +1,129 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Project member roles, matching the member_role enum
+const (
+	MemberRoleViewer = "viewer"
+	MemberRoleAdmin  = "admin"
+	MemberRoleOwner  = "owner"
+)
+
+// ProjectMember structure for database operations
+type ProjectMember struct {
+	ProjectID string    `json:"project_id"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MemberStore provides project membership data access
+type MemberStore struct {
+	Client *Client
+}
+
+// GetProjectMember => GET /rest/v1/project_members?project_id=eq.<id>&user_id=eq.<user>
+func (s *MemberStore) GetProjectMember(ctx context.Context, projectID, userID string) (*ProjectMember, error) {
+	if projectID == "" || userID == "" {
+		return nil, errors.New("project id and user id cannot be empty")
+	}
+
+	var members []ProjectMember
+	path := "/project_members?project_id=eq." + url.QueryEscape(projectID) + "&user_id=eq." + url.QueryEscape(userID) + "&select=*"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &members); err != nil {
+		return nil, err
+	}
+
+	if len(members) == 0 {
+		return nil, errors.New("project member not found")
+	}
+
+	return &members[0], nil
+}
+
+// ListProjectMembers => GET /rest/v1/project_members?project_id=eq.<id>
+func (s *MemberStore) ListProjectMembers(ctx context.Context, projectID string) ([]ProjectMember, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	var members []ProjectMember
+	path := "/project_members?project_id=eq." + url.QueryEscape(projectID) + "&select=*&order=created_at.asc"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &members); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// AddProjectMember => POST /rest/v1/project_members
+func (s *MemberStore) AddProjectMember(ctx context.Context, projectID, userID, role string) (*ProjectMember, error) {
+	if projectID == "" || userID == "" {
+		return nil, errors.New("project id and user id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+		"role":       role,
+	}
+
+	var members []ProjectMember
+	if err := s.Client.doRest(ctx, http.MethodPost, "/project_members", payload, "return=representation", &members); err != nil {
+		return nil, err
+	}
+
+	if len(members) == 0 {
+		return nil, errors.New("no project member returned from database")
+	}
+
+	return &members[0], nil
+}
+
+// UpdateProjectMemberRole => PATCH /rest/v1/project_members?project_id=eq.<id>&user_id=eq.<user>
+func (s *MemberStore) UpdateProjectMemberRole(ctx context.Context, projectID, userID, role string) (*ProjectMember, error) {
+	if projectID == "" || userID == "" {
+		return nil, errors.New("project id and user id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"role": role,
+	}
+
+	var members []ProjectMember
+	path := "/project_members?project_id=eq." + url.QueryEscape(projectID) + "&user_id=eq." + url.QueryEscape(userID)
+	if err := s.Client.doRest(ctx, http.MethodPatch, path, payload, "return=representation", &members); err != nil {
+		return nil, err
+	}
+
+	if len(members) == 0 {
+		return nil, errors.New("project member not found")
+	}
+
+	return &members[0], nil
+}
+
+// RemoveProjectMember => DELETE /rest/v1/project_members?project_id=eq.<id>&user_id=eq.<user>
+func (s *MemberStore) RemoveProjectMember(ctx context.Context, projectID, userID string) error {
+	if projectID == "" || userID == "" {
+		return errors.New("project id and user id cannot be empty")
+	}
+
+	var members []ProjectMember
+	path := "/project_members?project_id=eq." + url.QueryEscape(projectID) + "&user_id=eq." + url.QueryEscape(userID)
+	if err := s.Client.doRest(ctx, http.MethodDelete, path, nil, "return=representation", &members); err != nil {
+		return err
+	}
+
+	if len(members) == 0 {
+		return errors.New("project member not found")
+	}
+
+	return nil
+}
@@ -8,19 +8,36 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
 // Project structure for database operations
 type Project struct {
-	ID            string    `json:"id"`
-	Name          string    `json:"name"`
-	OwnerUserID   string    `json:"owner_user_id"`
-	RetentionDays *int      `json:"retention_days,omitempty"`
-	SignedOnly    *bool     `json:"signed_only,omitempty"`
-	IsDemo        *bool     `json:"is_demo,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	OwnerUserID   string     `json:"owner_user_id"`
+	RetentionDays *int       `json:"retention_days,omitempty"`
+	SignedOnly    *bool      `json:"signed_only,omitempty"`
+	IsDemo        *bool      `json:"is_demo,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	PurgeAfter    *time.Time `json:"purge_after,omitempty"`
+}
+
+// MemberProject is a project together with the caller's role in it
+type MemberProject struct {
+	Role    string  `json:"role"`
+	Project Project `json:"project"`
+}
+
+// UpdateProjectParams contains the project settings to change; nil fields are left as they are
+type UpdateProjectParams struct {
+	Name          *string
+	RetentionDays *int
+	SignedOnly    *bool
 }
 
 // ProjectKey structure for database operations
@@ -30,13 +47,19 @@ type ProjectKey struct {
 	Label             string     `json:"label"`
 	Env               string     `json:"env"`
 	SignedOnly        bool       `json:"signed_only"`
+	Scopes            []string   `json:"scopes"`
 	PublicKey         string     `json:"public_key"`
-	SecretEnc         string     `json:"secret_enc"`        // Stores hashed secret
+	SecretEnc         string     `json:"secret_enc"`         // Stores hashed secret
 	SecretFingerprint string     `json:"secret_fingerprint"` // Stores last4
 	Disabled          bool       `json:"disabled"`
 	CreatedBy         string     `json:"created_by"`
 	CreatedAt         time.Time  `json:"created_at"`
 	LastUsedAt        *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	AllowedCIDRs      []string   `json:"allowed_cidrs"`
+	AllowedOrigins    []string   `json:"allowed_origins"`
+	// Disabled because the project is pending deletion; restoring the project re-enables it
+	SuspendedByDeletion bool `json:"suspended_by_deletion"`
 }
 
 // CreateProjectKeyParams contains parameters for creating a project key
@@ -45,10 +68,15 @@ type CreateProjectKeyParams struct {
 	Label       string
 	Env         string
 	SignedOnly  bool
+	Scopes      []string
 	PublicKey   string
 	SecretHash  string
 	SecretLast4 string
 	CreatedBy   string
+	ExpiresAt   *time.Time
+	// Empty allowlists mean no restriction
+	AllowedCIDRs   []string
+	AllowedOrigins []string
 }
 
 // ProjectStore is a thin wrapper around Client that provides project-related data access.
@@ -113,12 +141,16 @@ func (s *ProjectStore) CreateProject(ctx context.Context, name string, ownerUser
 		return nil, errors.New("owner user id cannot be empty")
 	}
 
-	url := s.Client.BaseRestURL + "/projects"
-
-	payload := map[string]interface{}{
+	return s.insertProject(ctx, map[string]interface{}{
 		"name":          name,
 		"owner_user_id": ownerUserID,
-	}
+	})
+}
+
+// insertProject inserts a project row and its owner membership
+func (s *ProjectStore) insertProject(ctx context.Context, payload map[string]interface{}) (*Project, error) {
+	ownerUserID, _ := payload["owner_user_id"].(string)
+	url := s.Client.BaseRestURL + "/projects"
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -160,9 +192,178 @@ func (s *ProjectStore) CreateProject(ctx context.Context, name string, ownerUser
 		return nil, errors.New("no project returned from database")
 	}
 
+	// Record the creator as owner in project_members, which RLS and role checks rely on.
+	// PostgREST has no multi-statement transactions, so undo the project if this fails.
+	members := &MemberStore{Client: s.Client}
+	if _, err := members.AddProjectMember(ctx, projects[0].ID, ownerUserID, MemberRoleOwner); err != nil {
+		if delErr := s.Client.doRest(ctx, http.MethodDelete, "/projects?id=eq."+projects[0].ID, nil, "", nil); delErr != nil {
+			log.Printf("rollback project %s after owner member error: %s", projects[0].ID, delErr.Error())
+		}
+		return nil, err
+	}
+
 	return &projects[0], nil
 }
 
+// GetOrCreateDemoProject returns the owner's live demo project, creating it (with is_demo set, so
+// RLS lets everyone read it) when there is none
+// => GET /rest/v1/projects?owner_user_id=eq.<id>&is_demo=is.true&deleted_at=is.null
+func (s *ProjectStore) GetOrCreateDemoProject(ctx context.Context, ownerUserID, name string) (*Project, error) {
+	if ownerUserID == "" || name == "" {
+		return nil, errors.New("owner user id and name cannot be empty")
+	}
+
+	var projects []Project
+	path := "/projects?owner_user_id=eq." + url.QueryEscape(ownerUserID) + "&is_demo=is.true&deleted_at=is.null&select=*&order=created_at.asc&limit=1"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &projects); err != nil {
+		return nil, err
+	}
+	if len(projects) > 0 {
+		return &projects[0], nil
+	}
+
+	return s.insertProject(ctx, map[string]interface{}{
+		"name":          name,
+		"owner_user_id": ownerUserID,
+		"is_demo":       true,
+	})
+}
+
+// ListProjectsForUser => GET /rest/v1/project_members?user_id=eq.<id>&select=role,project:projects(*)
+// Projects are returned oldest membership first.
+func (s *ProjectStore) ListProjectsForUser(ctx context.Context, userID string) ([]MemberProject, error) {
+	if userID == "" {
+		return nil, errors.New("user id cannot be empty")
+	}
+
+	var projects []MemberProject
+	path := "/project_members?user_id=eq." + url.QueryEscape(userID) + "&select=role,project:projects(*)&order=created_at.asc"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// ListActiveProjects => GET /rest/v1/projects?deleted_at=is.null&select=id,retention_days
+// Only the columns background jobs need are selected. Pages are fetched until exhausted so the
+// PostgREST row cap does not truncate the list.
+func (s *ProjectStore) ListActiveProjects(ctx context.Context) ([]Project, error) {
+	const pageSize = 1000
+
+	var projects []Project
+	for offset := 0; ; offset += pageSize {
+		var page []Project
+		path := "/projects?deleted_at=is.null&select=id,retention_days&order=id&limit=" + strconv.Itoa(pageSize) + "&offset=" + strconv.Itoa(offset)
+		if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &page); err != nil {
+			return nil, err
+		}
+		projects = append(projects, page...)
+		if len(page) < pageSize {
+			return projects, nil
+		}
+	}
+}
+
+// UpdateProject => PATCH /rest/v1/projects?id=eq.<id>
+func (s *ProjectStore) UpdateProject(ctx context.Context, projectID string, params UpdateProjectParams) (*Project, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"updated_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	if params.Name != nil {
+		payload["name"] = *params.Name
+	}
+	if params.RetentionDays != nil {
+		payload["retention_days"] = *params.RetentionDays
+	}
+	if params.SignedOnly != nil {
+		payload["signed_only"] = *params.SignedOnly
+	}
+
+	var projects []Project
+	if err := s.Client.doRest(ctx, http.MethodPatch, "/projects?id=eq."+url.QueryEscape(projectID), payload, "return=representation", &projects); err != nil {
+		return nil, err
+	}
+
+	if len(projects) == 0 {
+		return nil, errors.New("project not found")
+	}
+
+	return &projects[0], nil
+}
+
+// SoftDeleteProject marks the project deleted until purgeAfter and disables its keys and tokens
+// => POST /rest/v1/rpc/soft_delete_project
+func (s *ProjectStore) SoftDeleteProject(ctx context.Context, projectID string, purgeAfter time.Time) (*Project, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id":  projectID,
+		"p_purge_after": purgeAfter.UTC().Format(time.RFC3339),
+	}
+
+	var project Project
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/soft_delete_project", payload, "", &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// RestoreProject undoes a deletion within its grace period and re-enables the suspended keys and tokens
+// => POST /rest/v1/rpc/restore_project
+func (s *ProjectStore) RestoreProject(ctx context.Context, projectID string) (*Project, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"p_project_id": projectID,
+	}
+
+	var project Project
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/restore_project", payload, "", &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// ListPurgeableProjects => GET /rest/v1/projects?deleted_at=not.is.null&purge_after=lte.<now>&select=id
+// It returns the deleted projects whose grace period has ended as of now.
+func (s *ProjectStore) ListPurgeableProjects(ctx context.Context, now time.Time) ([]Project, error) {
+	var projects []Project
+	path := "/projects?deleted_at=not.is.null&purge_after=lte." + url.QueryEscape(now.UTC().Format(time.RFC3339)) + "&select=id&order=id"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// PurgeProject => DELETE /rest/v1/projects?id=eq.<id>&deleted_at=not.is.null&purge_after=lte.<now>
+// Keys, tokens, members, consent records and any events left are removed by ON DELETE CASCADE.
+// It reports whether the project was purged; a project restored in the meantime is kept.
+func (s *ProjectStore) PurgeProject(ctx context.Context, projectID string, now time.Time) (bool, error) {
+	if projectID == "" {
+		return false, errors.New("project id cannot be empty")
+	}
+
+	var projects []Project
+	path := "/projects?id=eq." + url.QueryEscape(projectID) + "&deleted_at=not.is.null&purge_after=lte." + url.QueryEscape(now.UTC().Format(time.RFC3339)) + "&select=id"
+	if err := s.Client.doRest(ctx, http.MethodDelete, path, nil, "return=representation", &projects); err != nil {
+		return false, err
+	}
+
+	return len(projects) > 0, nil
+}
+
 // CreateProjectKey => POST /rest/v1/project_keys
 func (s *ProjectKeyStore) CreateProjectKey(ctx context.Context, params CreateProjectKeyParams) (*ProjectKey, error) {
 	if params.ProjectID == "" {
@@ -185,10 +386,16 @@ func (s *ProjectKeyStore) CreateProjectKey(ctx context.Context, params CreatePro
 		"label":              params.Label,
 		"env":                params.Env,
 		"signed_only":        params.SignedOnly,
+		"scopes":             params.Scopes,
 		"public_key":         params.PublicKey,
 		"secret_enc":         params.SecretHash,
 		"secret_fingerprint": params.SecretLast4,
 		"created_by":         params.CreatedBy,
+		"allowed_cidrs":      nonNilStrings(params.AllowedCIDRs),
+		"allowed_origins":    nonNilStrings(params.AllowedOrigins),
+	}
+	if params.ExpiresAt != nil {
+		payload["expires_at"] = params.ExpiresAt.UTC().Format(time.RFC3339)
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -231,3 +438,97 @@ func (s *ProjectKeyStore) CreateProjectKey(ctx context.Context, params CreatePro
 
 	return &keys[0], nil
 }
+
+// GetProjectKeyByPublicKey => GET /rest/v1/project_keys?public_key=eq.<key>
+func (s *ProjectKeyStore) GetProjectKeyByPublicKey(ctx context.Context, publicKey string) (*ProjectKey, error) {
+	if publicKey == "" {
+		return nil, errors.New("public key cannot be empty")
+	}
+
+	var keys []ProjectKey
+	path := "/project_keys?public_key=eq." + url.QueryEscape(publicKey) + "&select=*"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &keys); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("project key not found")
+	}
+
+	return &keys[0], nil
+}
+
+// projectKeyListColumns excludes the secret hash so it never leaves the store when listing keys
+const projectKeyListColumns = "id,project_id,label,env,signed_only,scopes,public_key,secret_fingerprint,disabled,created_by,created_at,last_used_at,expires_at,allowed_cidrs,allowed_origins"
+
+// ListProjectKeys => GET /rest/v1/project_keys?project_id=eq.<id>
+func (s *ProjectKeyStore) ListProjectKeys(ctx context.Context, projectID string) ([]ProjectKey, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	var keys []ProjectKey
+	path := "/project_keys?project_id=eq." + url.QueryEscape(projectID) + "&select=" + projectKeyListColumns + "&order=created_at.desc"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// DisableExpiredProjectKeys => PATCH /rest/v1/project_keys?expires_at=lte.<now>&disabled=is.false
+// It returns the number of keys that were disabled.
+func (s *ProjectKeyStore) DisableExpiredProjectKeys(ctx context.Context, now time.Time) (int, error) {
+	var keys []ProjectKey
+	path := "/project_keys?expires_at=lte." + url.QueryEscape(now.UTC().Format(time.RFC3339)) + "&disabled=is.false&select=id"
+	payload := map[string]interface{}{
+		"disabled": true,
+	}
+	if err := s.Client.doRest(ctx, http.MethodPatch, path, payload, "return=representation", &keys); err != nil {
+		return 0, err
+	}
+
+	return len(keys), nil
+}
+
+// GetProjectKeyBySecretHash => GET /rest/v1/project_keys?secret_enc=eq.<hash>
+func (s *ProjectKeyStore) GetProjectKeyBySecretHash(ctx context.Context, secretHash string) (*ProjectKey, error) {
+	if secretHash == "" {
+		return nil, errors.New("secret hash cannot be empty")
+	}
+
+	var keys []ProjectKey
+	path := "/project_keys?secret_enc=eq." + url.QueryEscape(secretHash) + "&select=*"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &keys); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("project key not found")
+	}
+
+	return &keys[0], nil
+}
+
+// DisableProjectKey => PATCH /rest/v1/project_keys?id=eq.<id>
+func (s *ProjectKeyStore) DisableProjectKey(ctx context.Context, keyID string) error {
+	if keyID == "" {
+		return errors.New("key id cannot be empty")
+	}
+
+	// Clearing the suspension keeps a restored project from re-enabling the key
+	path := "/project_keys?id=eq." + url.QueryEscape(keyID)
+	payload := map[string]interface{}{
+		"disabled":              true,
+		"suspended_by_deletion": false,
+	}
+	return s.Client.doRest(ctx, http.MethodPatch, path, payload, "return=minimal", nil)
+}
+
+// nonNilStrings returns an empty slice for nil so it is sent as [] rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,50 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ProjectSalt structure for database operations. The salt is stored encrypted.
+type ProjectSalt struct {
+	ProjectID  string `json:"project_id"`
+	SaltSealed string `json:"salt_sealed"`
+}
+
+// ProjectSaltStore provides pseudonymization salt data access
+type ProjectSaltStore struct {
+	Client *Client
+}
+
+// GetProjectSalt => GET /rest/v1/project_salts?project_id=eq.<id>
+// It returns "" when the project has no salt yet.
+func (s *ProjectSaltStore) GetProjectSalt(ctx context.Context, projectID string) (string, error) {
+	if projectID == "" {
+		return "", errors.New("project id cannot be empty")
+	}
+
+	var salts []ProjectSalt
+	if err := s.Client.doRest(ctx, http.MethodGet, "/project_salts?project_id=eq."+url.QueryEscape(projectID)+"&select=*", nil, "", &salts); err != nil {
+		return "", err
+	}
+
+	if len(salts) == 0 {
+		return "", nil
+	}
+
+	return salts[0].SaltSealed, nil
+}
+
+// CreateProjectSalt => POST /rest/v1/project_salts
+// An existing salt is never replaced: if another instance created one first, the insert is a no-op
+// and the caller reads back the winner's salt.
+func (s *ProjectSaltStore) CreateProjectSalt(ctx context.Context, projectID, saltSealed string) error {
+	if projectID == "" || saltSealed == "" {
+		return errors.New("project id and salt cannot be empty")
+	}
+
+	payload := ProjectSalt{ProjectID: projectID, SaltSealed: saltSealed}
+	return s.Client.doRest(ctx, http.MethodPost, "/project_salts?on_conflict=project_id", payload, "resolution=ignore-duplicates,return=minimal", nil)
+}
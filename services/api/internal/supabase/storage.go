@@ -0,0 +1,164 @@
+package supabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Storage is a client for the Supabase Storage API, used for files too large for table rows.
+// Uploads can take longer than REST calls, so it has its own HTTP client.
+type Storage struct {
+	BaseURL        string
+	ServiceRoleKey string
+	httpClient     *http.Client
+}
+
+// storageBaseURL. e.g. https://<project-id>.supabase.co/storage/v1
+func NewStorage(storageBaseURL, serviceRoleKey string) *Storage {
+	return &Storage{
+		BaseURL:        strings.TrimRight(storageBaseURL, "/"),
+		ServiceRoleKey: serviceRoleKey,
+		httpClient:     &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// UploadObject => POST /storage/v1/object/<bucket>/<path>
+// An existing object at the same path is replaced.
+func (s *Storage) UploadObject(ctx context.Context, bucket, path, contentType string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+"/object/"+bucket+"/"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "true")
+
+	return s.do(req, nil)
+}
+
+// CreateSignedURL => POST /storage/v1/object/sign/<bucket>/<path>
+// It returns a URL anyone can download the object from until expiresIn has passed.
+func (s *Storage) CreateSignedURL(ctx context.Context, bucket, path string, expiresIn time.Duration) (string, error) {
+	payload, err := json.Marshal(map[string]interface{}{"expiresIn": int(expiresIn.Seconds())})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+"/object/sign/"+bucket+"/"+path, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var signed struct {
+		SignedURL string `json:"signedURL"`
+	}
+	if err := s.do(req, &signed); err != nil {
+		return "", err
+	}
+	if signed.SignedURL == "" {
+		return "", errors.New("no signed url returned from storage")
+	}
+
+	// The returned path is relative to the storage API
+	return s.BaseURL + signed.SignedURL, nil
+}
+
+// DeleteObjects => DELETE /storage/v1/object/<bucket>
+func (s *Storage) DeleteObjects(ctx context.Context, bucket string, paths []string) error {
+	payload, err := json.Marshal(map[string]interface{}{"prefixes": paths})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.BaseURL+"/object/"+bucket, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return s.do(req, nil)
+}
+
+// DeleteFolder deletes every object directly under folder (e.g. "<project_id>/") and returns how
+// many were deleted. Storage only deletes objects by exact name, so the folder is listed page by
+// page => POST /storage/v1/object/list/<bucket>, then DELETE /storage/v1/object/<bucket>
+func (s *Storage) DeleteFolder(ctx context.Context, bucket, folder string) (int, error) {
+	const pageSize = 1000
+
+	folder = strings.TrimRight(folder, "/")
+	if folder == "" {
+		return 0, errors.New("folder cannot be empty")
+	}
+
+	total := 0
+	for {
+		payload, err := json.Marshal(map[string]interface{}{"prefix": folder, "limit": pageSize, "offset": 0})
+		if err != nil {
+			return total, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+"/object/list/"+bucket, bytes.NewReader(payload))
+		if err != nil {
+			return total, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		var entries []struct {
+			ID   *string `json:"id"`
+			Name string  `json:"name"`
+		}
+		if err := s.do(req, &entries); err != nil {
+			return total, err
+		}
+
+		// Entries without an id are sub-folders, which the bundles never use
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if entry.ID != nil {
+				paths = append(paths, folder+"/"+entry.Name)
+			}
+		}
+		if len(paths) == 0 {
+			return total, nil
+		}
+		if err := s.DeleteObjects(ctx, bucket, paths); err != nil {
+			return total, err
+		}
+		total += len(paths)
+		if len(entries) < pageSize {
+			return total, nil
+		}
+	}
+}
+
+// do sends a storage request with the service role headers and decodes the response into out
+func (s *Storage) do(req *http.Request, out interface{}) error {
+	req.Header.Set("apikey", s.ServiceRoleKey)
+	req.Header.Set("Authorization", "Bearer "+s.ServiceRoleKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		bodyStr := string(bodyBytes)
+		log.Printf("supabase storage api error: status=%d body=%s", resp.StatusCode, bodyStr)
+		return errors.New(bodyStr)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
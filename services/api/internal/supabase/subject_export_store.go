@@ -0,0 +1,149 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Subject export statuses, matching the subject_exports_status_check constraint
+const (
+	SubjectExportStatusPending   = "pending"
+	SubjectExportStatusRunning   = "running"
+	SubjectExportStatusCompleted = "completed"
+	SubjectExportStatusFailed    = "failed"
+	// SubjectExportStatusExpired marks completed exports whose bundle has been deleted
+	SubjectExportStatusExpired = "expired"
+)
+
+// SubjectExportBucket is the private Storage bucket holding subject access bundles
+const SubjectExportBucket = "subject-exports"
+
+// SubjectExport structure for database operations
+type SubjectExport struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	// UserIDH is only set while the export is open
+	UserIDH                *string    `json:"user_id_h,omitempty"`
+	SubjectHash            string     `json:"subject_hash"`
+	Status                 string     `json:"status"`
+	RequestedBy            string     `json:"requested_by"`
+	CreatedAt              time.Time  `json:"created_at"`
+	StartedAt              *time.Time `json:"started_at,omitempty"`
+	CompletedAt            *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt              *time.Time `json:"expires_at,omitempty"`
+	Attempts               int        `json:"attempts"`
+	ObjectPath             *string    `json:"object_path,omitempty"`
+	EventsExported         int        `json:"events_exported"`
+	ConsentsExported       int        `json:"consents_exported"`
+	ConsentHistoryExported int        `json:"consent_history_exported"`
+	Error                  *string    `json:"error,omitempty"`
+}
+
+// CreateSubjectExportParams contains parameters for creating a subject export
+type CreateSubjectExportParams struct {
+	ProjectID   string
+	UserIDH     string
+	SubjectHash string
+	RequestedBy string
+}
+
+// SubjectExportStore provides data subject access export data access
+type SubjectExportStore struct {
+	Client *Client
+}
+
+// CreateSubjectExport => POST /rest/v1/subject_exports
+func (s *SubjectExportStore) CreateSubjectExport(ctx context.Context, params CreateSubjectExportParams) (*SubjectExport, error) {
+	if params.ProjectID == "" || params.UserIDH == "" || params.SubjectHash == "" {
+		return nil, errors.New("project id, user id hash and subject hash cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"project_id":   params.ProjectID,
+		"user_id_h":    params.UserIDH,
+		"subject_hash": params.SubjectHash,
+		"requested_by": params.RequestedBy,
+	}
+
+	var exports []SubjectExport
+	if err := s.Client.doRest(ctx, http.MethodPost, "/subject_exports?select=*", payload, "return=representation", &exports); err != nil {
+		return nil, err
+	}
+
+	if len(exports) == 0 {
+		return nil, errors.New("no subject export returned from database")
+	}
+
+	return &exports[0], nil
+}
+
+// GetSubjectExport => GET /rest/v1/subject_exports?id=eq.<id>&project_id=eq.<project>
+func (s *SubjectExportStore) GetSubjectExport(ctx context.Context, projectID, exportID string) (*SubjectExport, error) {
+	if projectID == "" || exportID == "" {
+		return nil, errors.New("project id and export id cannot be empty")
+	}
+
+	var exports []SubjectExport
+	path := "/subject_exports?id=eq." + url.QueryEscape(exportID) + "&project_id=eq." + url.QueryEscape(projectID) + "&select=*"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &exports); err != nil {
+		return nil, err
+	}
+
+	if len(exports) == 0 {
+		return nil, errors.New("subject export not found")
+	}
+
+	return &exports[0], nil
+}
+
+// ListOpenSubjectExports => GET /rest/v1/subject_exports?status=in.(pending,running)
+// Oldest exports come first.
+func (s *SubjectExportStore) ListOpenSubjectExports(ctx context.Context, limit int) ([]SubjectExport, error) {
+	var exports []SubjectExport
+	path := "/subject_exports?status=in.(pending,running)&select=*&order=created_at.asc&limit=" + strconv.Itoa(limit)
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &exports); err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// ListExpiredSubjectExports => GET /rest/v1/subject_exports?status=eq.completed&expires_at=lt.<now>
+func (s *SubjectExportStore) ListExpiredSubjectExports(ctx context.Context, now time.Time, limit int) ([]SubjectExport, error) {
+	var exports []SubjectExport
+	path := "/subject_exports?status=eq.completed&expires_at=lt." + url.QueryEscape(now.UTC().Format(time.RFC3339)) +
+		"&select=*&order=expires_at.asc&limit=" + strconv.Itoa(limit)
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &exports); err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// SaveSubjectExport => PATCH /rest/v1/subject_exports?id=eq.<id>
+// It writes the export's progress; nil fields are stored as NULL, which clears user_id_h.
+func (s *SubjectExportStore) SaveSubjectExport(ctx context.Context, export SubjectExport) error {
+	if export.ID == "" {
+		return errors.New("export id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"status":                   export.Status,
+		"user_id_h":                export.UserIDH,
+		"attempts":                 export.Attempts,
+		"object_path":              export.ObjectPath,
+		"events_exported":          export.EventsExported,
+		"consents_exported":        export.ConsentsExported,
+		"consent_history_exported": export.ConsentHistoryExported,
+		"error":                    export.Error,
+		"started_at":               formatOptionalTime(export.StartedAt),
+		"completed_at":             formatOptionalTime(export.CompletedAt),
+		"expires_at":               formatOptionalTime(export.ExpiresAt),
+	}
+
+	return s.Client.doRest(ctx, http.MethodPatch, "/subject_exports?id=eq."+url.QueryEscape(export.ID), payload, "return=minimal", nil)
+}
@@ -0,0 +1,134 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ProjectToken structure for database operations (project access tokens)
+type ProjectToken struct {
+	ID         string     `json:"id"`
+	ProjectID  string     `json:"project_id"`
+	Label      string     `json:"label"`
+	Scopes     []string   `json:"scopes"`
+	TokenHash  string     `json:"token_hash,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Revoked    bool       `json:"revoked"`
+}
+
+// CreateProjectTokenParams contains parameters for creating a project access token
+type CreateProjectTokenParams struct {
+	ProjectID string
+	Label     string
+	Scopes    []string
+	TokenHash string
+	CreatedBy string
+	ExpiresAt *time.Time
+}
+
+// projectTokenListColumns excludes the token hash so it never leaves the store when listing tokens
+const projectTokenListColumns = "id,project_id,label,scopes,created_by,created_at,last_used_at,expires_at,revoked"
+
+// ProjectTokenStore provides project access token data access
+type ProjectTokenStore struct {
+	Client *Client
+}
+
+// CreateProjectToken => POST /rest/v1/project_tokens
+func (s *ProjectTokenStore) CreateProjectToken(ctx context.Context, params CreateProjectTokenParams) (*ProjectToken, error) {
+	if params.ProjectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+	if params.Label == "" {
+		return nil, errors.New("label cannot be empty")
+	}
+	if params.TokenHash == "" {
+		return nil, errors.New("token hash cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"project_id": params.ProjectID,
+		"label":      params.Label,
+		"scopes":     params.Scopes,
+		"token_hash": params.TokenHash,
+		"created_by": params.CreatedBy,
+	}
+	if params.ExpiresAt != nil {
+		payload["expires_at"] = params.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	var tokens []ProjectToken
+	path := "/project_tokens?select=" + projectTokenListColumns
+	if err := s.Client.doRest(ctx, http.MethodPost, path, payload, "return=representation", &tokens); err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("no project token returned from database")
+	}
+
+	return &tokens[0], nil
+}
+
+// ListProjectTokens => GET /rest/v1/project_tokens?project_id=eq.<id>
+func (s *ProjectTokenStore) ListProjectTokens(ctx context.Context, projectID string) ([]ProjectToken, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	var tokens []ProjectToken
+	path := "/project_tokens?project_id=eq." + url.QueryEscape(projectID) + "&select=" + projectTokenListColumns + "&order=created_at.desc"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// RevokeProjectToken => PATCH /rest/v1/project_tokens?id=eq.<id>&project_id=eq.<project>
+func (s *ProjectTokenStore) RevokeProjectToken(ctx context.Context, projectID, tokenID string) error {
+	if projectID == "" || tokenID == "" {
+		return errors.New("project id and token id cannot be empty")
+	}
+
+	var tokens []ProjectToken
+	path := "/project_tokens?id=eq." + url.QueryEscape(tokenID) + "&project_id=eq." + url.QueryEscape(projectID) + "&select=id"
+	payload := map[string]interface{}{
+		"revoked":               true,
+		"suspended_by_deletion": false,
+	}
+	if err := s.Client.doRest(ctx, http.MethodPatch, path, payload, "return=representation", &tokens); err != nil {
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return errors.New("project token not found")
+	}
+
+	return nil
+}
+
+// GetProjectTokenByHash => GET /rest/v1/project_tokens?token_hash=eq.<hash>
+func (s *ProjectTokenStore) GetProjectTokenByHash(ctx context.Context, tokenHash string) (*ProjectToken, error) {
+	if tokenHash == "" {
+		return nil, errors.New("token hash cannot be empty")
+	}
+
+	var tokens []ProjectToken
+	path := "/project_tokens?token_hash=eq." + url.QueryEscape(tokenHash) + "&select=" + projectTokenListColumns
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &tokens); err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("project token not found")
+	}
+
+	return &tokens[0], nil
+}
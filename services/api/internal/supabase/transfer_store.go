@@ -0,0 +1,134 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// OwnershipTransfer structure for database operations
+type OwnershipTransfer struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	FromUserID  string     `json:"from_user_id"`
+	ToUserID    string     `json:"to_user_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// CreateOwnershipTransferParams contains parameters for proposing an ownership transfer
+type CreateOwnershipTransferParams struct {
+	ProjectID  string
+	FromUserID string
+	ToUserID   string
+	ExpiresAt  time.Time
+}
+
+// OwnershipTransferStore provides project ownership transfer data access
+type OwnershipTransferStore struct {
+	Client *Client
+}
+
+// CreateOwnershipTransfer cancels any open transfer of the project and records the new one
+// => PATCH + POST /rest/v1/project_ownership_transfers
+func (s *OwnershipTransferStore) CreateOwnershipTransfer(ctx context.Context, params CreateOwnershipTransferParams) (*OwnershipTransfer, error) {
+	if params.ProjectID == "" || params.FromUserID == "" || params.ToUserID == "" {
+		return nil, errors.New("project id, from user id and to user id cannot be empty")
+	}
+
+	// Expired transfers still hold the pending slot until they are cancelled
+	cancel := map[string]interface{}{
+		"cancelled_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	openPath := "/project_ownership_transfers?project_id=eq." + url.QueryEscape(params.ProjectID) + "&accepted_at=is.null&cancelled_at=is.null"
+	if err := s.Client.doRest(ctx, http.MethodPatch, openPath, cancel, "return=minimal", nil); err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"project_id":   params.ProjectID,
+		"from_user_id": params.FromUserID,
+		"to_user_id":   params.ToUserID,
+		"expires_at":   params.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+
+	var transfers []OwnershipTransfer
+	if err := s.Client.doRest(ctx, http.MethodPost, "/project_ownership_transfers", payload, "return=representation", &transfers); err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, errors.New("no ownership transfer returned from database")
+	}
+
+	return &transfers[0], nil
+}
+
+// GetPendingOwnershipTransfer => GET /rest/v1/project_ownership_transfers?project_id=eq.<id>&accepted_at=is.null&cancelled_at=is.null&expires_at=gt.<now>
+func (s *OwnershipTransferStore) GetPendingOwnershipTransfer(ctx context.Context, projectID string, now time.Time) (*OwnershipTransfer, error) {
+	if projectID == "" {
+		return nil, errors.New("project id cannot be empty")
+	}
+
+	var transfers []OwnershipTransfer
+	path := "/project_ownership_transfers?project_id=eq." + url.QueryEscape(projectID) +
+		"&accepted_at=is.null&cancelled_at=is.null&expires_at=gt." + url.QueryEscape(now.UTC().Format(time.RFC3339)) +
+		"&select=*&limit=1"
+	if err := s.Client.doRest(ctx, http.MethodGet, path, nil, "", &transfers); err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, errors.New("ownership transfer not found")
+	}
+
+	return &transfers[0], nil
+}
+
+// CancelOwnershipTransfer => PATCH /rest/v1/project_ownership_transfers?id=eq.<id>&accepted_at=is.null&cancelled_at=is.null
+func (s *OwnershipTransferStore) CancelOwnershipTransfer(ctx context.Context, transferID string) error {
+	if transferID == "" {
+		return errors.New("transfer id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"cancelled_at": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	var transfers []OwnershipTransfer
+	path := "/project_ownership_transfers?id=eq." + url.QueryEscape(transferID) + "&accepted_at=is.null&cancelled_at=is.null&select=id"
+	if err := s.Client.doRest(ctx, http.MethodPatch, path, payload, "return=representation", &transfers); err != nil {
+		return err
+	}
+
+	if len(transfers) == 0 {
+		return errors.New("ownership transfer not found")
+	}
+
+	return nil
+}
+
+// AcceptOwnershipTransfer completes the transfer on behalf of userID and returns the updated project.
+// Roles, owner_user_id and the audit entry change in one transaction.
+// => POST /rest/v1/rpc/accept_project_ownership_transfer
+func (s *OwnershipTransferStore) AcceptOwnershipTransfer(ctx context.Context, transferID, userID string) (*Project, error) {
+	if transferID == "" || userID == "" {
+		return nil, errors.New("transfer id and user id cannot be empty")
+	}
+
+	payload := map[string]interface{}{
+		"p_transfer_id": transferID,
+		"p_user_id":     userID,
+	}
+
+	var project Project
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/accept_project_ownership_transfer", payload, "", &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
@@ -1,6 +1,10 @@
 package supabase
 
-import "context"
+import (
+	"context"
+	"errors"
+	"net/http"
+)
 
 // UserStore is a thin wrapper around Client that provides user-related data access.
 // It is used as the concrete implementation injected into handlers.
@@ -12,3 +16,24 @@ type UserStore struct {
 func (s *UserStore) GetUserByID(ctx context.Context, id string) (*User, error) {
 	return s.Client.GetUserByID(ctx, id)
 }
+
+// GetUserIDByEmail => POST /rest/v1/rpc/get_user_id_by_email
+func (s *UserStore) GetUserIDByEmail(ctx context.Context, email string) (string, error) {
+	if email == "" {
+		return "", errors.New("email cannot be empty")
+	}
+
+	var userID *string
+	payload := map[string]interface{}{
+		"p_email": email,
+	}
+	if err := s.Client.doRest(ctx, http.MethodPost, "/rpc/get_user_id_by_email", payload, "", &userID); err != nil {
+		return "", err
+	}
+
+	if userID == nil || *userID == "" {
+		return "", errors.New("user not found")
+	}
+
+	return *userID, nil
+}
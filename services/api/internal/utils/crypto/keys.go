@@ -4,9 +4,12 @@ import (
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
-	"encoding/base64"
+	"crypto/subtle"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash/crc32"
+	"strings"
 )
 
 var secretPepper string
@@ -16,24 +19,210 @@ func Init(pepper string) {
 	secretPepper = pepper
 }
 
-// GeneratePublicKey generates a public key in format: pk_live_<random>
-func GeneratePublicKey() (string, error) {
-	randomBytes := make([]byte, 24) // 24 bytes = 32 chars base64
-	if _, err := rand.Read(randomBytes); err != nil {
-		return "", err
+// Key environments, matching the project_keys_env_check constraint
+const (
+	EnvProd    = "prod"
+	EnvStaging = "staging"
+	EnvDev     = "dev"
+)
+
+// IsValidEnv reports whether env is one of the supported key environments
+func IsValidEnv(env string) bool {
+	switch env {
+	case EnvProd, EnvStaging, EnvDev:
+		return true
+	}
+	return false
+}
+
+// envMode maps a key environment to the mode embedded in key prefixes: live for prod, test otherwise
+func envMode(env string) string {
+	if env == EnvProd {
+		return "live"
+	}
+	return "test"
+}
+
+// Key prefixes. The random part and checksum that follow are base62, so a key is a single
+// word to secret scanners and can be matched with:
+//
+//	pk_(live|test)_[0-9A-Za-z]{36}
+//	psk_(live|test)_[0-9A-Za-z]{46}
+//	lpat_[0-9A-Za-z]{46}
+const (
+	publicKeyPrefix = "pk_"
+	secretPrefix    = "psk_"
+)
+
+// Lengths of the random part of each credential, excluding the checksum
+const (
+	publicKeyRandomLen  = 30
+	secretRandomLen     = 40
+	patRandomLen        = 40
+	invitationRandomLen = 40
+	checksumLen         = 6
+)
+
+// PATPrefix starts every project access token: lpat_[0-9A-Za-z]{46}
+const PATPrefix = "lpat_"
+
+// InvitationPrefix starts every project invitation token: lpinv_[0-9A-Za-z]{46}
+const InvitationPrefix = "lpinv_"
+
+// Lengths of the base64url random part used before checksums were introduced.
+// Keys issued in that format stay valid but cannot be checked offline.
+const (
+	legacyPublicKeyRandomLen = 32
+	legacySecretRandomLen    = 43
+)
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrMalformedKey is returned when a key or secret fails the offline format or checksum check
+var ErrMalformedKey = errors.New("malformed key")
+
+// GeneratePublicKey generates a public key in format: pk_<live|test>_<random><checksum>
+func GeneratePublicKey(env string) (string, error) {
+	if !IsValidEnv(env) {
+		return "", fmt.Errorf("invalid key env %q", env)
+	}
+	return generateChecksummed(publicKeyPrefix+envMode(env)+"_", publicKeyRandomLen)
+}
+
+// GenerateSecret generates a secret key in format: psk_<live|test>_<random><checksum>
+func GenerateSecret(env string) (string, error) {
+	if !IsValidEnv(env) {
+		return "", fmt.Errorf("invalid key env %q", env)
+	}
+	return generateChecksummed(secretPrefix+envMode(env)+"_", secretRandomLen)
+}
+
+// ValidatePublicKey checks the format and checksum of a public key without any database lookup
+func ValidatePublicKey(key string) error {
+	return validateChecksummed(key, publicKeyPrefix, publicKeyRandomLen, legacyPublicKeyRandomLen)
+}
+
+// ValidateSecret checks the format and checksum of a secret without any database lookup
+func ValidateSecret(secret string) error {
+	return validateChecksummed(secret, secretPrefix, secretRandomLen, legacySecretRandomLen)
+}
+
+// GeneratePAT generates a project access token in format: lpat_<random><checksum>
+func GeneratePAT() (string, error) {
+	return generateChecksummed(PATPrefix, patRandomLen)
+}
+
+// ValidatePAT checks the format and checksum of a project access token without any database lookup
+func ValidatePAT(token string) error {
+	if !strings.HasPrefix(token, PATPrefix) {
+		return ErrMalformedKey
 	}
-	encoded := base64.RawURLEncoding.EncodeToString(randomBytes)
-	return fmt.Sprintf("pk_live_%s", encoded), nil
+	return verifyChecksum(token, token[len(PATPrefix):], patRandomLen)
 }
 
-// GenerateSecret generates a secret key in format: psk_live_<random>
-func GenerateSecret() (string, error) {
-	randomBytes := make([]byte, 32) // 32 bytes = 43 chars base64
-	if _, err := rand.Read(randomBytes); err != nil {
+// GenerateInvitationToken creates a single-use project invitation token with a checksum
+func GenerateInvitationToken() (string, error) {
+	return generateChecksummed(InvitationPrefix, invitationRandomLen)
+}
+
+// ValidateInvitationToken checks the format and checksum of an invitation token without any database lookup
+func ValidateInvitationToken(token string) error {
+	if !strings.HasPrefix(token, InvitationPrefix) {
+		return ErrMalformedKey
+	}
+	return verifyChecksum(token, token[len(InvitationPrefix):], invitationRandomLen)
+}
+
+// generateChecksummed returns prefix followed by n random base62 characters and their checksum
+func generateChecksummed(prefix string, n int) (string, error) {
+	random, err := randomBase62(n)
+	if err != nil {
 		return "", err
 	}
-	encoded := base64.RawURLEncoding.EncodeToString(randomBytes)
-	return fmt.Sprintf("psk_live_%s", encoded), nil
+	body := prefix + random
+	return body + checksum(body), nil
+}
+
+// validateChecksummed checks that token is prefix + (live|test)_ followed by either n random
+// base62 characters and a matching checksum, or a legacy base64url random part of legacyN characters
+func validateChecksummed(token, prefix string, n, legacyN int) error {
+	if !strings.HasPrefix(token, prefix) {
+		return ErrMalformedKey
+	}
+	rest := token[len(prefix):]
+	switch {
+	case strings.HasPrefix(rest, "live_"), strings.HasPrefix(rest, "test_"):
+		rest = rest[len("live_"):]
+	default:
+		return ErrMalformedKey
+	}
+
+	if len(rest) == legacyN {
+		for _, r := range rest {
+			if !isBase64URL(r) {
+				return ErrMalformedKey
+			}
+		}
+		return nil
+	}
+
+	return verifyChecksum(token, rest, n)
+}
+
+// verifyChecksum checks that rest, the tail of token, is n base62 characters followed by the
+// checksum of everything in token before the checksum
+func verifyChecksum(token, rest string, n int) error {
+	if len(rest) != n+checksumLen {
+		return ErrMalformedKey
+	}
+	for _, r := range rest {
+		if !strings.ContainsRune(base62Alphabet, r) {
+			return ErrMalformedKey
+		}
+	}
+
+	body := token[:len(token)-checksumLen]
+	if subtle.ConstantTimeCompare([]byte(checksum(body)), []byte(token[len(body):])) != 1 {
+		return ErrMalformedKey
+	}
+	return nil
+}
+
+// checksum returns the CRC32 of s encoded as fixed-width base62
+func checksum(s string) string {
+	sum := uint64(crc32.ChecksumIEEE([]byte(s)))
+	out := make([]byte, checksumLen)
+	for i := checksumLen - 1; i >= 0; i-- {
+		out[i] = base62Alphabet[sum%62]
+		sum /= 62
+	}
+	return string(out)
+}
+
+// randomBase62 returns n uniformly distributed base62 characters
+func randomBase62(n int) (string, error) {
+	out := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			// Reject bytes >= 248 (62*4) so every character is equally likely
+			if b >= 248 {
+				continue
+			}
+			out = append(out, base62Alphabet[b%62])
+			if len(out) == n {
+				break
+			}
+		}
+	}
+	return string(out), nil
+}
+
+func isBase64URL(r rune) bool {
+	return strings.ContainsRune(base62Alphabet, r) || r == '-' || r == '_'
 }
 
 // HashSecret hashes a secret using HMAC-SHA256 with pepper and returns hex-encoded hash
@@ -50,3 +239,19 @@ func GetLast4(secret string) string {
 	}
 	return secret[len(secret)-4:]
 }
+
+// HashIP hashes a client IP with the pepper so audit logs can correlate requests without storing the IP
+func HashIP(ip string) string {
+	return HashSecret("ip:" + ip)
+}
+
+// HashSubject hashes an end user's user_id_h with the pepper so audit logs and erasure records
+// can refer to a data subject without storing the identifier
+func HashSubject(userIDH string) string {
+	return HashSecret("subject:" + userIDH)
+}
+
+// VerifySecret reports whether secret matches the stored hash, using a constant-time comparison
+func VerifySecret(secret, hash string) bool {
+	return hmac.Equal([]byte(HashSecret(secret)), []byte(hash))
+}
@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -32,6 +33,16 @@ func TestHashSecret(t *testing.T) {
 	}
 }
 
+func TestHashSubject(t *testing.T) {
+	hash := HashSubject("user-hash-1")
+	if hash != HashSubject("user-hash-1") {
+		t.Error("HashSubject not deterministic")
+	}
+	if strings.Contains(hash, "user-hash-1") || hash == HashSecret("user-hash-1") {
+		t.Error("HashSubject must not reveal the identifier or collide with secret hashes")
+	}
+}
+
 func TestGetLast4(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -52,7 +63,7 @@ func TestGetLast4(t *testing.T) {
 }
 
 func TestGeneratePublicKey(t *testing.T) {
-	key, err := GeneratePublicKey()
+	key, err := GeneratePublicKey(EnvProd)
 	if err != nil {
 		t.Fatalf("GeneratePublicKey error: %v", err)
 	}
@@ -64,14 +75,14 @@ func TestGeneratePublicKey(t *testing.T) {
 	}
 
 	// Should be unique
-	key2, _ := GeneratePublicKey()
+	key2, _ := GeneratePublicKey(EnvProd)
 	if key == key2 {
 		t.Errorf("GeneratePublicKey produced duplicate keys")
 	}
 }
 
 func TestGenerateSecret(t *testing.T) {
-	secret, err := GenerateSecret()
+	secret, err := GenerateSecret(EnvProd)
 	if err != nil {
 		t.Fatalf("GenerateSecret error: %v", err)
 	}
@@ -83,8 +94,165 @@ func TestGenerateSecret(t *testing.T) {
 	}
 
 	// Should be unique
-	secret2, _ := GenerateSecret()
+	secret2, _ := GenerateSecret(EnvProd)
 	if secret == secret2 {
 		t.Errorf("GenerateSecret produced duplicate secrets")
 	}
 }
+
+func TestGenerateKeys_EnvPrefixes(t *testing.T) {
+	tests := []struct {
+		env          string
+		publicPrefix string
+		secretPrefix string
+	}{
+		{EnvProd, "pk_live_", "psk_live_"},
+		{EnvStaging, "pk_test_", "psk_test_"},
+		{EnvDev, "pk_test_", "psk_test_"},
+	}
+
+	for _, tt := range tests {
+		key, err := GeneratePublicKey(tt.env)
+		if err != nil {
+			t.Fatalf("GeneratePublicKey(%q) error: %v", tt.env, err)
+		}
+		if !strings.HasPrefix(key, tt.publicPrefix) {
+			t.Errorf("GeneratePublicKey(%q) = %q, should start with %q", tt.env, key, tt.publicPrefix)
+		}
+
+		secret, err := GenerateSecret(tt.env)
+		if err != nil {
+			t.Fatalf("GenerateSecret(%q) error: %v", tt.env, err)
+		}
+		if !strings.HasPrefix(secret, tt.secretPrefix) {
+			t.Errorf("GenerateSecret(%q) = %q, should start with %q", tt.env, secret, tt.secretPrefix)
+		}
+	}
+
+	if _, err := GeneratePublicKey("production"); err == nil {
+		t.Errorf("GeneratePublicKey accepted an unknown env")
+	}
+	if _, err := GenerateSecret(""); err == nil {
+		t.Errorf("GenerateSecret accepted an empty env")
+	}
+}
+
+func TestVerifySecret(t *testing.T) {
+	secret := "psk_live_test123"
+	hash := HashSecret(secret)
+
+	if !VerifySecret(secret, hash) {
+		t.Errorf("VerifySecret(%q) = false, want true", secret)
+	}
+
+	if VerifySecret("psk_live_wrong", hash) {
+		t.Errorf("VerifySecret accepted a different secret")
+	}
+
+	if VerifySecret(secret, "") {
+		t.Errorf("VerifySecret accepted an empty hash")
+	}
+}
+
+func TestValidateGeneratedKeys(t *testing.T) {
+	for _, env := range []string{EnvProd, EnvStaging, EnvDev} {
+		key, _ := GeneratePublicKey(env)
+		if err := ValidatePublicKey(key); err != nil {
+			t.Errorf("ValidatePublicKey(%q) = %v, want nil", key, err)
+		}
+
+		secret, _ := GenerateSecret(env)
+		if err := ValidateSecret(secret); err != nil {
+			t.Errorf("ValidateSecret(%q) = %v, want nil", secret, err)
+		}
+
+		// A public key is not a valid secret and vice versa
+		if err := ValidateSecret(key); err == nil {
+			t.Errorf("ValidateSecret accepted public key %q", key)
+		}
+		if err := ValidatePublicKey(secret); err == nil {
+			t.Errorf("ValidatePublicKey accepted secret %q", secret)
+		}
+	}
+}
+
+func TestValidatePublicKey_RejectsTypos(t *testing.T) {
+	key, _ := GeneratePublicKey(EnvProd)
+
+	// Change one character of the random part
+	i := len("pk_live_") + 3
+	replacement := byte('a')
+	if key[i] == replacement {
+		replacement = 'b'
+	}
+	typo := key[:i] + string(replacement) + key[i+1:]
+
+	tests := []string{
+		typo,
+		key[:len(key)-1],
+		key + "x",
+		"pk_prod_" + key[len("pk_live_"):],
+		"pk_live_" + strings.Repeat("-", 36),
+		"",
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePublicKey(tt); err == nil {
+			t.Errorf("ValidatePublicKey(%q) = nil, want error", tt)
+		}
+	}
+}
+
+func TestValidate_LegacyFormat(t *testing.T) {
+	legacyKey := "pk_live_" + strings.Repeat("aB-_", 8)
+	if err := ValidatePublicKey(legacyKey); err != nil {
+		t.Errorf("ValidatePublicKey(%q) = %v, want nil for legacy key", legacyKey, err)
+	}
+
+	legacySecret := "psk_live_" + strings.Repeat("x", 43)
+	if err := ValidateSecret(legacySecret); err != nil {
+		t.Errorf("ValidateSecret(%q) = %v, want nil for legacy secret", legacySecret, err)
+	}
+}
+
+func TestGeneratePAT(t *testing.T) {
+	token, err := GeneratePAT()
+	if err != nil {
+		t.Fatalf("GeneratePAT error: %v", err)
+	}
+
+	if !strings.HasPrefix(token, PATPrefix) {
+		t.Errorf("GeneratePAT = %q, should start with %q", token, PATPrefix)
+	}
+	if err := ValidatePAT(token); err != nil {
+		t.Errorf("ValidatePAT(%q) = %v, want nil", token, err)
+	}
+
+	// Truncated or foreign credentials are rejected
+	if err := ValidatePAT(token[:len(token)-1]); err == nil {
+		t.Errorf("ValidatePAT accepted a truncated token")
+	}
+	secret, _ := GenerateSecret(EnvProd)
+	if err := ValidatePAT(secret); err == nil {
+		t.Errorf("ValidatePAT accepted a project secret")
+	}
+}
+
+func TestGenerateInvitationToken(t *testing.T) {
+	token, err := GenerateInvitationToken()
+	if err != nil {
+		t.Fatalf("GenerateInvitationToken error: %v", err)
+	}
+
+	if !strings.HasPrefix(token, InvitationPrefix) {
+		t.Errorf("GenerateInvitationToken = %q, should start with %q", token, InvitationPrefix)
+	}
+	if err := ValidateInvitationToken(token); err != nil {
+		t.Errorf("ValidateInvitationToken(%q) = %v, want nil", token, err)
+	}
+
+	pat, _ := GeneratePAT()
+	if err := ValidateInvitationToken(pat); err == nil {
+		t.Errorf("ValidateInvitationToken accepted a project access token")
+	}
+}
@@ -0,0 +1,79 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+// SaltSize is the length in bytes of per-project pseudonymization salts
+const SaltSize = 32
+
+// ErrSealedSalt is returned when a stored salt cannot be decrypted, e.g. after the pepper changed
+var ErrSealedSalt = errors.New("sealed salt cannot be opened")
+
+// GenerateSalt returns a new random per-project salt
+func GenerateSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// SealSalt encrypts a project's salt for storage with AES-256-GCM under a key derived from the
+// pepper. The project id is bound as additional data, so a sealed salt copied to another project
+// does not open.
+func SealSalt(projectID string, salt []byte) (string, error) {
+	aead, err := saltCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, salt, []byte(projectID))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSalt decrypts a salt sealed by SealSalt for the same project
+func OpenSalt(projectID, sealed string) ([]byte, error) {
+	aead, err := saltCipher()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return nil, ErrSealedSalt
+	}
+	salt, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(projectID))
+	if err != nil {
+		return nil, ErrSealedSalt
+	}
+	return salt, nil
+}
+
+// PseudonymizeUserID hashes a raw end user identifier with the project's salt into a user_id_h.
+// Without the salt the hash cannot be matched against precomputed tables.
+func PseudonymizeUserID(salt []byte, userID string) string {
+	h := hmac.New(sha256.New, salt)
+	h.Write([]byte(userID))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func saltCipher() (cipher.AEAD, error) {
+	// A dedicated key, so salts are not encrypted with anything that is also used for hashing
+	h := hmac.New(sha256.New, []byte(secretPepper))
+	h.Write([]byte("project-salt-key"))
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSealSalt_RoundTrip(t *testing.T) {
+	salt, err := GenerateSalt()
+	if err != nil {
+		t.Fatalf("GenerateSalt error: %v", err)
+	}
+	if len(salt) != SaltSize {
+		t.Fatalf("salt length = %d, want %d", len(salt), SaltSize)
+	}
+
+	sealed, err := SealSalt("proj-1", salt)
+	if err != nil {
+		t.Fatalf("SealSalt error: %v", err)
+	}
+	if bytes.Contains([]byte(sealed), salt) {
+		t.Error("sealed salt contains the plain salt")
+	}
+
+	opened, err := OpenSalt("proj-1", sealed)
+	if err != nil {
+		t.Fatalf("OpenSalt error: %v", err)
+	}
+	if !bytes.Equal(opened, salt) {
+		t.Error("OpenSalt did not return the sealed salt")
+	}
+}
+
+func TestOpenSalt_Rejects(t *testing.T) {
+	salt, _ := GenerateSalt()
+	sealed, _ := SealSalt("proj-1", salt)
+
+	if _, err := OpenSalt("proj-2", sealed); !errors.Is(err, ErrSealedSalt) {
+		t.Errorf("salt of another project: err = %v, want ErrSealedSalt", err)
+	}
+	if _, err := OpenSalt("proj-1", "not base64!"); !errors.Is(err, ErrSealedSalt) {
+		t.Errorf("garbage: err = %v, want ErrSealedSalt", err)
+	}
+
+	Init("another-pepper")
+	defer Init("test-pepper-for-hmac-hashing")
+	if _, err := OpenSalt("proj-1", sealed); !errors.Is(err, ErrSealedSalt) {
+		t.Errorf("other pepper: err = %v, want ErrSealedSalt", err)
+	}
+}
+
+func TestPseudonymizeUserID(t *testing.T) {
+	saltA, _ := GenerateSalt()
+	saltB, _ := GenerateSalt()
+
+	h := PseudonymizeUserID(saltA, "alice@example.com")
+	if h != PseudonymizeUserID(saltA, "alice@example.com") {
+		t.Error("PseudonymizeUserID not deterministic")
+	}
+	if len(h) != 64 || strings.Contains(h, "alice") {
+		t.Errorf("unexpected hash %q", h)
+	}
+	if h == PseudonymizeUserID(saltB, "alice@example.com") {
+		t.Error("different project salts must give different hashes")
+	}
+}
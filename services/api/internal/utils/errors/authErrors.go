@@ -2,6 +2,9 @@ package errors
 
 // Authentication-related error codes
 const (
-	ErrUnauthorized ErrorCode = "unauthorized"
-	ErrInvalidToken ErrorCode = "invalid_token"
+	ErrUnauthorized     ErrorCode = "unauthorized"
+	ErrInvalidToken     ErrorCode = "invalid_token"
+	ErrInvalidSignature ErrorCode = "invalid_signature"
+	ErrTokenExpired     ErrorCode = "token_expired"
+	ErrInvalidAudience  ErrorCode = "invalid_audience"
 )
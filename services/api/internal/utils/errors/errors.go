@@ -27,6 +27,57 @@ var ErrorMapping = map[ErrorCode]*APIError{
 		Code:   ErrInvalidToken,
 		Status: http.StatusUnauthorized,
 	},
+	ErrInvalidSignature: {
+		Error:  "Missing or invalid request signature",
+		Code:   ErrInvalidSignature,
+		Status: http.StatusUnauthorized,
+	},
+	ErrTokenExpired: {
+		Error:  "Session token has expired",
+		Code:   ErrTokenExpired,
+		Status: http.StatusUnauthorized,
+	},
+	ErrInvalidAudience: {
+		Error:  "Session token was not issued for this API",
+		Code:   ErrInvalidAudience,
+		Status: http.StatusUnauthorized,
+	},
+	// Project key errors
+	ErrInvalidProjectKey: {
+		Error:  "Missing or invalid project key",
+		Code:   ErrInvalidProjectKey,
+		Status: http.StatusUnauthorized,
+	},
+	ErrProjectKeyDisabled: {
+		Error:  "Project key is disabled",
+		Code:   ErrProjectKeyDisabled,
+		Status: http.StatusForbidden,
+	},
+	ErrProjectKeyExpired: {
+		Error:  "Project key has expired",
+		Code:   ErrProjectKeyExpired,
+		Status: http.StatusUnauthorized,
+	},
+	ErrSignatureRequired: {
+		Error:  "Project key requires a valid secret",
+		Code:   ErrSignatureRequired,
+		Status: http.StatusUnauthorized,
+	},
+	ErrInsufficientScope: {
+		Error:  "Project key is missing a required scope",
+		Code:   ErrInsufficientScope,
+		Status: http.StatusForbidden,
+	},
+	ErrIPNotAllowed: {
+		Error:  "Request IP is not allowed for this project key",
+		Code:   ErrIPNotAllowed,
+		Status: http.StatusForbidden,
+	},
+	ErrOriginNotAllowed: {
+		Error:  "Request origin is not allowed for this project key",
+		Code:   ErrOriginNotAllowed,
+		Status: http.StatusForbidden,
+	},
 	// Common errors
 	ErrBadRequest: {
 		Error:  "Invalid request payload",
@@ -78,3 +129,12 @@ func NewAPIError(code ErrorCode) *APIError {
 func (e *APIError) StatusCode() int {
 	return e.Status
 }
+
+// WithMessage returns a copy of the error with a more specific message, keeping code and status.
+func (e *APIError) WithMessage(msg string) *APIError {
+	return &APIError{
+		Error:  msg,
+		Code:   e.Code,
+		Status: e.Status,
+	}
+}
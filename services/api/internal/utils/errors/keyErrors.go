@@ -0,0 +1,12 @@
+package errors
+
+// Project key authentication error codes
+const (
+	ErrInvalidProjectKey  ErrorCode = "invalid_project_key"
+	ErrProjectKeyDisabled ErrorCode = "project_key_disabled"
+	ErrProjectKeyExpired  ErrorCode = "project_key_expired"
+	ErrSignatureRequired  ErrorCode = "signature_required"
+	ErrInsufficientScope  ErrorCode = "insufficient_scope"
+	ErrIPNotAllowed       ErrorCode = "ip_not_allowed"
+	ErrOriginNotAllowed   ErrorCode = "origin_not_allowed"
+)
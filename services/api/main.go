@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"expvar"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -10,12 +13,16 @@ import (
 	"github.com/libpulse/platform/services/api/internal/auth"
 	"github.com/libpulse/platform/services/api/internal/config"
 	"github.com/libpulse/platform/services/api/internal/handlers"
+	"github.com/libpulse/platform/services/api/internal/jobs"
+	"github.com/libpulse/platform/services/api/internal/notify"
+	"github.com/libpulse/platform/services/api/internal/pseudonym"
+	"github.com/libpulse/platform/services/api/internal/secretscan"
 	"github.com/libpulse/platform/services/api/internal/supabase"
 	"github.com/libpulse/platform/services/api/internal/utils/crypto"
 )
 
 type Config struct {
-	JWTSecret      []byte
+	JWTSecret      []byte // legacy HMAC secret, optional when Supabase signs with asymmetric keys
 	ServiceRoleKey string
 	AuthBaseURL    string
 	ProjectURL     string
@@ -29,12 +36,17 @@ func loadConfigFromEnv() (*Config, error) {
 	projectURL := os.Getenv("SUPABASE_PROJECT_URL")
 	secretPepper := os.Getenv("LIBPULSE_SECRET_PEPPER")
 
-	if jwtSecret == "" || serviceRole == "" || authURL == "" || projectURL == "" || secretPepper == "" {
+	if serviceRole == "" || authURL == "" || projectURL == "" || secretPepper == "" {
 		return nil, ErrMissingEnv
 	}
 
+	var jwtSecretBytes []byte
+	if jwtSecret != "" {
+		jwtSecretBytes = []byte(jwtSecret)
+	}
+
 	return &Config{
-		JWTSecret:      []byte(jwtSecret),
+		JWTSecret:      jwtSecretBytes,
 		ServiceRoleKey: serviceRole,
 		AuthBaseURL:    authURL,
 		ProjectURL:     projectURL,
@@ -42,7 +54,7 @@ func loadConfigFromEnv() (*Config, error) {
 	}, nil
 }
 
-var ErrMissingEnv = &configError{"SUPABASE_JWT_SECRET, SUPABASE_SERVICE_ROLE_KEY, SUPABASE_AUTH_URL, SUPABASE_PROJECT_URL, LIBPULSE_SECRET_PEPPER must be set"}
+var ErrMissingEnv = &configError{"SUPABASE_SERVICE_ROLE_KEY, SUPABASE_AUTH_URL, SUPABASE_PROJECT_URL, LIBPULSE_SECRET_PEPPER must be set"}
 
 type configError struct{ msg string }
 
@@ -60,10 +72,25 @@ func main() {
 	// Supabase Admin API client
 	restURL := cfg.ProjectURL + "/rest/v1"
 	sbClient := supabase.NewClient(cfg.AuthBaseURL, restURL, cfg.ServiceRoleKey)
+	storage := supabase.NewStorage(cfg.ProjectURL+"/storage/v1", cfg.ServiceRoleKey)
+
+	// Supabase Auth JWT signing keys, refreshed in the background
+	jwks := auth.NewJWKS(config.GetJWKSURL(cfg.AuthBaseURL))
+	go jwks.Run(context.Background(), config.GetJWKSRefreshInterval())
+	if cfg.JWTSecret != nil {
+		log.Printf("SUPABASE_JWT_SECRET set: HMAC-signed session tokens are still accepted")
+	}
 
 	// Gin router
 	r := gin.Default()
 
+	// Only honor X-Forwarded-For from configured proxies when resolving client IPs
+	trustedProxies := config.GetTrustedProxies()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("config error: invalid LIBPULSE_TRUSTED_PROXIES: %v", err)
+	}
+	log.Printf("Trusted proxies: %v", trustedProxies)
+
 	// Get CORS origins from environment
 	corsOrigins := config.GetCORSOrigins()
 	log.Printf("CORS allowed origins: %v", corsOrigins)
@@ -72,7 +99,7 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     corsOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", auth.HeaderProjectKey, auth.HeaderProjectSecret},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600,
@@ -83,19 +110,218 @@ func main() {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Service counters (expvar), off by default because they list project IDs
+	if config.GetExposeMetrics() {
+		r.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
+
 	// Protected API routes
 	api := r.Group("/api/v1")
 	// Create Adapter Stores
 	userStore := &supabase.UserStore{Client: sbClient}
 	projectStore := &supabase.ProjectStore{Client: sbClient}
 	projectKeyStore := &supabase.ProjectKeyStore{Client: sbClient}
+	eventStore := &supabase.EventStore{Client: sbClient}
+	auditLogStore := &supabase.AuditLogStore{Client: sbClient}
+	projectTokenStore := &supabase.ProjectTokenStore{Client: sbClient}
+	memberStore := &supabase.MemberStore{Client: sbClient}
+	invitationStore := &supabase.InvitationStore{Client: sbClient}
+	transferStore := &supabase.OwnershipTransferStore{Client: sbClient}
+	consentStore := &supabase.ConsentStore{Client: sbClient}
+	erasureStore := &supabase.ErasureStore{Client: sbClient}
+	subjectExportStore := &supabase.SubjectExportStore{Client: sbClient}
+	pseudonymizer := pseudonym.New(&supabase.ProjectSaltStore{Client: sbClient})
+
+	// Email delivery; without SMTP_HOST notifications are only logged, without their links
+	var notifier notify.Notifier = notify.LogNotifier{}
+	if sender := config.GetMailSender(); sender != nil {
+		notifier = notify.MailNotifier{Sender: sender}
+	}
+	invitations := handlers.InvitationHandlerConfig{
+		ProjectStore:    projectStore,
+		MemberStore:     memberStore,
+		InvitationStore: invitationStore,
+		Notifier:        notifier,
+		AcceptURL:       config.GetInvitationAcceptURL(),
+		TTL:             config.GetInvitationTTL(),
+	}
+	transfers := handlers.OwnershipTransferHandlerConfig{
+		ProjectStore:  projectStore,
+		MemberStore:   memberStore,
+		TransferStore: transferStore,
+		AuditStore:    auditLogStore,
+	}
+	archives := handlers.ProjectArchiveHandlerConfig{
+		ProjectStore:   projectStore,
+		MemberStore:    memberStore,
+		KeyStore:       projectKeyStore,
+		ConsentStore:   consentStore,
+		EventStore:     eventStore,
+		UserStore:      userStore,
+		AuditStore:     auditLogStore,
+		MaxImportBytes: config.GetImportMaxBytes(),
+	}
+	erasures := handlers.ErasureHandlerConfig{
+		ProjectStore: projectStore,
+		MemberStore:  memberStore,
+		ErasureStore: erasureStore,
+		AuditStore:   auditLogStore,
+		UserIDs:      pseudonymizer,
+	}
+	subjectExports := handlers.SubjectExportHandlerConfig{
+		ProjectStore: projectStore,
+		MemberStore:  memberStore,
+		ExportStore:  subjectExportStore,
+		Objects:      storage,
+		AuditStore:   auditLogStore,
+		UserIDs:      pseudonymizer,
+		URLTTL:       config.GetSubjectExportURLTTL(),
+	}
 
-	api.Use(auth.NewMiddleware(cfg.JWTSecret))
+	// Routes that accept project access tokens declare the scope they need with RequireTokenScope;
+	// every other route rejects PATs.
+	api.Use(auth.NewMiddleware(auth.MiddlewareConfig{
+		JWTSecret: cfg.JWTSecret,
+		JWKS:      jwks,
+		Tokens:    projectTokenStore,
+		Issuer:    config.GetJWTIssuer(cfg.AuthBaseURL),
+		Audience:  config.GetJWTAudience(),
+		Leeway:    config.GetJWTLeeway(),
+	}))
 	{
 		api.GET("/me", handlers.GetCurrentUserHandler(userStore))
+		api.GET("/projects", handlers.ListProjectsHandler(projectStore))
 		api.POST("/projects", handlers.CreateProjectHandler(projectStore))
-		api.POST("/projects/:id/keys", handlers.CreateProjectKeyHandler(projectStore, projectKeyStore))
+		api.GET("/projects/:id", auth.RequireTokenScope(auth.TokenScopeRead), handlers.GetProjectHandler(projectStore, memberStore))
+		api.PATCH("/projects/:id", auth.RequireTokenScope(auth.TokenScopeProjectWrite), handlers.UpdateProjectHandler(projectStore, memberStore))
+		api.DELETE("/projects/:id", handlers.DeleteProjectHandler(projectStore, memberStore, config.GetProjectDeletionGracePeriod()))
+		api.POST("/projects/:id/restore", handlers.RestoreProjectHandler(projectStore))
+		api.POST("/projects/:id/keys", auth.RequireTokenScope(auth.TokenScopeKeysWrite), handlers.CreateProjectKeyHandler(projectStore, memberStore, projectKeyStore))
+		api.GET("/projects/:id/events", auth.RequireTokenScope(auth.TokenScopeRead), handlers.SearchEventsHandler(projectStore, memberStore, eventStore))
+		api.GET("/projects/:id/events/timeseries", auth.RequireTokenScope(auth.TokenScopeRead), handlers.EventTimeseriesHandler(projectStore, memberStore, eventStore))
+		api.GET("/projects/:id/keys", auth.RequireTokenScope(auth.TokenScopeRead), handlers.ListProjectKeysHandler(projectStore, memberStore, projectKeyStore, config.GetKeyExpiryWarningWindow()))
+		api.GET("/projects/:id/members", auth.RequireTokenScope(auth.TokenScopeRead), handlers.ListProjectMembersHandler(projectStore, memberStore))
+		api.POST("/projects/:id/members", handlers.AddProjectMemberHandler(projectStore, memberStore, userStore))
+		api.PATCH("/projects/:id/members/:userId", handlers.UpdateProjectMemberHandler(projectStore, memberStore))
+		api.DELETE("/projects/:id/members/:userId", handlers.RemoveProjectMemberHandler(projectStore, memberStore))
+		api.POST("/projects/:id/invitations", handlers.CreateInvitationHandler(invitations))
+		api.GET("/projects/:id/invitations", handlers.ListInvitationsHandler(invitations))
+		api.DELETE("/projects/:id/invitations/:invitationId", handlers.RevokeInvitationHandler(invitations))
+		api.POST("/projects/:id/ownership-transfer", handlers.CreateOwnershipTransferHandler(transfers))
+		api.GET("/projects/:id/ownership-transfer", handlers.GetOwnershipTransferHandler(transfers))
+		api.DELETE("/projects/:id/ownership-transfer", handlers.CancelOwnershipTransferHandler(transfers))
+		api.POST("/projects/:id/ownership-transfer/accept", handlers.AcceptOwnershipTransferHandler(transfers))
+		api.POST("/invitations/accept", handlers.AcceptInvitationHandler(invitations))
+		api.POST("/invitations/decline", handlers.DeclineInvitationHandler(invitations))
+		api.POST("/projects/:id/tokens", handlers.CreateProjectTokenHandler(projectStore, memberStore, projectTokenStore))
+		api.GET("/projects/:id/tokens", handlers.ListProjectTokensHandler(projectStore, memberStore, projectTokenStore))
+		api.DELETE("/projects/:id/tokens/:tokenId", handlers.RevokeProjectTokenHandler(projectStore, memberStore, projectTokenStore))
+		api.GET("/projects/:id/export", handlers.ExportProjectHandler(archives))
+		api.POST("/projects/:id/import", handlers.ImportProjectHandler(archives))
+		api.POST("/projects/:id/erasures", handlers.CreateErasureRequestHandler(erasures))
+		api.GET("/projects/:id/erasures/:erasureId", handlers.GetErasureRequestHandler(erasures))
+		api.POST("/projects/:id/subject-exports", handlers.CreateSubjectExportHandler(subjectExports))
+		api.GET("/projects/:id/subject-exports/:exportId", handlers.GetSubjectExportHandler(subjectExports))
+	}
+
+	// SDK ingestion routes, authenticated with project keys
+	ingest := r.Group("/ingest/v1")
+	ingest.Use(auth.NewProjectKeyMiddleware(projectKeyStore, auditLogStore))
+	{
+		ingest.POST("/events", auth.RequireKeyScope(auth.ScopeIngest), handlers.IngestEventsHandler(eventStore, pseudonymizer))
+	}
+
+	// Leaked-credential reports (GitHub secret scanning partner format), authenticated by signature
+	r.POST("/webhooks/secret-scanning", handlers.SecretScanningHandler(handlers.SecretScanningHandlerConfig{
+		Verifier:     secretscan.NewVerifier(config.GetSecretScanningKeysURL()),
+		KeyStore:     projectKeyStore,
+		ProjectStore: projectStore,
+		UserStore:    userStore,
+		AuditStore:   auditLogStore,
+		Notifier:     notifier,
+	}))
+
+	// Background jobs, run by whichever replica holds each job's lease
+	keyExpirySweeper := &jobs.KeyExpirySweeper{Store: projectKeyStore}
+	projectPurger := &jobs.ProjectPurger{
+		Projects:  projectStore,
+		Events:    eventStore,
+		Objects:   storage,
+		BatchSize: config.GetRetentionBatchSize(),
+	}
+	retentionWorker := &jobs.RetentionWorker{
+		Projects:    projectStore,
+		Events:      eventStore,
+		Audit:       auditLogStore,
+		DefaultDays: config.GetRetentionDefaultDays(),
+		MaxDays:     config.GetRetentionMaxDays(),
+		BatchSize:   config.GetRetentionBatchSize(),
+	}
+
+	jitter := config.GetJobJitter()
+	newJob := func(name string, interval, timeout time.Duration, run func(ctx context.Context) error) jobs.Job {
+		spec := config.GetJobSchedule(name, interval)
+		schedule, err := jobs.ParseSchedule(spec)
+		if err != nil {
+			log.Fatalf("job %s: %v", name, err)
+		}
+		return jobs.Job{
+			Name:     name,
+			Schedule: schedule,
+			Timeout:  config.GetJobTimeout(name, timeout),
+			Jitter:   jitter,
+			Run:      run,
+		}
+	}
+
+	erasureWorker := &jobs.ErasureWorker{
+		Store:     erasureStore,
+		Audit:     auditLogStore,
+		BatchSize: config.GetRetentionBatchSize(),
+	}
+	subjectExportWorker := &jobs.SubjectExportWorker{
+		Exports:   subjectExportStore,
+		Events:    eventStore,
+		Consents:  consentStore,
+		Objects:   storage,
+		Audit:     auditLogStore,
+		BatchSize: 1000,
+		TTL:       config.GetSubjectExportTTL(),
+	}
+
+	scheduledJobs := []jobs.Job{
+		newJob("key-expiry-sweep", config.GetKeyExpirySweepInterval(), 2*time.Minute, keyExpirySweeper.SweepOnce),
+		newJob("project-purge", config.GetProjectPurgeInterval(), 30*time.Minute, projectPurger.PurgeOnce),
+		newJob("retention", config.GetRetentionInterval(), 45*time.Minute, retentionWorker.RunOnce),
+		newJob("erasure", config.GetErasureInterval(), 30*time.Minute, erasureWorker.ProcessOnce),
+		newJob("subject-export", config.GetSubjectExportInterval(), 30*time.Minute, subjectExportWorker.ProcessOnce),
+	}
+
+	// Public demo project with synthetic data, refreshed nightly so its timestamps stay recent
+	if demoOwner := config.GetDemoOwnerUserID(); demoOwner != "" {
+		demoSeeder := &jobs.DemoSeeder{
+			Projects:       projectStore,
+			Events:         eventStore,
+			OwnerUserID:    demoOwner,
+			ProjectName:    config.GetDemoProjectName(),
+			Days:           config.GetDemoDays(),
+			Users:          40,
+			SessionsPerDay: config.GetDemoSessionsPerDay(),
+			BatchSize:      500,
+		}
+		demoJob := newJob("demo-seed", 24*time.Hour, 10*time.Minute, demoSeeder.SeedOnce)
+		demoJob.RunAtStart = true
+		scheduledJobs = append(scheduledJobs, demoJob)
+	}
+
+	jobStore := &supabase.JobStore{Client: sbClient}
+	scheduler := &jobs.Scheduler{
+		Leases:  jobStore,
+		History: jobStore,
+		Holder:  jobs.NewHolderID(),
+		Jobs:    scheduledJobs,
 	}
+	go scheduler.Run(context.Background())
 
 	addr := ":8080"
 	log.Printf("LibPulse API listening on %s", addr)
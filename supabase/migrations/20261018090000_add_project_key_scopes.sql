@@ -0,0 +1,13 @@
+-- Persist the scopes granted to each project key
+-- Existing keys were issued with the implicit default scope, so backfill them with 'ingest'
+
+ALTER TABLE "public"."project_keys"
+    ADD COLUMN IF NOT EXISTS "scopes" "text"[] DEFAULT ARRAY['ingest'::"text"] NOT NULL;
+
+ALTER TABLE "public"."project_keys"
+    ADD CONSTRAINT "project_keys_scopes_check" CHECK (
+        ("cardinality"("scopes") >= 1)
+        AND ("scopes" <@ ARRAY['ingest'::"text", 'consent:write'::"text", 'read:stats'::"text"])
+    );
+
+COMMENT ON COLUMN "public"."project_keys"."scopes" IS 'Scopes granted to the key, enforced by the API on every key-authenticated route';
@@ -0,0 +1,11 @@
+-- Stamp the environment of the ingesting project key onto every event
+-- so dashboards can separate staging and dev traffic from production usage.
+-- Events ingested before this migration keep a NULL env.
+
+ALTER TABLE "public"."events"
+    ADD COLUMN IF NOT EXISTS "env" "text";
+
+ALTER TABLE "public"."events"
+    ADD CONSTRAINT "events_env_check" CHECK (("env" = ANY (ARRAY['prod'::"text", 'staging'::"text", 'dev'::"text"])));
+
+CREATE INDEX IF NOT EXISTS "idx_events_project_id_env_event_ts" ON "public"."events" USING "btree" ("project_id", "env", "event_ts" DESC);
@@ -0,0 +1,9 @@
+-- Optional expiry for project keys (e.g. short-lived CI keys)
+-- Ingestion rejects keys past expires_at, and the API's background sweeper marks them disabled.
+
+ALTER TABLE "public"."project_keys"
+    ADD COLUMN IF NOT EXISTS "expires_at" timestamp with time zone;
+
+-- Partial index used by the expiry sweeper to find keys that still need disabling
+CREATE INDEX IF NOT EXISTS "idx_project_keys_expires_at_active" ON "public"."project_keys" USING "btree" ("expires_at")
+    WHERE ("disabled" = false AND "expires_at" IS NOT NULL);
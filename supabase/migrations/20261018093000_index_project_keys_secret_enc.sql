@@ -0,0 +1,3 @@
+-- Leaked-credential reports look keys up by secret hash
+
+CREATE INDEX IF NOT EXISTS "idx_project_keys_secret_enc" ON "public"."project_keys" USING "btree" ("secret_enc");
@@ -0,0 +1,7 @@
+-- Optional network and origin allowlists per project key
+-- Empty arrays mean "no restriction". CIDRs restrict CI keys to known runner ranges,
+-- origins restrict browser-embedded keys to known sites.
+
+ALTER TABLE "public"."project_keys"
+    ADD COLUMN IF NOT EXISTS "allowed_cidrs" "cidr"[] DEFAULT '{}'::"cidr"[] NOT NULL,
+    ADD COLUMN IF NOT EXISTS "allowed_origins" "text"[] DEFAULT '{}'::"text"[] NOT NULL;
@@ -0,0 +1,3 @@
+-- Project access tokens are resolved by hash on every PAT-authenticated request
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_tokens_token_hash" ON "public"."project_tokens" USING "btree" ("token_hash");
@@ -0,0 +1,45 @@
+-- Every project keeps at least one owner in project_members
+-- Projects created before the API inserted the creator as owner get their owner row backfilled.
+
+INSERT INTO "public"."project_members" ("project_id", "user_id", "role")
+SELECT "p"."id", "p"."owner_user_id", 'owner'::"public"."member_role"
+FROM "public"."projects" "p"
+ON CONFLICT ("project_id", "user_id") DO UPDATE SET "role" = 'owner'::"public"."member_role";
+
+-- Reject demoting or removing the last owner. Cascades from a deleted project or user are allowed.
+CREATE OR REPLACE FUNCTION "public"."ensure_project_keeps_owner"() RETURNS "trigger"
+    LANGUAGE "plpgsql"
+    AS $$
+BEGIN
+    IF OLD.role <> 'owner' THEN
+        RETURN COALESCE(NEW, OLD);
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.role = 'owner' THEN
+        RETURN NEW;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM "public"."projects" WHERE "id" = OLD.project_id)
+       OR NOT EXISTS (SELECT 1 FROM "auth"."users" WHERE "id" = OLD.user_id) THEN
+        RETURN COALESCE(NEW, OLD);
+    END IF;
+
+    PERFORM 1 FROM "public"."project_members"
+    WHERE "project_id" = OLD.project_id AND "role" = 'owner'
+    FOR UPDATE;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM "public"."project_members"
+        WHERE "project_id" = OLD.project_id AND "role" = 'owner' AND "user_id" <> OLD.user_id
+    ) THEN
+        RAISE EXCEPTION 'project must keep at least one owner' USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN COALESCE(NEW, OLD);
+END;
+$$;
+
+ALTER FUNCTION "public"."ensure_project_keeps_owner"() OWNER TO "postgres";
+
+DROP TRIGGER IF EXISTS "project_members_keep_owner" ON "public"."project_members";
+CREATE TRIGGER "project_members_keep_owner"
+    BEFORE UPDATE OF "role" OR DELETE ON "public"."project_members"
+    FOR EACH ROW EXECUTE FUNCTION "public"."ensure_project_keeps_owner"();
@@ -0,0 +1,15 @@
+-- Resolve an auth user by email so members can be added by address
+-- Only the service role (the API) may call it; it would otherwise enumerate accounts.
+
+CREATE OR REPLACE FUNCTION "public"."get_user_id_by_email"("p_email" "text") RETURNS "uuid"
+    LANGUAGE "sql" STABLE SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+    SELECT "id" FROM "auth"."users" WHERE lower("email") = lower("p_email") LIMIT 1;
+$$;
+
+ALTER FUNCTION "public"."get_user_id_by_email"("p_email" "text") OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."get_user_id_by_email"("p_email" "text") FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."get_user_id_by_email"("p_email" "text") FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."get_user_id_by_email"("p_email" "text") TO "service_role";
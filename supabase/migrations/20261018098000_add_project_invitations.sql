@@ -0,0 +1,31 @@
+-- Email invitations to projects
+-- The token is only stored as a hash. An invitation is pending until it is accepted, declined
+-- or expires; accepted_by records which account redeemed it.
+
+CREATE TABLE IF NOT EXISTS "public"."project_invitations" (
+    "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
+    "project_id" "uuid" NOT NULL,
+    "email" "text" NOT NULL,
+    "role" "public"."member_role" NOT NULL,
+    "token_hash" "text" NOT NULL,
+    "invited_by" "uuid" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "expires_at" timestamp with time zone NOT NULL,
+    "accepted_at" timestamp with time zone,
+    "accepted_by" "uuid",
+    "declined_at" timestamp with time zone,
+    CONSTRAINT "project_invitations_pkey" PRIMARY KEY ("id"),
+    CONSTRAINT "project_invitations_project_id_fkey" FOREIGN KEY ("project_id") REFERENCES "public"."projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "project_invitations_invited_by_fkey" FOREIGN KEY ("invited_by") REFERENCES "auth"."users"("id") ON DELETE CASCADE,
+    CONSTRAINT "project_invitations_accepted_by_fkey" FOREIGN KEY ("accepted_by") REFERENCES "auth"."users"("id") ON DELETE SET NULL
+);
+
+ALTER TABLE "public"."project_invitations" OWNER TO "postgres";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_invitations_token_hash" ON "public"."project_invitations" USING "btree" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_project_invitations_project_id" ON "public"."project_invitations" USING "btree" ("project_id");
+
+-- Only the API (service role) reads and writes invitations
+ALTER TABLE "public"."project_invitations" ENABLE ROW LEVEL SECURITY;
+
+GRANT ALL ON TABLE "public"."project_invitations" TO "service_role";
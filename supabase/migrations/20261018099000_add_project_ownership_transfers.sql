@@ -0,0 +1,98 @@
+-- Two-step project ownership transfer
+-- The recorded owner proposes an admin member as the new owner; the transfer completes when that
+-- member accepts it. A project has at most one pending transfer.
+
+CREATE TABLE IF NOT EXISTS "public"."project_ownership_transfers" (
+    "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
+    "project_id" "uuid" NOT NULL,
+    "from_user_id" "uuid" NOT NULL,
+    "to_user_id" "uuid" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "expires_at" timestamp with time zone NOT NULL,
+    "accepted_at" timestamp with time zone,
+    "cancelled_at" timestamp with time zone,
+    CONSTRAINT "project_ownership_transfers_pkey" PRIMARY KEY ("id"),
+    CONSTRAINT "project_ownership_transfers_distinct_users" CHECK ("from_user_id" <> "to_user_id"),
+    CONSTRAINT "project_ownership_transfers_project_id_fkey" FOREIGN KEY ("project_id") REFERENCES "public"."projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "project_ownership_transfers_from_user_id_fkey" FOREIGN KEY ("from_user_id") REFERENCES "auth"."users"("id") ON DELETE CASCADE,
+    CONSTRAINT "project_ownership_transfers_to_user_id_fkey" FOREIGN KEY ("to_user_id") REFERENCES "auth"."users"("id") ON DELETE CASCADE
+);
+
+ALTER TABLE "public"."project_ownership_transfers" OWNER TO "postgres";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_ownership_transfers_pending"
+    ON "public"."project_ownership_transfers" USING "btree" ("project_id")
+    WHERE "accepted_at" IS NULL AND "cancelled_at" IS NULL;
+
+-- Only the API (service role) reads and writes transfers
+ALTER TABLE "public"."project_ownership_transfers" ENABLE ROW LEVEL SECURITY;
+
+GRANT ALL ON TABLE "public"."project_ownership_transfers" TO "service_role";
+
+-- Audit entries for actions taken with a user session
+ALTER TABLE "public"."audit_logs" DROP CONSTRAINT IF EXISTS "audit_logs_auth_mode_check";
+ALTER TABLE "public"."audit_logs" ADD CONSTRAINT "audit_logs_auth_mode_check"
+    CHECK (("auth_mode" = ANY (ARRAY['PAT'::"text", 'HMAC'::"text", 'PK_ONLY'::"text", 'SYSTEM'::"text", 'JWT'::"text"])));
+
+-- Complete a pending transfer accepted by p_user_id in one transaction: the new owner becomes an
+-- owner member and the recorded owner, the previous owner stays on as admin, and the change is audited.
+CREATE OR REPLACE FUNCTION "public"."accept_project_ownership_transfer"("p_transfer_id" "uuid", "p_user_id" "uuid") RETURNS "public"."projects"
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_transfer "public"."project_ownership_transfers";
+    v_project "public"."projects";
+    v_role "public"."member_role";
+BEGIN
+    SELECT * INTO v_transfer FROM "public"."project_ownership_transfers"
+    WHERE "id" = p_transfer_id
+    FOR UPDATE;
+
+    IF NOT FOUND OR v_transfer.to_user_id <> p_user_id OR v_transfer.accepted_at IS NOT NULL
+       OR v_transfer.cancelled_at IS NOT NULL OR v_transfer.expires_at <= "now"() THEN
+        RAISE EXCEPTION 'ownership transfer not found' USING ERRCODE = 'no_data_found';
+    END IF;
+
+    SELECT * INTO v_project FROM "public"."projects"
+    WHERE "id" = v_transfer.project_id
+    FOR UPDATE;
+
+    IF v_project.owner_user_id <> v_transfer.from_user_id THEN
+        RAISE EXCEPTION 'ownership transfer is stale: project owner changed' USING ERRCODE = 'check_violation';
+    END IF;
+
+    SELECT "role" INTO v_role FROM "public"."project_members"
+    WHERE "project_id" = v_transfer.project_id AND "user_id" = v_transfer.to_user_id
+    FOR UPDATE;
+
+    IF v_role IS NULL OR v_role NOT IN ('admin', 'owner') THEN
+        RAISE EXCEPTION 'ownership transfer target must be an admin member' USING ERRCODE = 'check_violation';
+    END IF;
+
+    UPDATE "public"."project_members" SET "role" = 'owner'
+    WHERE "project_id" = v_transfer.project_id AND "user_id" = v_transfer.to_user_id;
+
+    UPDATE "public"."projects" SET "owner_user_id" = v_transfer.to_user_id, "updated_at" = "now"()
+    WHERE "id" = v_transfer.project_id
+    RETURNING * INTO v_project;
+
+    UPDATE "public"."project_members" SET "role" = 'admin'
+    WHERE "project_id" = v_transfer.project_id AND "user_id" = v_transfer.from_user_id;
+
+    UPDATE "public"."project_ownership_transfers" SET "accepted_at" = "now"()
+    WHERE "id" = v_transfer.id;
+
+    INSERT INTO "public"."audit_logs" ("project_id", "actor_type", "actor_id", "action", "success", "status_code", "auth_mode", "details")
+    VALUES (v_transfer.project_id, 'user', p_user_id::"text", 'project.ownership_transferred', true, 200, 'JWT',
+            "jsonb_build_object"('transfer_id', v_transfer.id, 'from_user_id', v_transfer.from_user_id, 'to_user_id', v_transfer.to_user_id));
+
+    RETURN v_project;
+END;
+$$;
+
+ALTER FUNCTION "public"."accept_project_ownership_transfer"("p_transfer_id" "uuid", "p_user_id" "uuid") OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."accept_project_ownership_transfer"("p_transfer_id" "uuid", "p_user_id" "uuid") FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."accept_project_ownership_transfer"("p_transfer_id" "uuid", "p_user_id" "uuid") FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."accept_project_ownership_transfer"("p_transfer_id" "uuid", "p_user_id" "uuid") TO "service_role";
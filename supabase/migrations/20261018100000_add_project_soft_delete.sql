@@ -0,0 +1,94 @@
+-- Soft delete for projects
+-- A deleted project keeps its data until purge_after, when the purge job removes it (and, through
+-- ON DELETE CASCADE, its events, keys, tokens, members and consent records). Until then the owner
+-- can restore it.
+
+ALTER TABLE "public"."projects"
+    ADD COLUMN IF NOT EXISTS "deleted_at" timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS "purge_after" timestamp with time zone;
+
+ALTER TABLE "public"."projects" DROP CONSTRAINT IF EXISTS "projects_purge_after_check";
+ALTER TABLE "public"."projects" ADD CONSTRAINT "projects_purge_after_check"
+    CHECK ((("deleted_at" IS NULL) = ("purge_after" IS NULL)));
+
+CREATE INDEX IF NOT EXISTS "idx_projects_purge_after" ON "public"."projects" USING "btree" ("purge_after")
+    WHERE "deleted_at" IS NOT NULL;
+
+-- Keys and tokens switched off by a deletion, so a restore re-enables exactly those
+ALTER TABLE "public"."project_keys"
+    ADD COLUMN IF NOT EXISTS "suspended_by_deletion" boolean DEFAULT false NOT NULL;
+ALTER TABLE "public"."project_tokens"
+    ADD COLUMN IF NOT EXISTS "suspended_by_deletion" boolean DEFAULT false NOT NULL;
+
+-- Mark a project deleted and switch off its credentials, pending transfers and invitations in one transaction
+CREATE OR REPLACE FUNCTION "public"."soft_delete_project"("p_project_id" "uuid", "p_purge_after" timestamp with time zone) RETURNS "public"."projects"
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_project "public"."projects";
+BEGIN
+    UPDATE "public"."projects"
+    SET "deleted_at" = "now"(), "purge_after" = p_purge_after, "updated_at" = "now"()
+    WHERE "id" = p_project_id AND "deleted_at" IS NULL
+    RETURNING * INTO v_project;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'project not found' USING ERRCODE = 'no_data_found';
+    END IF;
+
+    UPDATE "public"."project_keys" SET "disabled" = true, "suspended_by_deletion" = true
+    WHERE "project_id" = p_project_id AND "disabled" = false;
+
+    UPDATE "public"."project_tokens" SET "revoked" = true, "suspended_by_deletion" = true
+    WHERE "project_id" = p_project_id AND "revoked" = false;
+
+    UPDATE "public"."project_ownership_transfers" SET "cancelled_at" = "now"()
+    WHERE "project_id" = p_project_id AND "accepted_at" IS NULL AND "cancelled_at" IS NULL;
+
+    DELETE FROM "public"."project_invitations"
+    WHERE "project_id" = p_project_id AND "accepted_at" IS NULL AND "declined_at" IS NULL;
+
+    RETURN v_project;
+END;
+$$;
+
+-- Undo a deletion within its grace period. Keys that expired in the meantime stay disabled.
+CREATE OR REPLACE FUNCTION "public"."restore_project"("p_project_id" "uuid") RETURNS "public"."projects"
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_project "public"."projects";
+BEGIN
+    UPDATE "public"."projects"
+    SET "deleted_at" = NULL, "purge_after" = NULL, "updated_at" = "now"()
+    WHERE "id" = p_project_id AND "deleted_at" IS NOT NULL AND "purge_after" > "now"()
+    RETURNING * INTO v_project;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'project not found' USING ERRCODE = 'no_data_found';
+    END IF;
+
+    UPDATE "public"."project_keys"
+    SET "disabled" = ("expires_at" IS NOT NULL AND "expires_at" <= "now"()), "suspended_by_deletion" = false
+    WHERE "project_id" = p_project_id AND "suspended_by_deletion";
+
+    UPDATE "public"."project_tokens"
+    SET "revoked" = false, "suspended_by_deletion" = false
+    WHERE "project_id" = p_project_id AND "suspended_by_deletion";
+
+    RETURN v_project;
+END;
+$$;
+
+ALTER FUNCTION "public"."soft_delete_project"("p_project_id" "uuid", "p_purge_after" timestamp with time zone) OWNER TO "postgres";
+ALTER FUNCTION "public"."restore_project"("p_project_id" "uuid") OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."soft_delete_project"("p_project_id" "uuid", "p_purge_after" timestamp with time zone) FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."soft_delete_project"("p_project_id" "uuid", "p_purge_after" timestamp with time zone) FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."soft_delete_project"("p_project_id" "uuid", "p_purge_after" timestamp with time zone) TO "service_role";
+
+REVOKE ALL ON FUNCTION "public"."restore_project"("p_project_id" "uuid") FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."restore_project"("p_project_id" "uuid") FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."restore_project"("p_project_id" "uuid") TO "service_role";
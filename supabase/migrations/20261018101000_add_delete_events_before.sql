@@ -0,0 +1,29 @@
+-- Retention: delete a project's events ingested before a cutoff, at most p_limit rows per call,
+-- so the retention job removes old data in short transactions instead of one long lock.
+-- Returns the number of rows deleted; the caller repeats until it gets fewer than p_limit.
+
+CREATE OR REPLACE FUNCTION "public"."delete_events_before"("p_project_id" "uuid", "p_before" timestamp with time zone, "p_limit" integer) RETURNS integer
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_deleted integer;
+BEGIN
+    DELETE FROM "public"."events"
+    WHERE "project_id" = p_project_id
+      AND "event_id" IN (
+        SELECT "event_id" FROM "public"."events"
+        WHERE "project_id" = p_project_id AND "ingested_at" < p_before
+        LIMIT p_limit
+      );
+
+    GET DIAGNOSTICS v_deleted = ROW_COUNT;
+    RETURN v_deleted;
+END;
+$$;
+
+ALTER FUNCTION "public"."delete_events_before"("p_project_id" "uuid", "p_before" timestamp with time zone, "p_limit" integer) OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."delete_events_before"("p_project_id" "uuid", "p_before" timestamp with time zone, "p_limit" integer) FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."delete_events_before"("p_project_id" "uuid", "p_before" timestamp with time zone, "p_limit" integer) FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."delete_events_before"("p_project_id" "uuid", "p_before" timestamp with time zone, "p_limit" integer) TO "service_role";
@@ -0,0 +1,60 @@
+-- Background job coordination between API replicas
+-- The API reaches Postgres through PostgREST, where every call is its own transaction, so session
+-- advisory locks cannot be held for the length of a job. Instead a replica takes a time-limited
+-- lease per job before running it; a lease left by a crashed replica expires on its own.
+
+CREATE TABLE IF NOT EXISTS "public"."job_leases" (
+    "job_name" "text" NOT NULL,
+    "holder" "text" NOT NULL,
+    "acquired_at" timestamp with time zone NOT NULL,
+    "expires_at" timestamp with time zone NOT NULL,
+    CONSTRAINT "job_leases_pkey" PRIMARY KEY ("job_name")
+);
+
+ALTER TABLE "public"."job_leases" OWNER TO "postgres";
+
+-- Run history, one row per job run on the replica that held the lease
+CREATE TABLE IF NOT EXISTS "public"."job_runs" (
+    "id" bigint GENERATED ALWAYS AS IDENTITY NOT NULL,
+    "job_name" "text" NOT NULL,
+    "holder" "text" NOT NULL,
+    "started_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "finished_at" timestamp with time zone,
+    "success" boolean,
+    "error" "text",
+    CONSTRAINT "job_runs_pkey" PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."job_runs" OWNER TO "postgres";
+
+CREATE INDEX IF NOT EXISTS "idx_job_runs_job_name_started_at" ON "public"."job_runs" USING "btree" ("job_name", "started_at" DESC);
+
+-- Only the API (service role) reads and writes job state
+ALTER TABLE "public"."job_leases" ENABLE ROW LEVEL SECURITY;
+ALTER TABLE "public"."job_runs" ENABLE ROW LEVEL SECURITY;
+
+GRANT ALL ON TABLE "public"."job_leases" TO "service_role";
+GRANT ALL ON TABLE "public"."job_runs" TO "service_role";
+
+-- Take the lease on p_job_name for p_ttl_seconds if it is free, expired or already ours.
+-- Returns whether p_holder now holds it.
+CREATE OR REPLACE FUNCTION "public"."acquire_job_lease"("p_job_name" "text", "p_holder" "text", "p_ttl_seconds" integer) RETURNS boolean
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+BEGIN
+    INSERT INTO "public"."job_leases" ("job_name", "holder", "acquired_at", "expires_at")
+    VALUES (p_job_name, p_holder, "now"(), "now"() + "make_interval"(secs => p_ttl_seconds))
+    ON CONFLICT ("job_name") DO UPDATE
+        SET "holder" = EXCLUDED."holder", "acquired_at" = EXCLUDED."acquired_at", "expires_at" = EXCLUDED."expires_at"
+        WHERE "job_leases"."expires_at" <= "now"() OR "job_leases"."holder" = p_holder;
+
+    RETURN FOUND;
+END;
+$$;
+
+ALTER FUNCTION "public"."acquire_job_lease"("p_job_name" "text", "p_holder" "text", "p_ttl_seconds" integer) OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."acquire_job_lease"("p_job_name" "text", "p_holder" "text", "p_ttl_seconds" integer) FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."acquire_job_lease"("p_job_name" "text", "p_holder" "text", "p_ttl_seconds" integer) FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."acquire_job_lease"("p_job_name" "text", "p_holder" "text", "p_ttl_seconds" integer) TO "service_role";
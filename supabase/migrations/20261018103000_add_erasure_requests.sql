@@ -0,0 +1,90 @@
+-- Data subject erasure: GDPR deletion requests for one end user (user_id_h) of a project.
+-- Requests are processed asynchronously by the erasure job. The identifier is only kept while
+-- the request is open; afterwards the request is known by its peppered subject_hash alone.
+
+CREATE TABLE IF NOT EXISTS "public"."erasure_requests" (
+    "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
+    "project_id" "uuid" NOT NULL,
+    "user_id_h" "text",
+    "subject_hash" "text" NOT NULL,
+    "status" "text" DEFAULT 'pending'::"text" NOT NULL,
+    "requested_by" "uuid" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "started_at" timestamp with time zone,
+    "completed_at" timestamp with time zone,
+    "attempts" integer DEFAULT 0 NOT NULL,
+    "events_deleted" integer DEFAULT 0 NOT NULL,
+    "consents_deleted" integer DEFAULT 0 NOT NULL,
+    "consent_history_deleted" integer DEFAULT 0 NOT NULL,
+    "error" "text",
+    CONSTRAINT "erasure_requests_pkey" PRIMARY KEY ("id"),
+    CONSTRAINT "erasure_requests_project_id_fkey" FOREIGN KEY ("project_id") REFERENCES "public"."projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "erasure_requests_status_check" CHECK (("status" = ANY (ARRAY['pending'::"text", 'running'::"text", 'completed'::"text", 'failed'::"text"]))),
+    CONSTRAINT "erasure_requests_user_id_h_check" CHECK (("char_length"("user_id_h") <= 128)),
+    -- Finished requests must not keep the identifier
+    CONSTRAINT "erasure_requests_user_id_h_cleared" CHECK ((("status" = ANY (ARRAY['pending'::"text", 'running'::"text"])) OR ("user_id_h" IS NULL)))
+);
+
+ALTER TABLE "public"."erasure_requests" OWNER TO "postgres";
+
+CREATE INDEX IF NOT EXISTS "idx_erasure_requests_open" ON "public"."erasure_requests" USING "btree" ("created_at")
+    WHERE ("status" = ANY (ARRAY['pending'::"text", 'running'::"text"]));
+
+-- Only the API (service role) reads and writes erasure requests
+ALTER TABLE "public"."erasure_requests" ENABLE ROW LEVEL SECURITY;
+GRANT ALL ON TABLE "public"."erasure_requests" TO "service_role";
+
+-- Find one subject's events within a project without scanning other projects' rows
+CREATE INDEX IF NOT EXISTS "idx_events_project_id_user_id_h" ON "public"."events" USING "btree" ("project_id", "user_id_h");
+
+-- Delete at most p_limit events of one subject; the caller repeats until fewer come back
+CREATE OR REPLACE FUNCTION "public"."delete_subject_events"("p_project_id" "uuid", "p_user_id_h" "text", "p_limit" integer) RETURNS integer
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_deleted integer;
+BEGIN
+    DELETE FROM "public"."events"
+    WHERE "project_id" = p_project_id
+      AND "event_id" IN (
+        SELECT "event_id" FROM "public"."events"
+        WHERE "project_id" = p_project_id AND "user_id_h" = p_user_id_h
+        LIMIT p_limit
+      );
+
+    GET DIAGNOSTICS v_deleted = ROW_COUNT;
+    RETURN v_deleted;
+END;
+$$;
+
+ALTER FUNCTION "public"."delete_subject_events"("p_project_id" "uuid", "p_user_id_h" "text", "p_limit" integer) OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."delete_subject_events"("p_project_id" "uuid", "p_user_id_h" "text", "p_limit" integer) FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."delete_subject_events"("p_project_id" "uuid", "p_user_id_h" "text", "p_limit" integer) FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."delete_subject_events"("p_project_id" "uuid", "p_user_id_h" "text", "p_limit" integer) TO "service_role";
+
+-- Delete one subject's consent state and consent history together
+CREATE OR REPLACE FUNCTION "public"."delete_subject_consent"("p_project_id" "uuid", "p_user_id_h" "text") RETURNS "json"
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_consents integer;
+    v_history integer;
+BEGIN
+    DELETE FROM "public"."user_consent" WHERE "project_id" = p_project_id AND "user_id_h" = p_user_id_h;
+    GET DIAGNOSTICS v_consents = ROW_COUNT;
+
+    DELETE FROM "public"."user_consent_history" WHERE "project_id" = p_project_id AND "user_id_h" = p_user_id_h;
+    GET DIAGNOSTICS v_history = ROW_COUNT;
+
+    RETURN "json_build_object"('consents_deleted', v_consents, 'consent_history_deleted', v_history);
+END;
+$$;
+
+ALTER FUNCTION "public"."delete_subject_consent"("p_project_id" "uuid", "p_user_id_h" "text") OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."delete_subject_consent"("p_project_id" "uuid", "p_user_id_h" "text") FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."delete_subject_consent"("p_project_id" "uuid", "p_user_id_h" "text") FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."delete_subject_consent"("p_project_id" "uuid", "p_user_id_h" "text") TO "service_role";
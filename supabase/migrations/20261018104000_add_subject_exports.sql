@@ -0,0 +1,44 @@
+-- Data subject access: bundles of every event and consent record of one end user (user_id_h),
+-- built asynchronously by the subject export job and stored in a private Storage bucket. Clients
+-- download them through short-lived signed URLs; bundles are deleted when expires_at passes.
+
+INSERT INTO "storage"."buckets" ("id", "name", "public")
+VALUES ('subject-exports', 'subject-exports', false)
+ON CONFLICT ("id") DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS "public"."subject_exports" (
+    "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
+    "project_id" "uuid" NOT NULL,
+    "user_id_h" "text",
+    "subject_hash" "text" NOT NULL,
+    "status" "text" DEFAULT 'pending'::"text" NOT NULL,
+    "requested_by" "uuid" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "started_at" timestamp with time zone,
+    "completed_at" timestamp with time zone,
+    "expires_at" timestamp with time zone,
+    "attempts" integer DEFAULT 0 NOT NULL,
+    "object_path" "text",
+    "events_exported" integer DEFAULT 0 NOT NULL,
+    "consents_exported" integer DEFAULT 0 NOT NULL,
+    "consent_history_exported" integer DEFAULT 0 NOT NULL,
+    "error" "text",
+    CONSTRAINT "subject_exports_pkey" PRIMARY KEY ("id"),
+    CONSTRAINT "subject_exports_project_id_fkey" FOREIGN KEY ("project_id") REFERENCES "public"."projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "subject_exports_status_check" CHECK (("status" = ANY (ARRAY['pending'::"text", 'running'::"text", 'completed'::"text", 'failed'::"text", 'expired'::"text"]))),
+    CONSTRAINT "subject_exports_user_id_h_check" CHECK (("char_length"("user_id_h") <= 128)),
+    -- Finished exports must not keep the identifier
+    CONSTRAINT "subject_exports_user_id_h_cleared" CHECK ((("status" = ANY (ARRAY['pending'::"text", 'running'::"text"])) OR ("user_id_h" IS NULL)))
+);
+
+ALTER TABLE "public"."subject_exports" OWNER TO "postgres";
+
+CREATE INDEX IF NOT EXISTS "idx_subject_exports_open" ON "public"."subject_exports" USING "btree" ("created_at")
+    WHERE ("status" = ANY (ARRAY['pending'::"text", 'running'::"text"]));
+
+CREATE INDEX IF NOT EXISTS "idx_subject_exports_expires_at" ON "public"."subject_exports" USING "btree" ("expires_at")
+    WHERE ("status" = 'completed'::"text");
+
+-- Only the API (service role) reads and writes subject exports
+ALTER TABLE "public"."subject_exports" ENABLE ROW LEVEL SECURITY;
+GRANT ALL ON TABLE "public"."subject_exports" TO "service_role";
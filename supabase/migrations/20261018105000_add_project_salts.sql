@@ -0,0 +1,17 @@
+-- Per-project salts for server-side pseudonymization of raw end user identifiers at ingestion.
+-- Salts are encrypted by the API (AES-GCM under a key derived from LIBPULSE_SECRET_PEPPER) and
+-- bound to their project, so this table alone does not allow hashes to be recomputed.
+
+CREATE TABLE IF NOT EXISTS "public"."project_salts" (
+    "project_id" "uuid" NOT NULL,
+    "salt_sealed" "text" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    CONSTRAINT "project_salts_pkey" PRIMARY KEY ("project_id"),
+    CONSTRAINT "project_salts_project_id_fkey" FOREIGN KEY ("project_id") REFERENCES "public"."projects"("id") ON DELETE CASCADE
+);
+
+ALTER TABLE "public"."project_salts" OWNER TO "postgres";
+
+-- Only the API (service role) reads and writes salts
+ALTER TABLE "public"."project_salts" ENABLE ROW LEVEL SECURITY;
+GRANT ALL ON TABLE "public"."project_salts" TO "service_role";
@@ -0,0 +1,57 @@
+-- Time-series aggregation for dashboards: event counts and distinct user_id_h counts per time
+-- bucket, optionally split by one dimension. Buckets are truncated in the caller's time zone, so
+-- a "day" is a local calendar day. The API fills empty buckets.
+
+CREATE OR REPLACE FUNCTION "public"."event_timeseries"(
+    "p_project_id" "uuid",
+    "p_from" timestamp with time zone,
+    "p_to" timestamp with time zone,
+    "p_bucket" "text",
+    "p_timezone" "text",
+    "p_group_by" "text" DEFAULT NULL,
+    "p_event_type" "text" DEFAULT NULL,
+    "p_op" "text" DEFAULT NULL,
+    "p_version" "text" DEFAULT NULL
+) RETURNS TABLE("bucket_start" timestamp with time zone, "group_value" "text", "event_count" bigint, "user_count" bigint)
+    LANGUAGE "plpgsql" STABLE SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+BEGIN
+    IF p_bucket NOT IN ('minute', 'hour', 'day') THEN
+        RAISE EXCEPTION 'invalid bucket %', p_bucket USING ERRCODE = '22023';
+    END IF;
+    IF p_group_by IS NOT NULL AND p_group_by NOT IN ('op', 'version', 'event_type', 'surface', 'variant', 'sdk_language') THEN
+        RAISE EXCEPTION 'invalid group_by %', p_group_by USING ERRCODE = '22023';
+    END IF;
+
+    -- Served by idx_events_project_id_event_ts
+    RETURN QUERY
+    SELECT
+        "date_trunc"(p_bucket, e."event_ts" AT TIME ZONE p_timezone) AT TIME ZONE p_timezone,
+        CASE p_group_by
+            WHEN 'op' THEN e."op"
+            WHEN 'version' THEN e."version"
+            WHEN 'event_type' THEN e."event_type"::"text"
+            WHEN 'surface' THEN e."surface"
+            WHEN 'variant' THEN e."variant"
+            WHEN 'sdk_language' THEN e."sdk_language"
+        END,
+        "count"(*),
+        "count"(DISTINCT e."user_id_h")
+    FROM "public"."events" e
+    WHERE e."project_id" = p_project_id
+      AND e."event_ts" >= p_from
+      AND e."event_ts" < p_to
+      AND (p_event_type IS NULL OR e."event_type"::"text" = p_event_type)
+      AND (p_op IS NULL OR e."op" = p_op)
+      AND (p_version IS NULL OR e."version" = p_version)
+    GROUP BY 1, 2
+    ORDER BY 1, 2;
+END;
+$$;
+
+ALTER FUNCTION "public"."event_timeseries"("uuid", timestamp with time zone, timestamp with time zone, "text", "text", "text", "text", "text", "text") OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."event_timeseries"("uuid", timestamp with time zone, timestamp with time zone, "text", "text", "text", "text", "text", "text") FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."event_timeseries"("uuid", timestamp with time zone, timestamp with time zone, "text", "text", "text", "text", "text", "text") FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."event_timeseries"("uuid", timestamp with time zone, timestamp with time zone, "text", "text", "text", "text", "text", "text") TO "service_role";
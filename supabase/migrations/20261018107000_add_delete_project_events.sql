@@ -0,0 +1,30 @@
+-- Project purge: delete the events of a project at most p_limit rows per call, so purging a
+-- large project runs as many short transactions before its row (and the remaining, small
+-- dependent tables) is removed by ON DELETE CASCADE.
+-- Returns the number of rows deleted; the caller repeats until it gets fewer than p_limit.
+
+CREATE OR REPLACE FUNCTION "public"."delete_project_events"("p_project_id" "uuid", "p_limit" integer) RETURNS integer
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_deleted integer;
+BEGIN
+    DELETE FROM "public"."events"
+    WHERE "project_id" = p_project_id
+      AND "event_id" IN (
+        SELECT "event_id" FROM "public"."events"
+        WHERE "project_id" = p_project_id
+        LIMIT p_limit
+      );
+
+    GET DIAGNOSTICS v_deleted = ROW_COUNT;
+    RETURN v_deleted;
+END;
+$$;
+
+ALTER FUNCTION "public"."delete_project_events"("p_project_id" "uuid", "p_limit" integer) OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."delete_project_events"("p_project_id" "uuid", "p_limit" integer) FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."delete_project_events"("p_project_id" "uuid", "p_limit" integer) FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."delete_project_events"("p_project_id" "uuid", "p_limit" integer) TO "service_role";
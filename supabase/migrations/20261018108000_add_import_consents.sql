@@ -0,0 +1,30 @@
+-- Project import: copy consent state from an archive without overwriting newer state.
+-- A row is inserted when the user has no consent state in the project yet, and replaces the
+-- existing state only when the archive's updated_at is later, so a revocation recorded in the
+-- target after the export was taken is kept.
+-- Returns the number of rows inserted or replaced.
+
+CREATE OR REPLACE FUNCTION "public"."import_consents"("p_project_id" "uuid", "p_consents" "jsonb") RETURNS integer
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_applied integer;
+BEGIN
+    INSERT INTO "public"."user_consent" ("project_id", "user_id_h", "state", "updated_at")
+    SELECT p_project_id, c."user_id_h", c."state", c."updated_at"
+    FROM "jsonb_to_recordset"(p_consents) AS c("user_id_h" "text", "state" "text", "updated_at" timestamp with time zone)
+    ON CONFLICT ("project_id", "user_id_h") DO UPDATE
+        SET "state" = EXCLUDED."state", "updated_at" = EXCLUDED."updated_at"
+        WHERE "user_consent"."updated_at" < EXCLUDED."updated_at";
+
+    GET DIAGNOSTICS v_applied = ROW_COUNT;
+    RETURN v_applied;
+END;
+$$;
+
+ALTER FUNCTION "public"."import_consents"("p_project_id" "uuid", "p_consents" "jsonb") OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."import_consents"("p_project_id" "uuid", "p_consents" "jsonb") FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."import_consents"("p_project_id" "uuid", "p_consents" "jsonb") FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."import_consents"("p_project_id" "uuid", "p_consents" "jsonb") TO "service_role";
@@ -0,0 +1,23 @@
+-- Retention: age events by when they happened (event_ts) rather than when they were ingested,
+-- so backfilled or imported history is not kept past the project's retention. The subselect is
+-- served by idx_events_project_id_event_ts.
+
+CREATE OR REPLACE FUNCTION "public"."delete_events_before"("p_project_id" "uuid", "p_before" timestamp with time zone, "p_limit" integer) RETURNS integer
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_deleted integer;
+BEGIN
+    DELETE FROM "public"."events"
+    WHERE "project_id" = p_project_id
+      AND "event_id" IN (
+        SELECT "event_id" FROM "public"."events"
+        WHERE "project_id" = p_project_id AND "event_ts" < p_before
+        LIMIT p_limit
+      );
+
+    GET DIAGNOSTICS v_deleted = ROW_COUNT;
+    RETURN v_deleted;
+END;
+$$;
@@ -0,0 +1,30 @@
+-- Demo seeding: delete a project's events whose event_id does not start with p_keep_prefix, at
+-- most p_limit rows per call. Each seeding pass generates ids with its own prefix, so this removes
+-- the earlier passes' events however their event_ts is spread.
+-- Returns the number of rows deleted; the caller repeats until it gets fewer than p_limit.
+
+CREATE OR REPLACE FUNCTION "public"."delete_events_without_prefix"("p_project_id" "uuid", "p_keep_prefix" "text", "p_limit" integer) RETURNS integer
+    LANGUAGE "plpgsql" SECURITY DEFINER
+    SET "search_path" TO ''
+    AS $$
+DECLARE
+    v_deleted integer;
+BEGIN
+    DELETE FROM "public"."events"
+    WHERE "project_id" = p_project_id
+      AND "event_id" IN (
+        SELECT "event_id" FROM "public"."events"
+        WHERE "project_id" = p_project_id AND "left"("event_id", "length"(p_keep_prefix)) <> p_keep_prefix
+        LIMIT p_limit
+      );
+
+    GET DIAGNOSTICS v_deleted = ROW_COUNT;
+    RETURN v_deleted;
+END;
+$$;
+
+ALTER FUNCTION "public"."delete_events_without_prefix"("p_project_id" "uuid", "p_keep_prefix" "text", "p_limit" integer) OWNER TO "postgres";
+
+REVOKE ALL ON FUNCTION "public"."delete_events_without_prefix"("p_project_id" "uuid", "p_keep_prefix" "text", "p_limit" integer) FROM PUBLIC;
+REVOKE ALL ON FUNCTION "public"."delete_events_without_prefix"("p_project_id" "uuid", "p_keep_prefix" "text", "p_limit" integer) FROM "anon", "authenticated";
+GRANT EXECUTE ON FUNCTION "public"."delete_events_without_prefix"("p_project_id" "uuid", "p_keep_prefix" "text", "p_limit" integer) TO "service_role";